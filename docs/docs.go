@@ -15,46 +15,220 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/download/{docKey}/{docName}": {
+        "/api/v1/archive": {
+            "post": {
+                "description": "orchestrator to download many documents of s3 as one zip, by keys or by prefix",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "parameters": [
+                    {
+                        "description": "Body payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/document.RequestArchiveDocument"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/dto.ErrorValidation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/documents": {
             "get": {
-                "description": "orchestrator to get base64 to s3",
+                "description": "orchestrator to list documents recorded in the metadata database, ordered by key",
                 "produces": [
                     "application/json"
                 ],
                 "parameters": [
                     {
                         "type": "string",
-                        "default": "base64",
-                        "description": "type downloaded can be empty(file),downloaded and base64",
-                        "name": "type",
+                        "description": "bucket listed in ALLOWED_BUCKETS, BUCKET_NAME when empty",
+                        "name": "bucket",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "default": "folder-in-s3",
-                        "description": "document key",
-                        "name": "docKey",
-                        "in": "path",
-                        "required": true
+                        "default": "folder-in-s3/",
+                        "description": "key prefix",
+                        "name": "prefix",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "default": "example.png",
-                        "description": "document name",
-                        "name": "docName",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "include soft deleted documents",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "max documents, up to 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "documents to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseListDocument"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/dto.ErrorValidation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
                         "schema": {
                             "$ref": "#/definitions/dto.ApiResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/api/v1/documents/copy": {
+            "post": {
+                "description": "orchestrator to copy document to another key or bucket without downloading it",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "description": "Body payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/document.RequestCopyDocument"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseUploadDocument"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/dto.ErrorValidation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/dto.ApiResponse"
                         }
@@ -68,9 +242,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/upload/base64": {
+        "/api/v1/documents/move": {
             "post": {
-                "description": "orchestrator to upload base64 to s3",
+                "description": "orchestrator to move document to another key or bucket, the source is deleted after the copy",
                 "produces": [
                     "application/json"
                 ],
@@ -81,7 +255,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/document.RequestUploadDocumentBase64"
+                            "$ref": "#/definitions/document.RequestCopyDocument"
                         }
                     }
                 ],
@@ -122,6 +296,18 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -131,35 +317,96 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/upload/file": {
-            "post": {
-                "description": "orchestrator to upload base64 to s3",
+        "/api/v1/documents/search": {
+            "get": {
+                "description": "orchestrator to search documents recorded in the metadata database, every filter must match",
                 "produces": [
                     "application/json"
                 ],
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "file document",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "type": "string",
+                        "description": "bucket listed in ALLOWED_BUCKETS, BUCKET_NAME when empty",
+                        "name": "bucket",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "default": "folder-in-s3",
-                        "description": "key document",
-                        "name": "document_key",
-                        "in": "formData",
-                        "required": true
+                        "default": "invoice",
+                        "description": "case insensitive substring of the key",
+                        "name": "name",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "default": "example",
-                        "description": "name document",
-                        "name": "document_name",
-                        "in": "formData",
-                        "required": true
+                        "description": "exact content type, or image/* for every subtype",
+                        "name": "content_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "min size in bytes",
+                        "name": "min_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max size in bytes",
+                        "name": "max_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "2024-01-01T00:00:00Z",
+                        "description": "RFC 3339 date time, included",
+                        "name": "uploaded_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 date time, excluded",
+                        "name": "uploaded_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "tag on the document, repeat it to require several",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "name:value custom metadata, repeat it to require several",
+                        "name": "metadata",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "key, size or created_at, prefixed by - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "max documents, up to 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -174,7 +421,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/document.ResponseUploadDocument"
+                                            "$ref": "#/definitions/document.ResponseSearchDocument"
                                         }
                                     }
                                 }
@@ -204,39 +451,1729 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.ApiResponse"
                         }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "document.RequestUploadDocumentBase64": {
-            "type": "object",
-            "required": [
-                "document_base64",
-                "document_key",
-                "document_name"
-            ],
-            "properties": {
-                "document_base64": {
-                    "type": "string",
-                    "example": "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAIAAACQd1PeAAAAA3NCSVQICAjb4U/gAAAADElEQVQImWMwMTEBAAE8AJ1tV5pJAAAAAElFTkSuQmCC"
-                },
-                "document_key": {
-                    "type": "string",
-                    "example": "folder-in-s3"
+        },
+        "/api/v1/documents/sign": {
+            "post": {
+                "description": "orchestrator to issue an expiring signed link of document served by this service, optionally bound to the client ip or disposition",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "api key of DOWNLOAD_SIGNING_API_KEYS",
+                        "name": "X-Api-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/document.RequestSignDownload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseSignDownload"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/dto.ErrorValidation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/documents/{docKey}/{docName}": {
+            "get": {
+                "description": "orchestrator to get metadata of document in s3, pdf info is returned for pdf document",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "folder-in-s3",
+                        "description": "document key",
+                        "name": "docKey",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "example.pdf",
+                        "description": "document name",
+                        "name": "docName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseDocumentMetadata"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "orchestrator to delete document in s3",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "folder-in-s3",
+                        "description": "document key",
+                        "name": "docKey",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "example.png",
+                        "description": "document name",
+                        "name": "docName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/documents/{docKey}/{docName}/versions": {
+            "get": {
+                "description": "orchestrator to list versions of document in versioned bucket",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "folder-in-s3",
+                        "description": "document key",
+                        "name": "docKey",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "example.png",
+                        "description": "document name",
+                        "name": "docName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/document.ResponseDocumentVersion"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/documents/{docKey}/{docName}/versions/{versionId}/restore": {
+            "post": {
+                "description": "orchestrator to restore old version of document as the latest version",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "folder-in-s3",
+                        "description": "document key",
+                        "name": "docKey",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "example.png",
+                        "description": "document name",
+                        "name": "docName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "version to restore",
+                        "name": "versionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseUploadDocument"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/download/{docKey}/{docName}": {
+            "get": {
+                "description": "orchestrator to get base64 to s3",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "base64",
+                        "description": "type downloaded can be empty(file),downloaded and base64",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of the downloaded file, the uploaded filename by default",
+                        "name": "filename",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "rendition profile or preview served instead of the document",
+                        "name": "rendition",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "version of document, latest when empty",
+                        "name": "versionId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "image width in pixel, derived from height when empty",
+                        "name": "w",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "image height in pixel, derived from width when empty",
+                        "name": "h",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "contain",
+                        "description": "how the image fit the size: contain, cover or fill",
+                        "name": "fit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "image output format: jpeg, png or webp",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 80,
+                        "description": "jpeg quality between 1 and 100",
+                        "name": "quality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "folder-in-s3",
+                        "description": "document key",
+                        "name": "docKey",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "example.png",
+                        "description": "document name",
+                        "name": "docName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "single byte range, ex: bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "compressed document is sent as is when its encoding is accepted",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix time the signed link expires at",
+                        "name": "expires",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the secret of the signed link",
+                        "name": "kid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated values bound to the signed link: ip, type",
+                        "name": "bind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "signature of the signed link",
+                        "name": "sig",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "etag of the copy of the client, 304 when it is still current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/files/{id}": {
+            "get": {
+                "description": "orchestrator to get document by its opaque id, the key of the document is never exposed",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "opaque id of the document",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "base64",
+                        "description": "type downloaded can be empty(file),downloaded and base64",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of the downloaded file, the uploaded filename by default",
+                        "name": "filename",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "rendition profile or preview served instead of the document",
+                        "name": "rendition",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "version of document, latest when empty",
+                        "name": "versionId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "image width in pixel, derived from height when empty",
+                        "name": "w",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "image height in pixel, derived from width when empty",
+                        "name": "h",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "contain",
+                        "description": "how the image fit the size: contain, cover or fill",
+                        "name": "fit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "image output format: jpeg, png or webp",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 80,
+                        "description": "jpeg quality between 1 and 100",
+                        "name": "quality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "single byte range, ex: bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "compressed document is sent as is when its encoding is accepted",
+                        "name": "Accept-Encoding",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix time the signed link expires at",
+                        "name": "expires",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the secret of the signed link",
+                        "name": "kid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated values bound to the signed link: ip, type",
+                        "name": "bind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "signature of the signed link",
+                        "name": "sig",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "etag of the copy of the client, 304 when it is still current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "416": {
+                        "description": "Requested Range Not Satisfiable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/upload/archive": {
+            "post": {
+                "description": "orchestrator to extract zip to s3, every entry is stored as document_key/\u003centry path\u003e",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "zip archive",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "folder-in-s3",
+                        "description": "key document",
+                        "name": "document_key",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "overwrite policy when document exists: allow, reject or rename",
+                        "name": "overwrite",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseUploadArchive"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/dto.ErrorValidation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/upload/base64": {
+            "post": {
+                "description": "orchestrator to upload base64 to s3",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "description": "Body payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/document.RequestUploadDocumentBase64"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "expected SHA-256 of decoded document, base64 or hex",
+                        "name": "X-Checksum-Sha256",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expected CRC32C of decoded document, base64 or hex",
+                        "name": "X-Checksum-Crc32c",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseUploadDocument"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/dto.ErrorValidation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/upload/batch": {
+            "post": {
+                "description": "orchestrator to upload many files to s3 in one request, the result of every file is returned",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "file documents, the field can be repeated",
+                        "name": "files",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "folder-in-s3",
+                        "description": "key document shared by every file",
+                        "name": "document_key",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "name of every file in the same order, file name without extension when empty",
+                        "name": "document_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "overwrite policy when document exists: allow, reject or rename",
+                        "name": "overwrite",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "delete the uploaded documents when one file failed, existing document is never overwritten",
+                        "name": "atomic",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "searchable tag of every file, repeat it for several, custom metadata is sent as metadata[name] fields",
+                        "name": "tags",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "public or private for every file, private require a signed link to download",
+                        "name": "visibility",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "expected SHA-256 of every file in the same order, base64 or hex, empty is not checked",
+                        "name": "checksum_sha256",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "expected CRC32C of every file in the same order, base64 or hex, empty is not checked",
+                        "name": "checksum_crc32c",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseUploadBatch"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "207": {
+                        "description": "Multi-Status",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseUploadBatch"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/dto.ErrorValidation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/upload/file": {
+            "post": {
+                "description": "orchestrator to upload base64 to s3",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "file document",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "folder-in-s3",
+                        "description": "key document",
+                        "name": "document_key",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "example",
+                        "description": "name document",
+                        "name": "document_name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "overwrite policy when document exists: allow, reject or rename",
+                        "name": "overwrite",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "searchable tag, repeat it for several, custom metadata is sent as metadata[name] fields",
+                        "name": "tags",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "public or private, private require a signed link to download",
+                        "name": "visibility",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expected SHA-256 of document, base64 or hex",
+                        "name": "X-Checksum-Sha256",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expected CRC32C of document, base64 or hex",
+                        "name": "X-Checksum-Crc32c",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseUploadDocument"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/dto.ErrorValidation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/upload/url": {
+            "post": {
+                "description": "orchestrator to fetch document from url and upload it to s3",
+                "produces": [
+                    "application/json"
+                ],
+                "parameters": [
+                    {
+                        "description": "Body payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/document.RequestUploadDocumentUrl"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "expected SHA-256 of fetched document, base64 or hex",
+                        "name": "X-Checksum-Sha256",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expected CRC32C of fetched document, base64 or hex",
+                        "name": "X-Checksum-Crc32c",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/document.ResponseUploadDocument"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.ApiResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "error": {
+                                            "$ref": "#/definitions/dto.ErrorValidation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApiResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "document.RequestArchiveDocument": {
+            "type": "object",
+            "required": [
+                "keys"
+            ],
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "folder-in-s3/example.png"
+                    ]
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "folder-in-s3/"
+                }
+            }
+        },
+        "document.RequestCopyDocument": {
+            "type": "object",
+            "required": [
+                "destination_key",
+                "source_key"
+            ],
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "destination_bucket": {
+                    "type": "string"
+                },
+                "destination_key": {
+                    "type": "string",
+                    "example": "archive/example.png"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "metadata_directive": {
+                    "description": "MetadataDirective is copy (default) to keep metadata of source, or replace to use Metadata and ContentType",
+                    "type": "string",
+                    "enum": [
+                        "copy",
+                        "replace"
+                    ],
+                    "example": "copy"
+                },
+                "overwrite": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "reject",
+                        "rename"
+                    ],
+                    "example": "reject"
+                },
+                "source_bucket": {
+                    "description": "SourceBucket and DestinationBucket must be listed in ALLOWED_BUCKETS, BUCKET_NAME when empty",
+                    "type": "string"
+                },
+                "source_key": {
+                    "type": "string",
+                    "example": "folder-in-s3/example.png"
+                },
+                "source_version_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "document.RequestSignDownload": {
+            "type": "object",
+            "required": [
+                "key"
+            ],
+            "properties": {
+                "client_ip": {
+                    "description": "ClientIp bind the link to the client address",
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "disposition": {
+                    "description": "Disposition bind the link to inline, download or base64 response",
+                    "type": "string",
+                    "enum": [
+                        "inline",
+                        "download",
+                        "base64"
+                    ],
+                    "example": "download"
+                },
+                "expires_in": {
+                    "description": "ExpiresIn is the lifetime of the link in seconds, DOWNLOAD_LINK_EXPIRES when empty",
+                    "type": "integer",
+                    "maximum": 604800,
+                    "minimum": 1,
+                    "example": 900
+                },
+                "key": {
+                    "type": "string",
+                    "example": "folder-in-s3/example.png"
+                }
+            }
+        },
+        "document.RequestUploadDocumentBase64": {
+            "type": "object",
+            "required": [
+                "document_base64",
+                "document_key",
+                "document_name",
+                "metadata",
+                "tags"
+            ],
+            "properties": {
+                "checksum_crc32c": {
+                    "type": "string"
+                },
+                "checksum_sha256": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "description": "ContentType take precedence over the media type of the data uri, DocumentBase64 can also be the bare\npayload in the standard or url safe alphabet, padded or not. The content is sniffed when both are empty",
+                    "type": "string",
+                    "example": "image/png"
+                },
+                "document_base64": {
+                    "type": "string",
+                    "example": "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAIAAACQd1PeAAAAA3NCSVQICAjb4U/gAAAADElEQVQImWMwMTEBAAE8AJ1tV5pJAAAAAElFTkSuQmCC"
+                },
+                "document_key": {
+                    "type": "string",
+                    "example": "folder-in-s3"
+                },
+                "document_name": {
+                    "type": "string",
+                    "example": "example"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "overwrite": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "reject",
+                        "rename"
+                    ],
+                    "example": "allow"
+                },
+                "tags": {
+                    "description": "Tags and Metadata are searchable, stored as user metadata of the object",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "invoice"
+                    ]
+                },
+                "visibility": {
+                    "description": "Visibility private require a signed link to download, DEFAULT_VISIBILITY when empty",
+                    "type": "string",
+                    "enum": [
+                        "public",
+                        "private"
+                    ],
+                    "example": "private"
+                }
+            }
+        },
+        "document.RequestUploadDocumentUrl": {
+            "type": "object",
+            "required": [
+                "document_key",
+                "document_name",
+                "metadata",
+                "tags",
+                "url"
+            ],
+            "properties": {
+                "checksum_crc32c": {
+                    "type": "string"
+                },
+                "checksum_sha256": {
+                    "type": "string"
+                },
+                "document_key": {
+                    "type": "string",
+                    "example": "folder-in-s3"
                 },
                 "document_name": {
                     "type": "string",
                     "example": "example"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "overwrite": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "reject",
+                        "rename"
+                    ],
+                    "example": "allow"
+                },
+                "tags": {
+                    "description": "Tags and Metadata are searchable, stored as user metadata of the object",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "invoice"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/invoice.pdf"
+                },
+                "visibility": {
+                    "description": "Visibility private require a signed link to download, DEFAULT_VISIBILITY when empty",
+                    "type": "string",
+                    "enum": [
+                        "public",
+                        "private"
+                    ],
+                    "example": "private"
+                }
+            }
+        },
+        "document.ResponseBatchDocument": {
+            "type": "object",
+            "properties": {
+                "document": {
+                    "$ref": "#/definitions/document.ResponseUploadDocument"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "file": {
+                    "type": "string"
+                }
+            }
+        },
+        "document.ResponseDocumentMetadata": {
+            "type": "object",
+            "properties": {
+                "content_encoding": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "etag": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_modified": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "pdf": {
+                    "$ref": "#/definitions/document.ResponsePdfInfo"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "version_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "document.ResponseDocumentRecord": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "checksum_sha256": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "document_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "DocumentId is the opaque id of the document when OPAQUE_IDS is enabled",
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uploader": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "document.ResponseDocumentVersion": {
+            "type": "object",
+            "properties": {
+                "etag": {
+                    "type": "string"
+                },
+                "is_delete_marker": {
+                    "type": "boolean"
+                },
+                "is_latest": {
+                    "type": "boolean"
+                },
+                "last_modified": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "version_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "document.ResponseExtractedDocument": {
+            "type": "object",
+            "properties": {
+                "document_url": {
+                    "type": "string"
+                },
+                "entry": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                }
+            }
+        },
+        "document.ResponseListDocument": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/document.ResponseDocumentRecord"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "document.ResponsePdfInfo": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "encrypted": {
+                    "type": "boolean"
+                },
+                "page_count": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "document.ResponseSearchDocument": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/document.ResponseDocumentRecord"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor is empty on the last page",
+                    "type": "string"
+                }
+            }
+        },
+        "document.ResponseSignDownload": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "document.ResponseUploadArchive": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/document.ResponseExtractedDocument"
+                    }
+                }
+            }
+        },
+        "document.ResponseUploadBatch": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/document.ResponseBatchDocument"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rolled_back": {
+                    "type": "boolean"
+                },
+                "succeeded": {
+                    "type": "integer"
                 }
             }
         },
         "document.ResponseUploadDocument": {
             "type": "object",
             "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "checksum_crc32c": {
+                    "type": "string"
+                },
+                "checksum_sha256": {
+                    "type": "string"
+                },
+                "content_encoding": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
                 "document_url": {
                     "type": "string"
+                },
+                "etag": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Id is the opaque id of the document when OPAQUE_IDS is enabled, DocumentUrl then use it instead of the key",
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "public_url": {
+                    "description": "PublicUrl serve public document from PUBLIC_BASE_URL without going through this service",
+                    "type": "string"
+                },
+                "renditions": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "signed_url": {
+                    "type": "string"
+                },
+                "signed_url_expires_at": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "version_id": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "type": "string"
                 }
             }
         },
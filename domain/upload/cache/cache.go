@@ -0,0 +1,267 @@
+package cache
+
+import (
+	"aws-s3-bucket/domain/upload/interfaces"
+	"aws-s3-bucket/models/document"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Config bound the cache, a tier with a zero size is disabled
+type Config struct {
+	// TTL is how long an object is served without asking S3, it is revalidated after
+	TTL time.Duration
+
+	// MemorySize is the total bytes kept in memory, for objects up to MemoryMaxObject bytes
+	MemorySize      int64
+	MemoryMaxObject int64
+
+	// DiskDir hold objects over MemoryMaxObject and up to DiskMaxObject bytes, DiskSize bytes in total
+	DiskDir       string
+	DiskSize      int64
+	DiskMaxObject int64
+}
+
+type entry struct {
+	id          string
+	object      document.CachedObject
+	validatedAt time.Time
+	// data is the content of memory entry, path the file of disk entry
+	data []byte
+	path string
+}
+
+type cache struct {
+	config Config
+	now    func() time.Time
+
+	mu     sync.Mutex
+	memory *lru
+	disk   *lru
+}
+
+// cacheFile is the name of the files of the disk cache, the sha256 of the object then a random suffix
+var cacheFile = regexp.MustCompile(`^[0-9a-f]{64}-[0-9]+$`)
+
+// New return a cache of two LRU tiers, in memory for small objects and on local disk for larger ones.
+// files left in DiskDir by a previous process are removed as nothing index them anymore, other files are kept
+func New(config Config) (interfaces.CacheInterface, error) {
+	c := &cache{config: config, now: time.Now}
+	if config.MemorySize > 0 && config.MemoryMaxObject > 0 {
+		c.memory = newLru(config.MemorySize, nil)
+	}
+	if config.DiskDir != "" && config.DiskSize > 0 && config.DiskMaxObject > 0 {
+		if err := os.MkdirAll(config.DiskDir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create cache dir: %w", err)
+		}
+		files, err := os.ReadDir(config.DiskDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to clean cache dir: %w", err)
+		}
+		for _, file := range files {
+			if cacheFile.MatchString(file.Name()) {
+				os.Remove(filepath.Join(config.DiskDir, file.Name()))
+			}
+		}
+		c.disk = newLru(config.DiskSize, func(e *entry) {
+			os.Remove(e.path)
+		})
+	}
+	return c, nil
+}
+
+func cacheId(bucket, key string) string {
+	return bucket + "/" + key
+}
+
+func (c *cache) Admit(size int64) bool {
+	return c.tier(size) != nil
+}
+
+// tier is the LRU an object of the size belong to, nil when it is too large for both
+func (c *cache) tier(size int64) *lru {
+	switch {
+	case size < 0:
+		return nil
+	case c.memory != nil && size <= c.config.MemoryMaxObject && size <= c.config.MemorySize:
+		return c.memory
+	case c.disk != nil && size <= c.config.DiskMaxObject && size <= c.config.DiskSize:
+		return c.disk
+	}
+	return nil
+}
+
+func (c *cache) Get(bucket, key string) (document.CachedObject, io.ReadCloser, bool) {
+	id := cacheId(bucket, key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tier := range []*lru{c.memory, c.disk} {
+		if tier == nil {
+			continue
+		}
+		e := tier.get(id)
+		if e == nil {
+			continue
+		}
+
+		object := e.object
+		object.Stale = c.now().Sub(e.validatedAt) > c.config.TTL
+		if e.path == "" {
+			return object, io.NopCloser(bytes.NewReader(e.data)), true
+		}
+		// a removed file is still readable once opened, eviction does not break a running download
+		file, err := os.Open(e.path)
+		if err != nil {
+			tier.remove(id)
+			return document.CachedObject{}, nil, false
+		}
+		return object, file, true
+	}
+	return document.CachedObject{}, nil, false
+}
+
+func (c *cache) Set(bucket, key string, object document.CachedObject, content io.Reader) (io.ReadCloser, error) {
+	tier := c.tier(object.Size)
+	if tier == nil {
+		return nil, fmt.Errorf("object of %d bytes cannot be cached", object.Size)
+	}
+	id := cacheId(bucket, key)
+	e := &entry{id: id, object: object}
+
+	if tier == c.memory {
+		data, err := io.ReadAll(io.LimitReader(content, c.config.MemoryMaxObject+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > c.config.MemoryMaxObject {
+			return nil, fmt.Errorf("object is larger than %d bytes", c.config.MemoryMaxObject)
+		}
+		e.data, e.object.Size = data, int64(len(data))
+	} else {
+		path, size, err := c.writeFile(bucket, key, object.ETag, content)
+		if err != nil {
+			return nil, err
+		}
+		e.path, e.object.Size = path, size
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(id)
+	e.validatedAt = c.now()
+	tier.add(e)
+	if e.path == "" {
+		return io.NopCloser(bytes.NewReader(e.data)), nil
+	}
+	return os.Open(e.path)
+}
+
+// writeFile write the content in a file named after bucket, key and etag. every write get its own file,
+// an entry replaced by the same object must not remove the file of the new one
+func (c *cache) writeFile(bucket, key, etag string, content io.Reader) (path string, size int64, err error) {
+	sum := sha256.Sum256([]byte(bucket + "\x00" + key + "\x00" + etag))
+
+	file, err := os.CreateTemp(c.config.DiskDir, hex.EncodeToString(sum[:])+"-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create cache file: %w", err)
+	}
+	size, err = io.Copy(file, io.LimitReader(content, c.config.DiskMaxObject+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size > c.config.DiskMaxObject {
+		err = fmt.Errorf("object is larger than %d bytes", c.config.DiskMaxObject)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", 0, fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return file.Name(), size, nil
+}
+
+func (c *cache) Revalidated(bucket, key string) {
+	id := cacheId(bucket, key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tier := range []*lru{c.memory, c.disk} {
+		if tier == nil {
+			continue
+		}
+		if e := tier.get(id); e != nil {
+			e.validatedAt = c.now()
+		}
+	}
+}
+
+func (c *cache) Delete(bucket, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(cacheId(bucket, key))
+}
+
+// remove drop the entry from every tier, the object may have changed tier with its size
+func (c *cache) remove(id string) {
+	for _, tier := range []*lru{c.memory, c.disk} {
+		if tier != nil {
+			tier.remove(id)
+		}
+	}
+}
+
+// lru keep entries up to capacity bytes, the least recently used are evicted first
+type lru struct {
+	capacity int64
+	size     int64
+	order    *list.List
+	items    map[string]*list.Element
+	onEvict  func(*entry)
+}
+
+func newLru(capacity int64, onEvict func(*entry)) *lru {
+	return &lru{capacity: capacity, order: list.New(), items: map[string]*list.Element{}, onEvict: onEvict}
+}
+
+func (l *lru) get(id string) *entry {
+	element, ok := l.items[id]
+	if !ok {
+		return nil
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*entry)
+}
+
+func (l *lru) add(e *entry) {
+	l.items[e.id] = l.order.PushFront(e)
+	l.size += e.object.Size
+	for l.size > l.capacity {
+		l.remove(l.order.Back().Value.(*entry).id)
+	}
+}
+
+func (l *lru) remove(id string) {
+	element, ok := l.items[id]
+	if !ok {
+		return
+	}
+	e := l.order.Remove(element).(*entry)
+	delete(l.items, id)
+	l.size -= e.object.Size
+	if l.onEvict != nil {
+		l.onEvict(e)
+	}
+}
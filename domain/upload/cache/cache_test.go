@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"aws-s3-bucket/models/document"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func initCacheUnitTest(t *testing.T) (*cache, *time.Time) {
+	interfaceCache, err := New(Config{
+		TTL:             time.Minute,
+		MemorySize:      10,
+		MemoryMaxObject: 4,
+		DiskDir:         t.TempDir(),
+		DiskSize:        20,
+		DiskMaxObject:   10,
+	})
+	require.NoError(t, err)
+
+	c := interfaceCache.(*cache)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func set(t *testing.T, c *cache, key, content string) {
+	body, err := c.Set("bucket", key, document.CachedObject{ETag: `"` + content + `"`, Size: int64(len(content))}, strings.NewReader(content))
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Equal(t, content, string(data))
+	body.Close()
+}
+
+func get(c *cache, key string) (string, bool) {
+	_, body, ok := c.Get("bucket", key)
+	if !ok {
+		return "", false
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	return string(data), true
+}
+
+func TestCache_Tiers(t *testing.T) {
+	c, _ := initCacheUnitTest(t)
+
+	require.True(t, c.Admit(4))
+	require.True(t, c.Admit(10))
+	require.False(t, c.Admit(11))
+
+	set(t, c, "a/small.txt", "abc")
+	set(t, c, "a/large.txt", "0123456789")
+	require.NotNil(t, c.memory.get("bucket/a/small.txt"))
+	require.NotNil(t, c.disk.get("bucket/a/large.txt"))
+
+	content, ok := get(c, "a/large.txt")
+	require.True(t, ok)
+	require.Equal(t, "0123456789", content)
+
+	// the object grew, its previous tier must not serve it anymore
+	set(t, c, "a/small.txt", "abcdefgh")
+	require.Nil(t, c.memory.get("bucket/a/small.txt"))
+	content, _ = get(c, "a/small.txt")
+	require.Equal(t, "abcdefgh", content)
+
+	c.Delete("bucket", "a/large.txt")
+	_, ok = get(c, "a/large.txt")
+	require.False(t, ok)
+	files, _ := os.ReadDir(c.config.DiskDir)
+	require.Len(t, files, 1)
+}
+
+func TestCache_Eviction(t *testing.T) {
+	c, _ := initCacheUnitTest(t)
+
+	set(t, c, "a/1.txt", "1111")
+	set(t, c, "a/2.txt", "2222")
+	// 1 is used, 2 is the least recently used
+	_, ok := get(c, "a/1.txt")
+	require.True(t, ok)
+	set(t, c, "a/3.txt", "3333")
+
+	_, ok = get(c, "a/2.txt")
+	require.False(t, ok)
+	_, ok = get(c, "a/1.txt")
+	require.True(t, ok)
+	_, ok = get(c, "a/3.txt")
+	require.True(t, ok)
+	require.Equal(t, int64(8), c.memory.size)
+}
+
+func TestCache_Stale(t *testing.T) {
+	c, now := initCacheUnitTest(t)
+
+	set(t, c, "a/1.txt", "1111")
+	object, body, ok := c.Get("bucket", "a/1.txt")
+	require.True(t, ok)
+	require.False(t, object.Stale)
+	body.Close()
+
+	*now = now.Add(2 * time.Minute)
+	object, body, _ = c.Get("bucket", "a/1.txt")
+	require.True(t, object.Stale)
+	body.Close()
+
+	c.Revalidated("bucket", "a/1.txt")
+	object, body, _ = c.Get("bucket", "a/1.txt")
+	require.False(t, object.Stale)
+	body.Close()
+}
+
+func TestNew_CleanDiskDir(t *testing.T) {
+	dir := t.TempDir()
+	leftover := filepath.Join(dir, strings.Repeat("a", 64)+"-123")
+	other := filepath.Join(dir, "keep.txt")
+	require.NoError(t, os.WriteFile(leftover, []byte("old"), 0o600))
+	require.NoError(t, os.WriteFile(other, []byte("keep"), 0o600))
+
+	_, err := New(Config{DiskDir: dir, DiskSize: 10, DiskMaxObject: 10})
+	require.NoError(t, err)
+
+	_, err = os.Stat(leftover)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(other)
+	require.NoError(t, err)
+}
@@ -0,0 +1,120 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/models/dto"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"bufio"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Integrator godoc
+// @Description  orchestrator to download many documents of s3 as one zip, by keys or by prefix
+// @Accept json
+// @Produce application/zip
+// @Param body body document.RequestArchiveDocument true "Body payload"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Router /api/v1/archive [post]
+func (h *handler) Archive(c *fiber.Ctx) error {
+	var request document.RequestArchiveDocument
+
+	if err := c.BodyParser(&request); err != nil {
+		log.Error("Error parsing request body")
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_PARSING_REQUEST,
+			Message:    "Failed to parse request body",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	err := h.validator.Validate(&request)
+	if err != nil {
+		log.Error("Validation error", err)
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_VALIDATION_ERROR,
+			Errors:     utils.UnwrapValidation(err),
+			Message:    "Validation failed",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	archive, err := h.usecase.PrepareArchive(c.Context(), request)
+	if err != nil {
+		log.Errorf("Error to prepare archive :%s", err.Error())
+		return errorResponse(c, err, "Failed to archive documents")
+	}
+
+	c.Set("Content-Type", "application/zip")
+	c.Set("Content-Disposition", `attachment; filename="archive.zip"`)
+	c.Status(http.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// request context is released when the handler return, streaming happen after
+		if err := h.usecase.WriteArchive(context.Background(), archive, w); err != nil {
+			log.Errorf("Error to write archive :%s", err.Error())
+		}
+	})
+
+	defer log.Info("Documents archived successfully", "count", len(archive.Entries), "size", archive.TotalSize)
+	return nil
+}
+
+// Integrator godoc
+// @Description  orchestrator to extract zip to s3, every entry is stored as document_key/<entry path>
+// @Produce json
+// @Param file formData file true "zip archive"
+// @Param document_key formData string true "key document" default(folder-in-s3)
+// @Param overwrite formData string false "overwrite policy when document exists: allow, reject or rename"
+// @Success 201 {object} dto.ApiResponse{data=document.ResponseUploadArchive}
+// @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Router /api/v1/upload/archive [post]
+func (h *handler) UploadArchive(c *fiber.Ctx) error {
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Error("Error Get file from form")
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_PARSING_REQUEST,
+			Message:    "Error get file from form",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	request := document.RequestUploadArchive{
+		DocumentKey: c.FormValue("document_key"),
+		Overwrite:   c.FormValue("overwrite"),
+	}
+
+	err = h.validator.Validate(request)
+	if err != nil {
+		log.Error("Validation error", err)
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_VALIDATION_ERROR,
+			Errors:     utils.UnwrapValidation(err),
+			Message:    "Validation failed",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	response, err := h.usecase.UploadArchive(c.Context(), request, file)
+	if err != nil {
+		log.Errorf("Error to extract archive :%s", err.Error())
+		return errorResponse(c, err, "Failed to extract archive")
+	}
+	defer log.Info("Archive extracted successfully", "document_key", request.DocumentKey, "count", len(response.Documents))
+
+	return c.Status(http.StatusCreated).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Archive extracted successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,163 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+
+	handler, mockUsecase, mockValidator := initRestUnitTest(t)
+
+	archive := document.Archive{Entries: []document.ArchiveEntry{{Key: "abc/file.txt", Name: "file.txt", Size: 5}}, TotalSize: 5}
+
+	tests := []struct {
+		name       string
+		body       string
+		prepare    func()
+		statusCode int
+		response   string
+	}{
+		{
+			name:       "Archive_ParseError",
+			body:       "invalid-json",
+			prepare:    func() {},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "Archive_Success",
+			body: `{"prefix":"abc/"}`,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("PrepareArchive", mock.Anything, document.RequestArchiveDocument{Prefix: "abc/"}).Return(archive, nil).Once()
+				mockUsecase.On("WriteArchive", mock.Anything, archive, mock.Anything).Run(func(args mock.Arguments) {
+					io.WriteString(args.Get(2).(io.Writer), "zip content")
+				}).Return(nil).Once()
+			},
+			statusCode: fiber.StatusOK,
+			response:   "zip content",
+		},
+		{
+			name: "Archive_TooLarge",
+			body: `{"keys":["abc/file.txt"]}`,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("PrepareArchive", mock.Anything, mock.Anything).Return(document.Archive{}, fmt.Errorf("%w: more than 1 files", document.ErrArchiveTooLarge)).Once()
+			},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "Archive_Error",
+			body: `{"keys":["abc/file.txt"]}`,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("PrepareArchive", mock.Anything, mock.Anything).Return(document.Archive{}, errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Post("/archive", handler.Archive)
+
+			req := httptest.NewRequest(http.MethodPost, "/archive", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+			if tt.response != "" {
+				body, _ := io.ReadAll(resp.Body)
+				require.Equal(t, tt.response, string(body))
+				require.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestUploadArchive(t *testing.T) {
+
+	handler, mockUsecase, mockValidator := initRestUnitTest(t)
+
+	tests := []struct {
+		name       string
+		withFile   bool
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name:       "UploadArchive_NoFile",
+			prepare:    func() {},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name:     "UploadArchive_Success",
+			withFile: true,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadArchive", mock.Anything, document.RequestUploadArchive{DocumentKey: "folder"}, mock.Anything).
+					Return(document.ResponseUploadArchive{Documents: []document.ResponseExtractedDocument{{Entry: "a.txt", Key: "folder/a.txt"}}}, nil).Once()
+			},
+			statusCode: fiber.StatusCreated,
+		},
+		{
+			name:     "UploadArchive_Invalid",
+			withFile: true,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadArchive", mock.Anything, mock.Anything, mock.Anything).
+					Return(document.ResponseUploadArchive{}, fmt.Errorf("%w: zip: not a valid zip file", document.ErrInvalidArchive)).Once()
+			},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name:     "UploadArchive_Error",
+			withFile: true,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadArchive", mock.Anything, mock.Anything, mock.Anything).
+					Return(document.ResponseUploadArchive{}, errors.New("failed to open file")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Post("/upload/archive", handler.UploadArchive)
+
+			body := new(bytes.Buffer)
+			writer := multipart.NewWriter(body)
+			writer.WriteField("document_key", "folder")
+			if tt.withFile {
+				fw, _ := writer.CreateFormFile("file", "archive.zip")
+				fw.Write([]byte("zip"))
+			}
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/upload/archive", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/constant"
+	"crypto/subtle"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// signingApiKeys is configured by DOWNLOAD_SIGNING_API_KEYS, comma separated keys of the clients allowed to sign links
+func signingApiKeys() (keys []string) {
+	for _, key := range strings.Split(os.Getenv("DOWNLOAD_SIGNING_API_KEYS"), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return
+}
+
+// requireSigningApiKey let the request through only with an X-Api-Key of DOWNLOAD_SIGNING_API_KEYS. a signed link
+// bypass every download restriction, so the route is closed when no key is configured
+func requireSigningApiKey(c *fiber.Ctx) error {
+	keys := signingApiKeys()
+	if len(keys) == 0 {
+		return errorResponse(c, document.ErrSigningNotConfigured, "Failed to sign download link")
+	}
+
+	given := []byte(c.Get(constant.HEADER_API_KEY))
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare(given, []byte(key)) == 1 {
+			return c.Next()
+		}
+	}
+	return errorResponse(c, document.ErrUnauthorized, "Failed to sign download link")
+}
@@ -0,0 +1,45 @@
+package delivery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireSigningApiKey(t *testing.T) {
+
+	app := fiber.New()
+	app.Post("/documents/sign", requireSigningApiKey, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		keys       string
+		apiKey     string
+		statusCode int
+	}{
+		{name: "NotConfigured", apiKey: "key-1", statusCode: fiber.StatusNotImplemented},
+		{name: "Missing", keys: "key-1,key-2", statusCode: fiber.StatusUnauthorized},
+		{name: "Wrong", keys: "key-1,key-2", apiKey: "key-3", statusCode: fiber.StatusUnauthorized},
+		{name: "Valid", keys: "key-1, key-2", apiKey: "key-2", statusCode: fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("DOWNLOAD_SIGNING_API_KEYS", tt.keys)
+			defer os.Unsetenv("DOWNLOAD_SIGNING_API_KEYS")
+
+			req := httptest.NewRequest(http.MethodPost, "/documents/sign", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-Api-Key", tt.apiKey)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/models/dto"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Integrator godoc
+// @Description  orchestrator to upload many files to s3 in one request, the result of every file is returned
+// @Produce json
+// @Param files formData file true "file documents, the field can be repeated"
+// @Param document_key formData string true "key document shared by every file" default(folder-in-s3)
+// @Param document_name formData []string false "name of every file in the same order, file name without extension when empty" collectionFormat(multi)
+// @Param overwrite formData string false "overwrite policy when document exists: allow, reject or rename"
+// @Param atomic formData bool false "delete the uploaded documents when one file failed, existing document is never overwritten"
+// @Param tags formData []string false "searchable tag of every file, repeat it for several, custom metadata is sent as metadata[name] fields" collectionFormat(multi)
+// @Param visibility formData string false "public or private for every file, private require a signed link to download"
+// @Param checksum_sha256 formData []string false "expected SHA-256 of every file in the same order, base64 or hex, empty is not checked" collectionFormat(multi)
+// @Param checksum_crc32c formData []string false "expected CRC32C of every file in the same order, base64 or hex, empty is not checked" collectionFormat(multi)
+// @Success 201 {object} dto.ApiResponse{data=document.ResponseUploadBatch}
+// @Success 207 {object} dto.ApiResponse{data=document.ResponseUploadBatch}
+// @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Router /api/v1/upload/batch [post]
+func (h *handler) UploadBatch(c *fiber.Ctx) error {
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		log.Error("Error Get files from form")
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_PARSING_REQUEST,
+			Message:    "Error get files from form",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	request := document.RequestUploadBatch{
+		DocumentKey:   c.FormValue("document_key"),
+		DocumentNames: form.Value["document_name"],
+		Overwrite:     c.FormValue("overwrite"),
+		Atomic:        c.FormValue("atomic") == "true",
+		Visibility:    c.FormValue("visibility"),
+
+		ChecksumSHA256: form.Value["checksum_sha256"],
+		ChecksumCRC32C: form.Value["checksum_crc32c"],
+	}
+	request.Tags, request.Metadata = formAttributes(c)
+
+	err = h.validator.Validate(request)
+	if err != nil {
+		log.Error("Validation error", err)
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_VALIDATION_ERROR,
+			Errors:     utils.UnwrapValidation(err),
+			Message:    "Validation failed",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	response, err := h.usecase.UploadBatch(c.Context(), request, form.File["files"])
+	if err != nil {
+		log.Errorf("Error to upload batch :%s", err.Error())
+		return errorResponse(c, err, "Failed to upload documents")
+	}
+	defer log.Info("Batch uploaded", "document_key", request.DocumentKey, "succeeded", response.Succeeded, "failed", response.Failed)
+
+	if response.Failed > 0 {
+		return c.Status(http.StatusMultiStatus).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_GENERAL_ERROR,
+			Message:    "Some documents failed to upload",
+			Data:       response,
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Documents uploaded successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
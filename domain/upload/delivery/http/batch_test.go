@@ -0,0 +1,103 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadBatch(t *testing.T) {
+
+	handler, mockUsecase, mockValidator := initRestUnitTest(t)
+
+	request := document.RequestUploadBatch{
+		DocumentKey:    "folder",
+		DocumentNames:  []string{"a", "b"},
+		Atomic:         true,
+		Visibility:     "private",
+		Tags:           []string{"invoice"},
+		ChecksumSHA256: []string{"", "abc"},
+	}
+	withFiles := mock.MatchedBy(func(files []*multipart.FileHeader) bool {
+		return len(files) == 2 && files[0].Filename == "a.txt" && files[1].Filename == "b.txt"
+	})
+
+	tests := []struct {
+		name       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name: "UploadBatch_Success",
+			prepare: func() {
+				mockValidator.On("Validate", request).Return(nil).Once()
+				mockUsecase.On("UploadBatch", mock.Anything, request, withFiles).Return(document.ResponseUploadBatch{Succeeded: 2}, nil).Once()
+			},
+			statusCode: fiber.StatusCreated,
+		},
+		{
+			name: "UploadBatch_PartialFailure",
+			prepare: func() {
+				mockValidator.On("Validate", request).Return(nil).Once()
+				mockUsecase.On("UploadBatch", mock.Anything, request, withFiles).Return(document.ResponseUploadBatch{Succeeded: 1, Failed: 1, RolledBack: true}, nil).Once()
+			},
+			statusCode: fiber.StatusMultiStatus,
+		},
+		{
+			name: "UploadBatch_Invalid",
+			prepare: func() {
+				mockValidator.On("Validate", request).Return(nil).Once()
+				mockUsecase.On("UploadBatch", mock.Anything, request, withFiles).Return(document.ResponseUploadBatch{}, fmt.Errorf("%w: between 1 and 1 files expected", document.ErrInvalidBatch)).Once()
+			},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "UploadBatch_Error",
+			prepare: func() {
+				mockValidator.On("Validate", request).Return(nil).Once()
+				mockUsecase.On("UploadBatch", mock.Anything, request, withFiles).Return(document.ResponseUploadBatch{}, errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Post("/upload/batch", handler.UploadBatch)
+
+			body := new(bytes.Buffer)
+			writer := multipart.NewWriter(body)
+			writer.WriteField("document_key", "folder")
+			writer.WriteField("document_name", "a")
+			writer.WriteField("document_name", "b")
+			writer.WriteField("atomic", "true")
+			writer.WriteField("visibility", "private")
+			writer.WriteField("tags", "invoice")
+			writer.WriteField("checksum_sha256", "")
+			writer.WriteField("checksum_sha256", "abc")
+			for _, name := range []string{"a.txt", "b.txt"} {
+				fw, _ := writer.CreateFormFile("files", name)
+				fw.Write([]byte(name))
+			}
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/upload/batch", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
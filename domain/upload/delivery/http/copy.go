@@ -0,0 +1,107 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/models/dto"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Integrator godoc
+// @Description  orchestrator to copy document to another key or bucket without downloading it
+// @Produce json
+// @Param body body document.RequestCopyDocument true "Body payload"
+// @Success 201 {object} dto.ApiResponse{data=document.ResponseUploadDocument}
+// @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 409 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Router /api/v1/documents/copy [post]
+func (h *handler) CopyDocument(c *fiber.Ctx) error {
+	request, err := h.parseCopyRequest(c)
+	if err != nil {
+		return err
+	}
+	if request == nil {
+		return nil
+	}
+
+	response, err := h.usecase.CopyDocument(c.Context(), *request)
+	if err != nil {
+		log.Errorf("Error to copy document :%s", err.Error())
+		return errorResponse(c, err, "Failed to copy document")
+	}
+	defer log.Info("Document copied successfully", "source_key", request.SourceKey, "destination_key", response.Key)
+
+	return c.Status(http.StatusCreated).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Document copied successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
+
+// Integrator godoc
+// @Description  orchestrator to move document to another key or bucket, the source is deleted after the copy
+// @Produce json
+// @Param body body document.RequestCopyDocument true "Body payload"
+// @Success 200 {object} dto.ApiResponse{data=document.ResponseUploadDocument}
+// @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 409 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Router /api/v1/documents/move [post]
+func (h *handler) MoveDocument(c *fiber.Ctx) error {
+	request, err := h.parseCopyRequest(c)
+	if err != nil {
+		return err
+	}
+	if request == nil {
+		return nil
+	}
+
+	response, err := h.usecase.MoveDocument(c.Context(), *request)
+	if err != nil {
+		log.Errorf("Error to move document :%s", err.Error())
+		return errorResponse(c, err, "Failed to move document")
+	}
+	defer log.Info("Document moved successfully", "source_key", request.SourceKey, "destination_key", response.Key)
+
+	return c.Status(http.StatusOK).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Document moved successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
+
+// parseCopyRequest return nil request when the error response is already written
+func (h *handler) parseCopyRequest(c *fiber.Ctx) (*document.RequestCopyDocument, error) {
+	var request document.RequestCopyDocument
+
+	if err := c.BodyParser(&request); err != nil {
+		log.Error("Error parsing request body")
+		return nil, c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_PARSING_REQUEST,
+			Message:    "Failed to parse request body",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	if err := h.validator.Validate(&request); err != nil {
+		log.Error("Validation error", err)
+		return nil, c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_VALIDATION_ERROR,
+			Errors:     utils.UnwrapValidation(err),
+			Message:    "Validation failed",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	return &request, nil
+}
@@ -0,0 +1,104 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyDocument(t *testing.T) {
+
+	handler, mockUsecase, mockValidator := initRestUnitTest(t)
+
+	body := `{"source_key":"a/b.pdf","destination_key":"c/b.pdf","overwrite":"reject"}`
+	request := document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf", Overwrite: "reject"}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name:       "Copy_ParseError",
+			path:       "/documents/copy",
+			body:       "invalid-json",
+			prepare:    func() {},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "Copy_Success",
+			path: "/documents/copy",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("CopyDocument", mock.Anything, request).Return(document.ResponseUploadDocument{Key: "c/b.pdf"}, nil).Once()
+			},
+			statusCode: fiber.StatusCreated,
+		},
+		{
+			name: "Copy_Exists",
+			path: "/documents/copy",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("CopyDocument", mock.Anything, request).Return(document.ResponseUploadDocument{}, document.ErrDocumentAlreadyExists).Once()
+			},
+			statusCode: fiber.StatusConflict,
+		},
+		{
+			name: "Move_Success",
+			path: "/documents/move",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("MoveDocument", mock.Anything, request).Return(document.ResponseUploadDocument{Key: "c/b.pdf"}, nil).Once()
+			},
+			statusCode: fiber.StatusOK,
+		},
+		{
+			name: "Move_NotFound",
+			path: "/documents/move",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("MoveDocument", mock.Anything, request).Return(document.ResponseUploadDocument{}, document.ErrDocumentNotFound).Once()
+			},
+			statusCode: fiber.StatusNotFound,
+		},
+		{
+			name: "Move_Error",
+			path: "/documents/move",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("MoveDocument", mock.Anything, request).Return(document.ResponseUploadDocument{}, errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Post("/documents/copy", handler.CopyDocument)
+			app.Post("/documents/move", handler.MoveDocument)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/models/dto"
+	"aws-s3-bucket/shared/constant"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorResponse write the response of usecase error, known document error get its own status
+// and the others fallback to internal server error with the given message
+func errorResponse(c *fiber.Ctx, err error, message string) error {
+	status, code := http.StatusInternalServerError, constant.STATUS_CODE_GENERAL_ERROR
+
+	switch {
+	case errors.Is(err, document.ErrDocumentAlreadyExists):
+		status, code, message = http.StatusConflict, constant.STATUS_CODE_CONFLICT, "Document already exists"
+	case errors.Is(err, document.ErrDocumentNotFound):
+		status, code, message = http.StatusNotFound, constant.STATUS_CODE_NOT_FOUND, "Document not found"
+	case errors.Is(err, document.ErrChecksumMismatch):
+		status, code, message = http.StatusBadRequest, constant.STATUS_CODE_VALIDATION_ERROR, "Checksum mismatch"
+	case errors.Is(err, document.ErrInvalidChecksum):
+		status, code, message = http.StatusBadRequest, constant.STATUS_CODE_VALIDATION_ERROR, "Invalid checksum format"
+	case errors.Is(err, document.ErrInvalidTransformation):
+		status, code, message = http.StatusBadRequest, constant.STATUS_CODE_VALIDATION_ERROR, err.Error()
+	case errors.Is(err, document.ErrArchiveTooLarge), errors.Is(err, document.ErrInvalidArchive), errors.Is(err, document.ErrInvalidBatch):
+		status, code, message = http.StatusBadRequest, constant.STATUS_CODE_VALIDATION_ERROR, err.Error()
+	case errors.Is(err, document.ErrRangeNotSatisfiable):
+		status, code, message = http.StatusRequestedRangeNotSatisfiable, constant.STATUS_CODE_VALIDATION_ERROR, "Range not satisfiable"
+	case errors.Is(err, document.ErrInvalidUrl), errors.Is(err, document.ErrBucketNotAllowed), errors.Is(err, document.ErrInvalidCopy):
+		status, code, message = http.StatusBadRequest, constant.STATUS_CODE_VALIDATION_ERROR, err.Error()
+	case errors.Is(err, document.ErrInvalidMetadata), errors.Is(err, document.ErrInvalidSearch), errors.Is(err, document.ErrInvalidVisibility):
+		status, code, message = http.StatusBadRequest, constant.STATUS_CODE_VALIDATION_ERROR, err.Error()
+	case errors.Is(err, document.ErrInvalidBase64), errors.Is(err, document.ErrReservedKey):
+		status, code, message = http.StatusBadRequest, constant.STATUS_CODE_VALIDATION_ERROR, err.Error()
+	case errors.Is(err, document.ErrDocumentTooLarge):
+		status, code, message = http.StatusRequestEntityTooLarge, constant.STATUS_CODE_VALIDATION_ERROR, err.Error()
+	case errors.Is(err, document.ErrRemoteFetch):
+		status, message = http.StatusBadGateway, err.Error()
+	case errors.Is(err, document.ErrDatabaseNotConfigured):
+		status, message = http.StatusNotImplemented, "Document database is not configured"
+	case errors.Is(err, document.ErrInvalidSignature):
+		status, code, message = http.StatusForbidden, constant.STATUS_CODE_FORBIDDEN, err.Error()
+	case errors.Is(err, document.ErrUnauthorized):
+		status, code, message = http.StatusUnauthorized, constant.STATUS_CODE_UNAUTHORIZED, err.Error()
+	case errors.Is(err, document.ErrSigningNotConfigured):
+		status, message = http.StatusNotImplemented, "Download signing is not configured"
+	case errors.Is(err, document.ErrEncryptedDocument):
+		status, code, message = http.StatusBadRequest, constant.STATUS_CODE_VALIDATION_ERROR, "Encrypted document is not allowed"
+	}
+
+	return c.Status(status).JSON(dto.ApiResponse{
+		Code:       code,
+		Message:    message,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
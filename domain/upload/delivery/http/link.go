@@ -0,0 +1,61 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/models/dto"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Integrator godoc
+// @Description  orchestrator to issue an expiring signed link of document served by this service, optionally bound to the client ip or disposition
+// @Produce json
+// @Param X-Api-Key header string true "api key of DOWNLOAD_SIGNING_API_KEYS"
+// @Param body body document.RequestSignDownload true "Body payload"
+// @Success 200 {object} dto.ApiResponse{data=document.ResponseSignDownload}
+// @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 401 {object} dto.ApiResponse{}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Failure 501 {object} dto.ApiResponse{}
+// @Router /api/v1/documents/sign [post]
+func (h *handler) SignDownloadLink(c *fiber.Ctx) error {
+	var request document.RequestSignDownload
+
+	if err := c.BodyParser(&request); err != nil {
+		log.Error("Error parsing request body")
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_PARSING_REQUEST,
+			Message:    "Failed to parse request body",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	if err := h.validator.Validate(&request); err != nil {
+		log.Error("Validation error", err)
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_VALIDATION_ERROR,
+			Errors:     utils.UnwrapValidation(err),
+			Message:    "Validation failed",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	response, err := h.usecase.SignDownloadLink(c.Context(), request)
+	if err != nil {
+		log.Errorf("Error to sign download link :%s", err.Error())
+		return errorResponse(c, err, "Failed to sign download link")
+	}
+
+	return c.Status(http.StatusOK).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Download link signed successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
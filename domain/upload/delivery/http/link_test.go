@@ -0,0 +1,93 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignDownloadLink(t *testing.T) {
+
+	handler, mockUsecase, mockValidator := initRestUnitTest(t)
+	request := document.RequestSignDownload{Key: "abc/file.txt", ExpiresIn: 60, Disposition: "download"}
+
+	tests := []struct {
+		name       string
+		body       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name:       "ParseError",
+			body:       "invalid-json",
+			prepare:    func() {},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "Success",
+			body: `{"key":"abc/file.txt","expires_in":60,"disposition":"download"}`,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("SignDownloadLink", mock.Anything, request).Return(document.ResponseSignDownload{Url: "http://localhost/api/v1/download/abc/file.txt?sig=x"}, nil).Once()
+			},
+			statusCode: fiber.StatusOK,
+		},
+		{
+			name: "NotConfigured",
+			body: `{"key":"abc/file.txt","expires_in":60,"disposition":"download"}`,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("SignDownloadLink", mock.Anything, request).Return(document.ResponseSignDownload{}, document.ErrSigningNotConfigured).Once()
+			},
+			statusCode: fiber.StatusNotImplemented,
+		},
+	}
+
+	app := fiber.New()
+	app.Post("/documents/sign", handler.SignDownloadLink)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			req := httptest.NewRequest(http.MethodPost, "/documents/sign", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestGetFile_SignedLink(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	app := fiber.New()
+	app.Get("/download/:docKey/:docName", handler.GetFile)
+
+	link := &document.RequestDownloadLink{Expires: "1700000000", KeyId: "k1", Bind: "type", Signature: "sig", ClientIp: "0.0.0.0", Disposition: "download"}
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{Key: "abc/file.txt", Link: link}).Return(&s3.GetObjectOutput{
+		Body:        io.NopCloser(bytes.NewReader([]byte("Hello Fiber"))),
+		ContentType: aws.String("text/plain"),
+	}, nil).Once()
+	mockUsecase.On("DownloadFile", mock.Anything, mock.Anything).Return(nil, document.ErrInvalidSignature).Once()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/download/abc/file.txt?type=download&expires=1700000000&kid=k1&bind=type&sig=sig", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/download/abc/file.txt?expires=1700000000&kid=k1&sig=forged", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
@@ -0,0 +1,62 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/models/dto"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Integrator godoc
+// @Description  orchestrator to list documents recorded in the metadata database, ordered by key
+// @Produce json
+// @Param bucket query string false "bucket listed in ALLOWED_BUCKETS, BUCKET_NAME when empty"
+// @Param prefix query string false "key prefix" default(folder-in-s3/)
+// @Param include_deleted query bool false "include soft deleted documents"
+// @Param limit query int false "max documents, up to 1000" default(100)
+// @Param offset query int false "documents to skip"
+// @Success 200 {object} dto.ApiResponse{data=document.ResponseListDocument}
+// @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Failure 501 {object} dto.ApiResponse{}
+// @Router /api/v1/documents [get]
+func (h *handler) ListDocuments(c *fiber.Ctx) error {
+	var request document.RequestListDocument
+
+	if err := c.QueryParser(&request); err != nil {
+		log.Error("Error parsing request query")
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_PARSING_REQUEST,
+			Message:    "Failed to parse request query",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	if err := h.validator.Validate(&request); err != nil {
+		log.Error("Validation error", err)
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_VALIDATION_ERROR,
+			Errors:     utils.UnwrapValidation(err),
+			Message:    "Validation failed",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	response, err := h.usecase.ListDocuments(c.Context(), request)
+	if err != nil {
+		log.Errorf("Error to list documents :%s", err.Error())
+		return errorResponse(c, err, "Failed to list documents")
+	}
+
+	return c.Status(http.StatusOK).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Documents listed successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
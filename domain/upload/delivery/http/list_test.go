@@ -0,0 +1,75 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDocuments(t *testing.T) {
+
+	handler, mockUsecase, mockValidator := initRestUnitTest(t)
+
+	request := document.RequestListDocument{Prefix: "data/", IncludeDeleted: true, Limit: 10, Offset: 20}
+
+	tests := []struct {
+		name       string
+		path       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name:       "ParseError",
+			path:       "/documents?limit=ten",
+			prepare:    func() {},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "Success",
+			path: "/documents?prefix=data/&include_deleted=true&limit=10&offset=20",
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("ListDocuments", mock.Anything, request).Return(document.ResponseListDocument{Limit: 10, Offset: 20}, nil).Once()
+			},
+			statusCode: fiber.StatusOK,
+		},
+		{
+			name: "NotConfigured",
+			path: "/documents",
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("ListDocuments", mock.Anything, document.RequestListDocument{}).Return(document.ResponseListDocument{}, document.ErrDatabaseNotConfigured).Once()
+			},
+			statusCode: fiber.StatusNotImplemented,
+		},
+		{
+			name: "Error",
+			path: "/documents?prefix=data/&include_deleted=true&limit=10&offset=20",
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("ListDocuments", mock.Anything, request).Return(document.ResponseListDocument{}, errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Get("/documents", handler.ListDocuments)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
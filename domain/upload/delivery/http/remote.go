@@ -0,0 +1,71 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/models/dto"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Integrator godoc
+// @Description  orchestrator to fetch document from url and upload it to s3
+// @Produce json
+// @Param body body document.RequestUploadDocumentUrl true "Body payload"
+// @Param X-Checksum-Sha256 header string false "expected SHA-256 of fetched document, base64 or hex"
+// @Param X-Checksum-Crc32c header string false "expected CRC32C of fetched document, base64 or hex"
+// @Success 201 {object} dto.ApiResponse{data=document.ResponseUploadDocument}
+// @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 409 {object} dto.ApiResponse{}
+// @Failure 413 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Failure 502 {object} dto.ApiResponse{}
+// @Router /api/v1/upload/url [post]
+func (h *handler) UploadUrl(c *fiber.Ctx) error {
+	var request document.RequestUploadDocumentUrl
+
+	if err := c.BodyParser(&request); err != nil {
+		log.Error("Error parsing request body")
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_PARSING_REQUEST,
+			Message:    "Failed to parse request body",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	if checksum := c.Get(constant.HEADER_CHECKSUM_SHA256); checksum != "" {
+		request.ChecksumSHA256 = checksum
+	}
+	if checksum := c.Get(constant.HEADER_CHECKSUM_CRC32C); checksum != "" {
+		request.ChecksumCRC32C = checksum
+	}
+
+	err := h.validator.Validate(&request)
+	if err != nil {
+		log.Error("Validation error", err)
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_VALIDATION_ERROR,
+			Errors:     utils.UnwrapValidation(err),
+			Message:    "Validation failed",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	response, err := h.usecase.UploadUrl(c.Context(), request)
+	if err != nil {
+		log.Errorf("Error to upload url :%s", err.Error())
+		return errorResponse(c, err, "Failed upload document")
+	}
+	defer log.Info("Document uploaded successfully", "document_key", request.DocumentKey, "document_name", request.DocumentName)
+
+	return c.Status(http.StatusCreated).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Document uploaded successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
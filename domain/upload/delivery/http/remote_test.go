@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadUrl(t *testing.T) {
+
+	handler, mockUsecase, mockValidator := initRestUnitTest(t)
+
+	body := `{"document_key":"folder","document_name":"invoice","url":"https://example.com/invoice.pdf"}`
+	request := document.RequestUploadDocumentUrl{DocumentKey: "folder", DocumentName: "invoice", Url: "https://example.com/invoice.pdf"}
+
+	tests := []struct {
+		name       string
+		body       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name:       "UploadUrl_ParseError",
+			body:       "invalid-json",
+			prepare:    func() {},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "UploadUrl_Success",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadUrl", mock.Anything, request).Return(document.ResponseUploadDocument{Key: "folder/invoice.pdf"}, nil).Once()
+			},
+			statusCode: fiber.StatusCreated,
+		},
+		{
+			name: "UploadUrl_Blocked",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadUrl", mock.Anything, request).Return(document.ResponseUploadDocument{}, fmt.Errorf("%w: host example.com is not allowed", document.ErrInvalidUrl)).Once()
+			},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "UploadUrl_TooLarge",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadUrl", mock.Anything, request).Return(document.ResponseUploadDocument{}, document.ErrDocumentTooLarge).Once()
+			},
+			statusCode: fiber.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "UploadUrl_RemoteError",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadUrl", mock.Anything, request).Return(document.ResponseUploadDocument{}, fmt.Errorf("%w: remote respond 404 Not Found", document.ErrRemoteFetch)).Once()
+			},
+			statusCode: fiber.StatusBadGateway,
+		},
+		{
+			name: "UploadUrl_Error",
+			body: body,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadUrl", mock.Anything, request).Return(document.ResponseUploadDocument{}, errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Post("/upload/url", handler.UploadUrl)
+
+			req := httptest.NewRequest(http.MethodPost, "/upload/url", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
@@ -12,8 +12,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
 )
@@ -31,7 +35,23 @@ func NewHandler(route fiber.Router, usecase interfaces.UsecaseInterface, validat
 
 	route.Post("upload/base64", handler.UploadBase64)
 	route.Post("upload/file", handler.UploadFile)
+	route.Post("upload/url", handler.UploadUrl)
+	route.Post("upload/batch", handler.UploadBatch)
+	route.Post("upload/archive", handler.UploadArchive)
 	route.Get("download/:docKey/:docName", handler.GetFile)
+	// nested key, ex: extracted archive entry folder-in-s3/sub/example.png
+	route.Get("download/:docKey/*", handler.GetFile)
+	route.Get("files/:id", handler.GetFileById)
+	route.Get("documents", handler.ListDocuments)
+	route.Get("documents/search", handler.SearchDocuments)
+	route.Post("documents/sign", requireSigningApiKey, handler.SignDownloadLink)
+	route.Delete("documents/:docKey/:docName", handler.DeleteFile)
+	route.Get("documents/:docKey/:docName", handler.GetMetadata)
+	route.Post("archive", handler.Archive)
+	route.Post("documents/copy", handler.CopyDocument)
+	route.Post("documents/move", handler.MoveDocument)
+	route.Get("documents/:docKey/:docName/versions", handler.ListVersions)
+	route.Post("documents/:docKey/:docName/versions/:versionId/restore", handler.RestoreVersion)
 
 }
 
@@ -39,8 +59,11 @@ func NewHandler(route fiber.Router, usecase interfaces.UsecaseInterface, validat
 // @Description  orchestrator to upload base64 to s3
 // @Produce json
 // @Param body body document.RequestUploadDocumentBase64 true "Body payload"
+// @Param X-Checksum-Sha256 header string false "expected SHA-256 of decoded document, base64 or hex"
+// @Param X-Checksum-Crc32c header string false "expected CRC32C of decoded document, base64 or hex"
 // @Success 200 {object} dto.ApiResponse{data=document.ResponseUploadDocument}
 // @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 409 {object} dto.ApiResponse{}
 // @Failure 500 {object} dto.ApiResponse{}
 // @Router /api/v1/upload/base64 [post]
 func (h *handler) UploadBase64(c *fiber.Ctx) error {
@@ -55,6 +78,13 @@ func (h *handler) UploadBase64(c *fiber.Ctx) error {
 		})
 	}
 
+	if checksum := c.Get(constant.HEADER_CHECKSUM_SHA256); checksum != "" {
+		request.ChecksumSHA256 = checksum
+	}
+	if checksum := c.Get(constant.HEADER_CHECKSUM_CRC32C); checksum != "" {
+		request.ChecksumCRC32C = checksum
+	}
+
 	err := h.validator.Validate(&request)
 	if err != nil {
 		log.Error("Validation error", err)
@@ -69,11 +99,7 @@ func (h *handler) UploadBase64(c *fiber.Ctx) error {
 	response, err := h.usecase.UploadBase64(c.Context(), request)
 	if err != nil {
 		log.Error("Error to upload base64")
-		return c.Status(http.StatusInternalServerError).JSON(dto.ApiResponse{
-			Code:       constant.STATUS_CODE_GENERAL_ERROR,
-			Message:    "Failed upload document",
-			ServerTime: time.Now().Format(time.RFC3339),
-		})
+		return errorResponse(c, err, "Failed upload document")
 	}
 
 	defer log.Info("Document uploaded successfully", "document_key", request.DocumentKey, "document_name", request.DocumentName)
@@ -93,8 +119,14 @@ func (h *handler) UploadBase64(c *fiber.Ctx) error {
 // @Param file formData file true "file document"
 // @Param document_key formData string true "key document" default(folder-in-s3)
 // @Param document_name formData string true "name document" default(example)
+// @Param overwrite formData string false "overwrite policy when document exists: allow, reject or rename"
+// @Param tags formData []string false "searchable tag, repeat it for several, custom metadata is sent as metadata[name] fields" collectionFormat(multi)
+// @Param visibility formData string false "public or private, private require a signed link to download"
+// @Param X-Checksum-Sha256 header string false "expected SHA-256 of document, base64 or hex"
+// @Param X-Checksum-Crc32c header string false "expected CRC32C of document, base64 or hex"
 // @Success 200 {object} dto.ApiResponse{data=document.ResponseUploadDocument}
 // @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 409 {object} dto.ApiResponse{}
 // @Failure 500 {object} dto.ApiResponse{}
 // @Router /api/v1/upload/file [post]
 func (h *handler) UploadFile(c *fiber.Ctx) error {
@@ -114,7 +146,13 @@ func (h *handler) UploadFile(c *fiber.Ctx) error {
 	request := document.RequestUploadDocumentFile{
 		DocumentKey:  documentName,
 		DocumentName: documenKey,
+		Overwrite:    c.FormValue("overwrite"),
+		Visibility:   c.FormValue("visibility"),
+
+		ChecksumSHA256: c.Get(constant.HEADER_CHECKSUM_SHA256, c.FormValue("checksum_sha256")),
+		ChecksumCRC32C: c.Get(constant.HEADER_CHECKSUM_CRC32C, c.FormValue("checksum_crc32c")),
 	}
+	request.Tags, request.Metadata = formAttributes(c)
 
 	err = h.validator.Validate(request)
 	if err != nil {
@@ -130,11 +168,7 @@ func (h *handler) UploadFile(c *fiber.Ctx) error {
 	response, err := h.usecase.UploadFile(c.Context(), request, file)
 	if err != nil {
 		log.Error("Error usecase to upload file")
-		return c.Status(http.StatusInternalServerError).JSON(dto.ApiResponse{
-			Code:       constant.STATUS_CODE_GENERAL_ERROR,
-			Message:    "Failed to upload document",
-			ServerTime: time.Now().Format(time.RFC3339),
-		})
+		return errorResponse(c, err, "Failed to upload document")
 	}
 	defer log.Info("Document uploaded successfully", "document_key", request.DocumentKey, "document_name", request.DocumentName)
 
@@ -150,26 +184,123 @@ func (h *handler) UploadFile(c *fiber.Ctx) error {
 // @Description  orchestrator to get base64 to s3
 // @Produce json
 // @Param type query string  false "type downloaded can be empty(file),downloaded and base64" default(base64)
+// @Param filename query string false "name of the downloaded file, the uploaded filename by default"
+// @Param rendition query string false "rendition profile or preview served instead of the document"
+// @Param versionId query string false "version of document, latest when empty"
+// @Param w query int false "image width in pixel, derived from height when empty"
+// @Param h query int false "image height in pixel, derived from width when empty"
+// @Param fit query string false "how the image fit the size: contain, cover or fill" default(contain)
+// @Param format query string false "image output format: jpeg, png or webp"
+// @Param quality query int false "jpeg quality between 1 and 100" default(80)
 // @Param docKey path string true "document key" default(folder-in-s3)
 // @Param docName path string true "document name" default(example.png)
+// @Param Range header string false "single byte range, ex: bytes=0-1023"
+// @Param Accept-Encoding header string false "compressed document is sent as is when its encoding is accepted"
+// @Param expires query int false "unix time the signed link expires at"
+// @Param kid query string false "id of the secret of the signed link"
+// @Param bind query string false "comma separated values bound to the signed link: ip, type"
+// @Param sig query string false "signature of the signed link"
+// @Param If-None-Match header string false "etag of the copy of the client, 304 when it is still current"
 // @Failure 200 {object} dto.ApiResponse{}
+// @Failure 206 {object} dto.ApiResponse{}
+// @Failure 304 {object} dto.ApiResponse{}
 // @Failure 500 {object} dto.ApiResponse{}
 // @Failure 400 {object} dto.ApiResponse{}
+// @Failure 403 {object} dto.ApiResponse{}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 416 {object} dto.ApiResponse{}
 // @Router /api/v1/download/{docKey}/{docName} [get]
 func (h *handler) GetFile(c *fiber.Ctx) error {
+	docName := c.Params("docName", c.Params("*"))
+	return h.sendFile(c, fmt.Sprintf("%s/%s", c.Params("docKey"), docName), "")
+}
+
+// Integrator godoc
+// @Description  orchestrator to get document by its opaque id, the key of the document is never exposed
+// @Produce json
+// @Param id path string true "opaque id of the document"
+// @Param type query string  false "type downloaded can be empty(file),downloaded and base64" default(base64)
+// @Param filename query string false "name of the downloaded file, the uploaded filename by default"
+// @Param rendition query string false "rendition profile or preview served instead of the document"
+// @Param versionId query string false "version of document, latest when empty"
+// @Param w query int false "image width in pixel, derived from height when empty"
+// @Param h query int false "image height in pixel, derived from width when empty"
+// @Param fit query string false "how the image fit the size: contain, cover or fill" default(contain)
+// @Param format query string false "image output format: jpeg, png or webp"
+// @Param quality query int false "jpeg quality between 1 and 100" default(80)
+// @Param Range header string false "single byte range, ex: bytes=0-1023"
+// @Param Accept-Encoding header string false "compressed document is sent as is when its encoding is accepted"
+// @Param expires query int false "unix time the signed link expires at"
+// @Param kid query string false "id of the secret of the signed link"
+// @Param bind query string false "comma separated values bound to the signed link: ip, type"
+// @Param sig query string false "signature of the signed link"
+// @Param If-None-Match header string false "etag of the copy of the client, 304 when it is still current"
+// @Failure 200 {object} dto.ApiResponse{}
+// @Failure 206 {object} dto.ApiResponse{}
+// @Failure 304 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Failure 400 {object} dto.ApiResponse{}
+// @Failure 403 {object} dto.ApiResponse{}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 416 {object} dto.ApiResponse{}
+// @Router /api/v1/files/{id} [get]
+func (h *handler) GetFileById(c *fiber.Ctx) error {
+	key, err := h.usecase.ResolveDocumentId(c.Context(), c.Params("id"))
+	if err != nil {
+		log.Errorf("Error to resolve document id :%s", err.Error())
+		return errorResponse(c, err, "Failed to get document")
+	}
+	return h.sendFile(c, key, c.Params("id"))
+}
+
+// sendFile write the document as file or base64 json, id is set when the document is requested by its opaque id
+func (h *handler) sendFile(c *fiber.Ctx, key, id string) error {
 
 	typeResponse := c.Query("type")
-	response, err := h.usecase.DownloadFile(c.Context(), fmt.Sprintf("%s/%s", c.Params("docKey"), c.Params("docName")))
+	request := document.RequestDownloadDocument{
+		Key:       key,
+		Id:        id,
+		VersionId: c.Query("versionId"),
+		Width:     c.QueryInt("w"),
+		Height:    c.QueryInt("h"),
+		Fit:       c.Query("fit"),
+		Format:    strings.ToLower(c.Query("format")),
+		Quality:   c.QueryInt("quality"),
+		Rendition: c.Query("rendition"),
+	}
+	if signature := c.Query("sig"); signature != "" {
+		request.Link = &document.RequestDownloadLink{
+			Expires:     c.Query("expires"),
+			KeyId:       c.Query("kid"),
+			Bind:        c.Query("bind"),
+			Signature:   signature,
+			ClientIp:    c.IP(),
+			Disposition: typeResponse,
+		}
+	}
+	// base64 response is the decoded content as a whole
+	if typeResponse != "base64" {
+		request.AcceptEncoding = c.Get(fiber.HeaderAcceptEncoding)
+		request.Range = c.Get(fiber.HeaderRange)
+	}
+	response, err := h.usecase.DownloadFile(c.Context(), request)
 	if err != nil {
 		log.Error("Error to get file :%s", err.Error())
-		return c.Status(http.StatusInternalServerError).JSON(dto.ApiResponse{
-			Code:       constant.STATUS_CODE_GENERAL_ERROR,
-			Message:    "Failed to get document",
-			ServerTime: time.Now().Format(time.RFC3339),
-		})
+		return errorResponse(c, err, "Failed to get document")
 	}
 	defer response.Body.Close()
 
+	if response.CacheControl != nil {
+		c.Set(fiber.HeaderCacheControl, *response.CacheControl)
+	}
+	if typeResponse != "base64" {
+		setCacheHeaders(c, response)
+		// the copy of the client is still the current one, the whole document is not sent again
+		if response.ContentRange == nil && c.Fresh() {
+			return c.SendStatus(http.StatusNotModified)
+		}
+	}
+
 	var buf bytes.Buffer
 	tee := io.TeeReader(response.Body, &buf)
 	_, err = io.Copy(c.Response().BodyWriter(), tee)
@@ -182,15 +313,37 @@ func (h *handler) GetFile(c *fiber.Ctx) error {
 		})
 	}
 
+	if response.ChecksumSHA256 != nil {
+		c.Set("Digest", "sha-256="+*response.ChecksumSHA256)
+		c.Set("Repr-Digest", "sha-256=:"+*response.ChecksumSHA256+":")
+	} else if response.ChecksumCRC32C != nil {
+		c.Set("Digest", "crc32c="+*response.ChecksumCRC32C)
+	}
+
 	if typeResponse != "base64" {
 		diposition := "inline"
 		if typeResponse == "download" {
 			diposition = "attachment"
 		}
 		c.Status(http.StatusOK)
-		c.Append("Content-Type", *response.ContentType)
-		c.Append("Content-Length", fmt.Sprintf("%d", response.ContentLength))
-		c.Append("Content-Disposition", fmt.Sprintf("%s; filename=\"%s\"", diposition, c.Params("docName")))
+		if response.ContentRange != nil {
+			c.Status(http.StatusPartialContent)
+			c.Set(fiber.HeaderContentRange, *response.ContentRange)
+		}
+		c.Set(fiber.HeaderAcceptRanges, "bytes")
+		if response.ContentEncoding != nil {
+			c.Set(fiber.HeaderContentEncoding, *response.ContentEncoding)
+		}
+		// an object stored without content type is served as S3 would, never sniffed by the client
+		contentType := fiber.MIMEOctetStream
+		if response.ContentType != nil {
+			contentType = *response.ContentType
+		}
+		c.Set(fiber.HeaderContentType, contentType)
+		if response.ContentLength != nil {
+			c.Append("Content-Length", fmt.Sprintf("%d", *response.ContentLength))
+		}
+		c.Set(fiber.HeaderContentDisposition, utils.ContentDisposition(diposition, downloadFilename(c, key, request.Format, response)))
 
 		return nil
 	} else {
@@ -205,3 +358,88 @@ func (h *handler) GetFile(c *fiber.Ctx) error {
 	}
 
 }
+
+// Integrator godoc
+// @Description  orchestrator to delete document in s3
+// @Produce json
+// @Param docKey path string true "document key" default(folder-in-s3)
+// @Param docName path string true "document name" default(example.png)
+// @Success 200 {object} dto.ApiResponse{}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Router /api/v1/documents/{docKey}/{docName} [delete]
+func (h *handler) DeleteFile(c *fiber.Ctx) error {
+
+	err := h.usecase.DeleteFile(c.Context(), fmt.Sprintf("%s/%s", c.Params("docKey"), c.Params("docName")))
+	if err != nil {
+		log.Errorf("Error to delete file :%s", err.Error())
+		return errorResponse(c, err, "Failed to delete document")
+	}
+	defer log.Info("Document deleted successfully", "document_key", c.Params("docKey"), "document_name", c.Params("docName"))
+
+	return c.Status(http.StatusOK).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Document deleted successfully",
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
+
+// Integrator godoc
+// @Description  orchestrator to get metadata of document in s3, pdf info is returned for pdf document
+// @Produce json
+// @Param docKey path string true "document key" default(folder-in-s3)
+// @Param docName path string true "document name" default(example.pdf)
+// @Success 200 {object} dto.ApiResponse{data=document.ResponseDocumentMetadata}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Router /api/v1/documents/{docKey}/{docName} [get]
+func (h *handler) GetMetadata(c *fiber.Ctx) error {
+
+	response, err := h.usecase.GetMetadata(c.Context(), fmt.Sprintf("%s/%s", c.Params("docKey"), c.Params("docName")))
+	if err != nil {
+		log.Errorf("Error to get metadata :%s", err.Error())
+		return errorResponse(c, err, "Failed to get document metadata")
+	}
+
+	return c.Status(http.StatusOK).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Document metadata get successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
+
+// setCacheHeaders write the validators and expiration of the document so browsers and CDN can cache it,
+// Vary is set when the content depend on Accept-Encoding
+func setCacheHeaders(c *fiber.Ctx, response *s3.GetObjectOutput) {
+	if response.ETag != nil {
+		c.Set(fiber.HeaderETag, *response.ETag)
+	}
+	if response.LastModified != nil {
+		c.Set(fiber.HeaderLastModified, response.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if response.ExpiresString != nil {
+		c.Set(fiber.HeaderExpires, *response.ExpiresString)
+	}
+	if vary, _ := response.ResultMetadata.Get(document.ResultVary{}).(string); vary != "" {
+		c.Set(fiber.HeaderVary, vary)
+	} else if response.ContentEncoding != nil {
+		c.Set(fiber.HeaderVary, fiber.HeaderAcceptEncoding)
+	}
+}
+
+// downloadFilename is the filename query of the client, or else the name the document was uploaded with,
+// or else the base of its key. The extension follow a converted format
+func downloadFilename(c *fiber.Ctx, key, format string, response *s3.GetObjectOutput) string {
+	if filename := c.Query("filename"); filename != "" {
+		return filename
+	}
+	filename, _ := response.ResultMetadata.Get(document.ResultFilename{}).(string)
+	if filename == "" {
+		filename = path.Base(key)
+	}
+	if format != "" {
+		filename = strings.TrimSuffix(filename, filepath.Ext(filename)) + "." + format
+	}
+	return filename
+}
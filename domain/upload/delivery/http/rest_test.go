@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -103,6 +104,59 @@ func TestUploadBase64(t *testing.T) {
 				statusCode: fiber.StatusInternalServerError,
 			},
 		},
+		{
+			name: "upload checksum mismatch",
+			args: args{
+				request: `{
+					"document_name": "test",
+					"document_key": "folder-in-s3",
+					"document_base64": "ZGF0YQ==",
+					"checksum_sha256": "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+				}`,
+			},
+			prepare: func(args args) {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadBase64", mock.Anything, mock.Anything).Return(document.ResponseUploadDocument{}, document.ErrChecksumMismatch).Once()
+			},
+			expected: expected{
+				statusCode: fiber.StatusBadRequest,
+			},
+		},
+		{
+			name: "upload invalid base64",
+			args: args{
+				request: `{
+					"document_name": "test",
+					"document_key": "folder-in-s3",
+					"document_base64": "ZGF0*YQ=="
+				}`,
+			},
+			prepare: func(args args) {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadBase64", mock.Anything, mock.Anything).Return(document.ResponseUploadDocument{}, fmt.Errorf("%w: illegal character '*' at offset 4", document.ErrInvalidBase64)).Once()
+			},
+			expected: expected{
+				statusCode: fiber.StatusBadRequest,
+			},
+		},
+		{
+			name: "upload conflict",
+			args: args{
+				request: `{
+					"document_name": "test",
+					"document_key": "folder-in-s3",
+					"document_base64": "ZGF0YQ==",
+					"overwrite": "reject"
+				}`,
+			},
+			prepare: func(args args) {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadBase64", mock.Anything, mock.Anything).Return(document.ResponseUploadDocument{}, document.ErrDocumentAlreadyExists).Once()
+			},
+			expected: expected{
+				statusCode: fiber.StatusConflict,
+			},
+		},
 		{
 			name: "upload success",
 			args: args{
@@ -220,6 +274,29 @@ func TestUploadFile_Usecase(t *testing.T) {
 				statusCode: fiber.StatusInternalServerError,
 			},
 		},
+		{
+			name: "upload conflict",
+			args: args{
+				request: []struct {
+					key   string
+					value string
+				}{
+					{key: "document_key", value: "test-key"},
+					{key: "document_name", value: "test"},
+					{key: "overwrite", value: "reject"},
+				},
+				isRequestFile: true,
+			},
+			prepare: func(args args) {
+
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadFile", mock.Anything, mock.Anything, mock.Anything).
+					Return(document.ResponseUploadDocument{}, document.ErrDocumentAlreadyExists).Once()
+			},
+			expected: expected{
+				statusCode: fiber.StatusConflict,
+			},
+		},
 		{
 			name: "upload success",
 			args: args{
@@ -244,6 +321,32 @@ func TestUploadFile_Usecase(t *testing.T) {
 				statusCode: fiber.StatusCreated,
 			},
 		},
+		{
+			name: "upload with tags and metadata",
+			args: args{
+				request: []struct {
+					key   string
+					value string
+				}{
+					{key: "document_key", value: "test-key"},
+					{key: "document_name", value: "test"},
+					{key: "tags", value: "invoice"},
+					{key: "tags", value: "finance"},
+					{key: "metadata[owner]", value: "alice"},
+				},
+				isRequestFile: true,
+			},
+			prepare: func(args args) {
+
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("UploadFile", mock.Anything, mock.MatchedBy(func(request document.RequestUploadDocumentFile) bool {
+					return len(request.Tags) == 2 && request.Tags[1] == "finance" && request.Metadata["owner"] == "alice"
+				}), mock.Anything).Return(document.ResponseUploadDocument{}, nil).Once()
+			},
+			expected: expected{
+				statusCode: fiber.StatusCreated,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -387,6 +490,20 @@ func TestGetFile_SuccessInline(t *testing.T) {
 				statusCode: fiber.StatusInternalServerError,
 			},
 		},
+		{
+			name: "GetFile_NotFound",
+			args: args{
+				docKey:       "abc",
+				docName:      "file.txt",
+				typeDocument: "base64",
+			},
+			prepare: func(a args) {
+				mockUsecase.On("DownloadFile", mock.Anything, mock.Anything).Return(nil, document.ErrDocumentNotFound).Once()
+			},
+			expected: expected{
+				statusCode: fiber.StatusNotFound,
+			},
+		},
 		{
 			name: "GetFile_ErrorIoCopy",
 			args: args{
@@ -431,3 +548,341 @@ func TestGetFile_SuccessInline(t *testing.T) {
 	}
 
 }
+
+func TestDeleteFile(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	tests := []struct {
+		name       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name: "DeleteFile_Success",
+			prepare: func() {
+				mockUsecase.On("DeleteFile", mock.Anything, "abc/file.txt").Return(nil).Once()
+			},
+			statusCode: fiber.StatusOK,
+		},
+		{
+			name: "DeleteFile_NotFound",
+			prepare: func() {
+				mockUsecase.On("DeleteFile", mock.Anything, "abc/file.txt").Return(document.ErrDocumentNotFound).Once()
+			},
+			statusCode: fiber.StatusNotFound,
+		},
+		{
+			name: "DeleteFile_Error",
+			prepare: func() {
+				mockUsecase.On("DeleteFile", mock.Anything, "abc/file.txt").Return(errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Delete("/documents/:docKey/:docName", handler.DeleteFile)
+
+			req := httptest.NewRequest(http.MethodDelete, "/documents/abc/file.txt", nil)
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestGetFileById(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+	id := "0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70"
+
+	app := fiber.New()
+	app.Get("/files/:id", handler.GetFileById)
+
+	mockUsecase.On("ResolveDocumentId", mock.Anything, "unknown").Return("", document.ErrDocumentNotFound).Once()
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/files/unknown", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	mockUsecase.On("ResolveDocumentId", mock.Anything, id).Return("abc/sub/file.txt", nil).Once()
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{Key: "abc/sub/file.txt", Id: id}).Return(&s3.GetObjectOutput{
+		Body:        io.NopCloser(bytes.NewReader([]byte("Hello Fiber"))),
+		ContentType: aws.String("text/plain"),
+	}, nil).Once()
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/files/"+id+"?type=download", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, `attachment; filename="file.txt"`, resp.Header.Get("Content-Disposition"))
+}
+
+func TestGetFile_Digest(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{Key: "abc/file.txt"}).Return(&s3.GetObjectOutput{
+		Body:           io.NopCloser(bytes.NewReader([]byte("Hello Fiber"))),
+		ContentType:    aws.String("text/plain"),
+		ChecksumSHA256: aws.String("cm34vMIcsxnd4DHhCjq0DuXOSXnO8BRRqb40H+yOgVM="),
+	}, nil).Once()
+
+	app := fiber.New()
+	app.Get("/file/:docKey/:docName", handler.GetFile)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/file/abc/file.txt", nil))
+
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "sha-256=cm34vMIcsxnd4DHhCjq0DuXOSXnO8BRRqb40H+yOgVM=", resp.Header.Get("Digest"))
+	require.Equal(t, "sha-256=:cm34vMIcsxnd4DHhCjq0DuXOSXnO8BRRqb40H+yOgVM=:", resp.Header.Get("Repr-Digest"))
+}
+
+func TestGetFile_NoContentType(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{Key: "abc/file"}).Return(&s3.GetObjectOutput{
+		Body:          io.NopCloser(bytes.NewReader([]byte("Hello Fiber"))),
+		ContentLength: aws.Int64(11),
+	}, nil).Once()
+
+	app := fiber.New()
+	app.Get("/file/:docKey/:docName", handler.GetFile)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/file/abc/file", nil))
+
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, fiber.MIMEOctetStream, resp.Header.Get("Content-Type"))
+}
+
+func TestGetFile_CacheControl(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{Key: "abc/file.txt"}).Return(&s3.GetObjectOutput{
+		Body:         io.NopCloser(bytes.NewReader([]byte("Hello Fiber"))),
+		ContentType:  aws.String("text/plain"),
+		CacheControl: aws.String("public, max-age=86400"),
+	}, nil).Once()
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{Key: "abc/private.txt"}).Return(nil, document.ErrInvalidSignature).Once()
+
+	app := fiber.New()
+	app.Get("/file/:docKey/:docName", handler.GetFile)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/file/abc/file.txt", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "public, max-age=86400", resp.Header.Get("Cache-Control"))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/file/abc/private.txt", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestGetFile_CacheHeaders(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	output := func() *s3.GetObjectOutput {
+		output := &s3.GetObjectOutput{
+			Body:          io.NopCloser(bytes.NewReader([]byte("Hello Fiber"))),
+			ContentType:   aws.String("text/plain"),
+			ETag:          aws.String(`W/"abc"`),
+			LastModified:  aws.Time(lastModified),
+			ExpiresString: aws.String("Wed, 21 Oct 2026 07:28:00 GMT"),
+		}
+		output.ResultMetadata.Set(document.ResultVary{}, "Accept-Encoding")
+		return output
+	}
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{Key: "abc/file.txt"}).Return(output(), nil).Once()
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{Key: "abc/file.txt"}).Return(output(), nil).Once()
+
+	app := fiber.New()
+	app.Get("/file/:docKey/:docName", handler.GetFile)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/file/abc/file.txt", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, `W/"abc"`, resp.Header.Get("ETag"))
+	require.Equal(t, "Tue, 02 Jan 2024 03:04:05 GMT", resp.Header.Get("Last-Modified"))
+	require.Equal(t, "Wed, 21 Oct 2026 07:28:00 GMT", resp.Header.Get("Expires"))
+	require.Equal(t, "Accept-Encoding", resp.Header.Get("Vary"))
+
+	req := httptest.NewRequest(http.MethodGet, "/file/abc/file.txt", nil)
+	req.Header.Set("If-None-Match", `W/"abc"`)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	require.Empty(t, body)
+}
+
+func TestGetFile_ContentDisposition(t *testing.T) {
+
+	output := func(filename string) *s3.GetObjectOutput {
+		output := &s3.GetObjectOutput{
+			Body:        io.NopCloser(bytes.NewReader([]byte("Hello Fiber"))),
+			ContentType: aws.String("text/plain"),
+		}
+		if filename != "" {
+			output.ResultMetadata.Set(document.ResultFilename{}, filename)
+		}
+		return output
+	}
+
+	tests := []struct {
+		name     string
+		url      string
+		filename string
+		expected string
+	}{
+		{name: "key name", url: "/file/abc/file.txt", expected: `inline; filename="file.txt"`},
+		{name: "uploaded filename", url: "/file/abc/file.txt?type=download", filename: "Quarterly report.txt", expected: `attachment; filename="Quarterly report.txt"`},
+		{name: "non ascii filename", url: "/file/abc/file.txt", filename: "résumé.txt", expected: `inline; filename="r_sum_.txt"; filename*=UTF-8''r%C3%A9sum%C3%A9.txt`},
+		{name: "filename query", url: "/file/abc/file.txt?filename=%22evil%22%0D%0AX-Injected%3A%201.txt", filename: "résumé.txt", expected: `inline; filename="_evil_X-Injected: 1.txt"; filename*=UTF-8''%22evil%22X-Injected%3A%201.txt`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler, mockUsecase, _ := initRestUnitTest(t)
+			mockUsecase.On("DownloadFile", mock.Anything, mock.Anything).Return(output(test.filename), nil).Once()
+
+			app := fiber.New()
+			app.Get("/file/:docKey/:docName", handler.GetFile)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, test.url, nil))
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusOK, resp.StatusCode)
+			require.Equal(t, test.expected, resp.Header.Get("Content-Disposition"))
+			require.Empty(t, resp.Header.Get("X-Injected"))
+		})
+	}
+}
+
+func TestGetFile_Transform(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{
+		Key:     "abc/photo.png",
+		Width:   200,
+		Height:  100,
+		Fit:     "cover",
+		Format:  "webp",
+		Quality: 70,
+	}).Return(&s3.GetObjectOutput{
+		Body:        io.NopCloser(bytes.NewReader([]byte("webp"))),
+		ContentType: aws.String("image/webp"),
+	}, nil).Once()
+	mockUsecase.On("DownloadFile", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: size 5000x0 is not allowed", document.ErrInvalidTransformation)).Once()
+
+	app := fiber.New()
+	app.Get("/file/:docKey/:docName", handler.GetFile)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/file/abc/photo.png?w=200&h=100&fit=cover&format=WEBP&quality=70", nil))
+
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Content-Type"), "image/webp")
+	require.Equal(t, `inline; filename="photo.webp"`, resp.Header.Get("Content-Disposition"))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/file/abc/photo.png?w=5000", nil))
+
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGetMetadata(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	tests := []struct {
+		name       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name: "GetMetadata_Success",
+			prepare: func() {
+				mockUsecase.On("GetMetadata", mock.Anything, "abc/file.pdf").Return(document.ResponseDocumentMetadata{
+					Key: "abc/file.pdf",
+					Pdf: &document.ResponsePdfInfo{PageCount: 2},
+				}, nil).Once()
+			},
+			statusCode: fiber.StatusOK,
+		},
+		{
+			name: "GetMetadata_NotFound",
+			prepare: func() {
+				mockUsecase.On("GetMetadata", mock.Anything, "abc/file.pdf").Return(document.ResponseDocumentMetadata{}, document.ErrDocumentNotFound).Once()
+			},
+			statusCode: fiber.StatusNotFound,
+		},
+		{
+			name: "GetMetadata_Error",
+			prepare: func() {
+				mockUsecase.On("GetMetadata", mock.Anything, "abc/file.pdf").Return(document.ResponseDocumentMetadata{}, errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Get("/documents/:docKey/:docName", handler.GetMetadata)
+
+			req := httptest.NewRequest(http.MethodGet, "/documents/abc/file.pdf", nil)
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestGetFile_Compressed(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{
+		Key:            "abc/report.csv",
+		AcceptEncoding: "gzip",
+		Range:          "bytes=0-3",
+	}).Return(&s3.GetObjectOutput{
+		Body:            io.NopCloser(bytes.NewReader([]byte("\x1f\x8b\x08\x00"))),
+		ContentType:     aws.String("text/csv"),
+		ContentEncoding: aws.String("gzip"),
+		ContentLength:   aws.Int64(4),
+		ContentRange:    aws.String("bytes 0-3/120"),
+	}, nil).Once()
+	mockUsecase.On("DownloadFile", mock.Anything, document.RequestDownloadDocument{Key: "abc/report.csv", Range: "bytes=500-"}).
+		Return(nil, fmt.Errorf("%w: bytes=500-", document.ErrRangeNotSatisfiable)).Once()
+
+	app := fiber.New()
+	app.Get("/file/:docKey/:docName", handler.GetFile)
+
+	req := httptest.NewRequest(http.MethodGet, "/file/abc/report.csv", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Range", "bytes=0-3")
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	require.Equal(t, "bytes 0-3/120", resp.Header.Get("Content-Range"))
+	require.Equal(t, "Accept-Encoding", resp.Header.Get("Vary"))
+
+	req = httptest.NewRequest(http.MethodGet, "/file/abc/report.csv", nil)
+	req.Header.Set("Range", "bytes=500-")
+	resp, err = app.Test(req)
+
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+}
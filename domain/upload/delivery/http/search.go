@@ -0,0 +1,91 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/models/dto"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Integrator godoc
+// @Description  orchestrator to search documents recorded in the metadata database, every filter must match
+// @Produce json
+// @Param bucket query string false "bucket listed in ALLOWED_BUCKETS, BUCKET_NAME when empty"
+// @Param name query string false "case insensitive substring of the key" default(invoice)
+// @Param content_type query string false "exact content type, or image/* for every subtype"
+// @Param min_size query int false "min size in bytes"
+// @Param max_size query int false "max size in bytes"
+// @Param uploaded_after query string false "RFC 3339 date time, included" default(2024-01-01T00:00:00Z)
+// @Param uploaded_before query string false "RFC 3339 date time, excluded"
+// @Param tag query []string false "tag on the document, repeat it to require several" collectionFormat(multi)
+// @Param metadata query []string false "name:value custom metadata, repeat it to require several" collectionFormat(multi)
+// @Param sort query string false "key, size or created_at, prefixed by - for descending order" default(-created_at)
+// @Param limit query int false "max documents, up to 1000" default(50)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} dto.ApiResponse{data=document.ResponseSearchDocument}
+// @Failure 400 {object} dto.ApiResponse{error=dto.ErrorValidation}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Failure 501 {object} dto.ApiResponse{}
+// @Router /api/v1/documents/search [get]
+func (h *handler) SearchDocuments(c *fiber.Ctx) error {
+	var request document.RequestSearchDocument
+
+	if err := c.QueryParser(&request); err != nil {
+		log.Error("Error parsing request query")
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_PARSING_REQUEST,
+			Message:    "Failed to parse request query",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	if err := h.validator.Validate(&request); err != nil {
+		log.Error("Validation error", err)
+		return c.Status(http.StatusBadRequest).JSON(dto.ApiResponse{
+			Code:       constant.STATUS_CODE_VALIDATION_ERROR,
+			Errors:     utils.UnwrapValidation(err),
+			Message:    "Validation failed",
+			ServerTime: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	response, err := h.usecase.SearchDocuments(c.Context(), request)
+	if err != nil {
+		log.Errorf("Error to search documents :%s", err.Error())
+		return errorResponse(c, err, "Failed to search documents")
+	}
+
+	return c.Status(http.StatusOK).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Documents searched successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
+
+// formAttributes read the repeated "tags" fields and the "metadata[name]" fields of a multipart form
+func formAttributes(c *fiber.Ctx) (tags []string, metadata map[string]string) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, nil
+	}
+
+	tags = form.Value["tags"]
+	for field, values := range form.Value {
+		name, ok := strings.CutPrefix(field, "metadata[")
+		if !ok || !strings.HasSuffix(name, "]") || len(values) == 0 {
+			continue
+		}
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+		metadata[strings.TrimSuffix(name, "]")] = values[0]
+	}
+	return tags, metadata
+}
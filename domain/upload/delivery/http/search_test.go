@@ -0,0 +1,83 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchDocuments(t *testing.T) {
+
+	handler, mockUsecase, mockValidator := initRestUnitTest(t)
+
+	request := document.RequestSearchDocument{
+		Name:     "invoice",
+		MinSize:  10,
+		Tags:     []string{"finance", "invoice"},
+		Metadata: []string{"owner:alice,bob"},
+		Sort:     "-size",
+		Cursor:   "abc",
+	}
+	query := "/documents/search?name=invoice&min_size=10&tag=finance&tag=invoice&metadata=owner:alice,bob&sort=-size&cursor=abc"
+
+	tests := []struct {
+		name       string
+		path       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name:       "ParseError",
+			path:       "/documents/search?min_size=big",
+			prepare:    func() {},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "Success",
+			path: query,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("SearchDocuments", mock.Anything, request).Return(document.ResponseSearchDocument{NextCursor: "def"}, nil).Once()
+			},
+			statusCode: fiber.StatusOK,
+		},
+		{
+			name: "InvalidSearch",
+			path: query,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("SearchDocuments", mock.Anything, request).Return(document.ResponseSearchDocument{}, document.ErrInvalidSearch).Once()
+			},
+			statusCode: fiber.StatusBadRequest,
+		},
+		{
+			name: "Error",
+			path: query,
+			prepare: func() {
+				mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+				mockUsecase.On("SearchDocuments", mock.Anything, request).Return(document.ResponseSearchDocument{}, errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Get("/documents/search", handler.SearchDocuments)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/dto"
+	"aws-s3-bucket/shared/constant"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// Integrator godoc
+// @Description  orchestrator to list versions of document in versioned bucket
+// @Produce json
+// @Param docKey path string true "document key" default(folder-in-s3)
+// @Param docName path string true "document name" default(example.png)
+// @Success 200 {object} dto.ApiResponse{data=[]document.ResponseDocumentVersion}
+// @Failure 403 {object} dto.ApiResponse{}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Router /api/v1/documents/{docKey}/{docName}/versions [get]
+func (h *handler) ListVersions(c *fiber.Ctx) error {
+
+	response, err := h.usecase.ListVersions(c.Context(), fmt.Sprintf("%s/%s", c.Params("docKey"), c.Params("docName")))
+	if err != nil {
+		log.Errorf("Error to list versions :%s", err.Error())
+		return errorResponse(c, err, "Failed to list document versions")
+	}
+
+	return c.Status(http.StatusOK).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Document versions get successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
+
+// Integrator godoc
+// @Description  orchestrator to restore old version of document as the latest version
+// @Produce json
+// @Param docKey path string true "document key" default(folder-in-s3)
+// @Param docName path string true "document name" default(example.png)
+// @Param versionId path string true "version to restore"
+// @Success 200 {object} dto.ApiResponse{data=document.ResponseUploadDocument}
+// @Failure 404 {object} dto.ApiResponse{}
+// @Failure 500 {object} dto.ApiResponse{}
+// @Router /api/v1/documents/{docKey}/{docName}/versions/{versionId}/restore [post]
+func (h *handler) RestoreVersion(c *fiber.Ctx) error {
+
+	response, err := h.usecase.RestoreVersion(c.Context(), fmt.Sprintf("%s/%s", c.Params("docKey"), c.Params("docName")), c.Params("versionId"))
+	if err != nil {
+		log.Errorf("Error to restore version :%s", err.Error())
+		return errorResponse(c, err, "Failed to restore document version")
+	}
+	defer log.Info("Document version restored successfully", "document_key", c.Params("docKey"), "document_name", c.Params("docName"), "version_id", c.Params("versionId"))
+
+	return c.Status(http.StatusOK).JSON(dto.ApiResponse{
+		Code:       constant.STATUS_CODE_GENERAL_SUCCESS,
+		Message:    "Document version restored successfully",
+		Data:       response,
+		ServerTime: time.Now().Format(time.RFC3339),
+	})
+}
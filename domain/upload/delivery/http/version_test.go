@@ -0,0 +1,105 @@
+package delivery
+
+import (
+	"aws-s3-bucket/models/document"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListVersions(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	tests := []struct {
+		name       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name: "ListVersions_Success",
+			prepare: func() {
+				mockUsecase.On("ListVersions", mock.Anything, "abc/file.txt").Return([]document.ResponseDocumentVersion{{VersionId: "v1"}}, nil).Once()
+			},
+			statusCode: fiber.StatusOK,
+		},
+		{
+			name: "ListVersions_NotFound",
+			prepare: func() {
+				mockUsecase.On("ListVersions", mock.Anything, "abc/file.txt").Return(nil, document.ErrDocumentNotFound).Once()
+			},
+			statusCode: fiber.StatusNotFound,
+		},
+		{
+			name: "ListVersions_Error",
+			prepare: func() {
+				mockUsecase.On("ListVersions", mock.Anything, "abc/file.txt").Return(nil, errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Get("/documents/:docKey/:docName/versions", handler.ListVersions)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/documents/abc/file.txt/versions", nil))
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestRestoreVersion(t *testing.T) {
+
+	handler, mockUsecase, _ := initRestUnitTest(t)
+
+	tests := []struct {
+		name       string
+		prepare    func()
+		statusCode int
+	}{
+		{
+			name: "RestoreVersion_Success",
+			prepare: func() {
+				mockUsecase.On("RestoreVersion", mock.Anything, "abc/file.txt", "v1").Return(document.ResponseUploadDocument{VersionId: "v2"}, nil).Once()
+			},
+			statusCode: fiber.StatusOK,
+		},
+		{
+			name: "RestoreVersion_NotFound",
+			prepare: func() {
+				mockUsecase.On("RestoreVersion", mock.Anything, "abc/file.txt", "v1").Return(document.ResponseUploadDocument{}, document.ErrDocumentNotFound).Once()
+			},
+			statusCode: fiber.StatusNotFound,
+		},
+		{
+			name: "RestoreVersion_Error",
+			prepare: func() {
+				mockUsecase.On("RestoreVersion", mock.Anything, "abc/file.txt", "v1").Return(document.ResponseUploadDocument{}, errors.New("connection error")).Once()
+			},
+			statusCode: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			app := fiber.New()
+			app.Post("/documents/:docKey/:docName/versions/:versionId/restore", handler.RestoreVersion)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/documents/abc/file.txt/versions/v1/restore", nil))
+
+			require.NoError(t, err)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+		})
+	}
+}
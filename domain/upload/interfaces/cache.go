@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"aws-s3-bucket/models/document"
+	"io"
+)
+
+type CacheInterface interface {
+	// Admit tell whether an object of the size can be cached
+	Admit(size int64) bool
+	Get(bucket, key string) (object document.CachedObject, body io.ReadCloser, ok bool)
+	// Set read the content until EOF and return it from the cache
+	Set(bucket, key string, object document.CachedObject, content io.Reader) (body io.ReadCloser, err error)
+	// Revalidated mark the object fresh again, S3 answered it is not modified
+	Revalidated(bucket, key string)
+	Delete(bucket, key string)
+}
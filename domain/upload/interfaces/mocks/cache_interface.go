@@ -0,0 +1,125 @@
+// Code generated by mockery v2.50.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	document "aws-s3-bucket/models/document"
+
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CacheInterface is an autogenerated mock type for the CacheInterface type
+type CacheInterface struct {
+	mock.Mock
+}
+
+// Admit provides a mock function with given fields: size
+func (_m *CacheInterface) Admit(size int64) bool {
+	ret := _m.Called(size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Admit")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(int64) bool); ok {
+		r0 = rf(size)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: bucket, key
+func (_m *CacheInterface) Delete(bucket string, key string) {
+	_m.Called(bucket, key)
+}
+
+// Get provides a mock function with given fields: bucket, key
+func (_m *CacheInterface) Get(bucket string, key string) (document.CachedObject, io.ReadCloser, bool) {
+	ret := _m.Called(bucket, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 document.CachedObject
+	var r1 io.ReadCloser
+	var r2 bool
+	if rf, ok := ret.Get(0).(func(string, string) (document.CachedObject, io.ReadCloser, bool)); ok {
+		return rf(bucket, key)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) document.CachedObject); ok {
+		r0 = rf(bucket, key)
+	} else {
+		r0 = ret.Get(0).(document.CachedObject)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) io.ReadCloser); ok {
+		r1 = rf(bucket, key)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string) bool); ok {
+		r2 = rf(bucket, key)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	return r0, r1, r2
+}
+
+// Revalidated provides a mock function with given fields: bucket, key
+func (_m *CacheInterface) Revalidated(bucket string, key string) {
+	_m.Called(bucket, key)
+}
+
+// Set provides a mock function with given fields: bucket, key, object, content
+func (_m *CacheInterface) Set(bucket string, key string, object document.CachedObject, content io.Reader) (io.ReadCloser, error) {
+	ret := _m.Called(bucket, key, object, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, document.CachedObject, io.Reader) (io.ReadCloser, error)); ok {
+		return rf(bucket, key, object, content)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, document.CachedObject, io.Reader) io.ReadCloser); ok {
+		r0 = rf(bucket, key, object, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, document.CachedObject, io.Reader) error); ok {
+		r1 = rf(bucket, key, object, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCacheInterface creates a new instance of CacheInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCacheInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CacheInterface {
+	mock := &CacheInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,67 @@
+// Code generated by mockery v2.50.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	s3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PresignInterface is an autogenerated mock type for the PresignInterface type
+type PresignInterface struct {
+	mock.Mock
+}
+
+// PresignGetObject provides a mock function with given fields: ctx, params, optFns
+func (_m *PresignInterface) PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PresignGetObject")
+	}
+
+	var r0 *v4.PresignedHTTPRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *s3.GetObjectInput, ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *s3.GetObjectInput, ...func(*s3.PresignOptions)) *v4.PresignedHTTPRequest); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v4.PresignedHTTPRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *s3.GetObjectInput, ...func(*s3.PresignOptions)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPresignInterface creates a new instance of PresignInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPresignInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PresignInterface {
+	mock := &PresignInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
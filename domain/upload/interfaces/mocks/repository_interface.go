@@ -0,0 +1,134 @@
+// Code generated by mockery v2.50.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	document "aws-s3-bucket/models/document"
+	context "context"
+
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RepositoryInterface is an autogenerated mock type for the RepositoryInterface type
+type RepositoryInterface struct {
+	mock.Mock
+}
+
+// DeleteDocument provides a mock function with given fields: ctx, bucket, key, deletedAt
+func (_m *RepositoryInterface) DeleteDocument(ctx context.Context, bucket string, key string, deletedAt time.Time) error {
+	ret := _m.Called(ctx, bucket, key, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDocument")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, bucket, key, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListDocuments provides a mock function with given fields: ctx, filter
+func (_m *RepositoryInterface) ListDocuments(ctx context.Context, filter document.RecordFilter) ([]document.Record, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDocuments")
+	}
+
+	var r0 []document.Record
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RecordFilter) ([]document.Record, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RecordFilter) []document.Record); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]document.Record)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RecordFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveDocument provides a mock function with given fields: ctx, record
+func (_m *RepositoryInterface) SaveDocument(ctx context.Context, record document.Record) error {
+	ret := _m.Called(ctx, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDocument")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.Record) error); ok {
+		r0 = rf(ctx, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchDocuments provides a mock function with given fields: ctx, filter
+func (_m *RepositoryInterface) SearchDocuments(ctx context.Context, filter document.SearchFilter) ([]document.Record, string, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchDocuments")
+	}
+
+	var r0 []document.Record
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.SearchFilter) ([]document.Record, string, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.SearchFilter) []document.Record); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]document.Record)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.SearchFilter) string); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, document.SearchFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewRepositoryInterface creates a new instance of RepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RepositoryInterface {
+	mock := &RepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
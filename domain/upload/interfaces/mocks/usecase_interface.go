@@ -6,11 +6,11 @@ import (
 	document "aws-s3-bucket/models/document"
 	context "context"
 
-	mock "github.com/stretchr/testify/mock"
-
+	io "io"
 	multipart "mime/multipart"
 
 	s3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	mock "github.com/stretchr/testify/mock"
 )
 
 // UsecaseInterface is an autogenerated mock type for the UsecaseInterface type
@@ -18,24 +18,156 @@ type UsecaseInterface struct {
 	mock.Mock
 }
 
-// DownloadFile provides a mock function with given fields: ctx, fileIdentifier
-func (_m *UsecaseInterface) DownloadFile(ctx context.Context, fileIdentifier string) (*s3.GetObjectOutput, error) {
+// CopyDocument provides a mock function with given fields: ctx, request
+func (_m *UsecaseInterface) CopyDocument(ctx context.Context, request document.RequestCopyDocument) (document.ResponseUploadDocument, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CopyDocument")
+	}
+
+	var r0 document.ResponseUploadDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestCopyDocument) (document.ResponseUploadDocument, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestCopyDocument) document.ResponseUploadDocument); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(document.ResponseUploadDocument)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestCopyDocument) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteFile provides a mock function with given fields: ctx, fileIdentifier
+func (_m *UsecaseInterface) DeleteFile(ctx context.Context, fileIdentifier string) error {
 	ret := _m.Called(ctx, fileIdentifier)
 
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, fileIdentifier)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DownloadFile provides a mock function with given fields: ctx, request
+func (_m *UsecaseInterface) DownloadFile(ctx context.Context, request document.RequestDownloadDocument) (*s3.GetObjectOutput, error) {
+	ret := _m.Called(ctx, request)
+
 	if len(ret) == 0 {
 		panic("no return value specified for DownloadFile")
 	}
 
 	var r0 *s3.GetObjectOutput
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*s3.GetObjectOutput, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestDownloadDocument) (*s3.GetObjectOutput, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestDownloadDocument) *s3.GetObjectOutput); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*s3.GetObjectOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestDownloadDocument) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMetadata provides a mock function with given fields: ctx, fileIdentifier
+func (_m *UsecaseInterface) GetMetadata(ctx context.Context, fileIdentifier string) (document.ResponseDocumentMetadata, error) {
+	ret := _m.Called(ctx, fileIdentifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMetadata")
+	}
+
+	var r0 document.ResponseDocumentMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (document.ResponseDocumentMetadata, error)); ok {
 		return rf(ctx, fileIdentifier)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *s3.GetObjectOutput); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) document.ResponseDocumentMetadata); ok {
+		r0 = rf(ctx, fileIdentifier)
+	} else {
+		r0 = ret.Get(0).(document.ResponseDocumentMetadata)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, fileIdentifier)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDocuments provides a mock function with given fields: ctx, request
+func (_m *UsecaseInterface) ListDocuments(ctx context.Context, request document.RequestListDocument) (document.ResponseListDocument, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDocuments")
+	}
+
+	var r0 document.ResponseListDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestListDocument) (document.ResponseListDocument, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestListDocument) document.ResponseListDocument); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(document.ResponseListDocument)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestListDocument) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListVersions provides a mock function with given fields: ctx, fileIdentifier
+func (_m *UsecaseInterface) ListVersions(ctx context.Context, fileIdentifier string) ([]document.ResponseDocumentVersion, error) {
+	ret := _m.Called(ctx, fileIdentifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListVersions")
+	}
+
+	var r0 []document.ResponseDocumentVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]document.ResponseDocumentVersion, error)); ok {
+		return rf(ctx, fileIdentifier)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []document.ResponseDocumentVersion); ok {
 		r0 = rf(ctx, fileIdentifier)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*s3.GetObjectOutput)
+			r0 = ret.Get(0).([]document.ResponseDocumentVersion)
 		}
 	}
 
@@ -48,6 +180,202 @@ func (_m *UsecaseInterface) DownloadFile(ctx context.Context, fileIdentifier str
 	return r0, r1
 }
 
+// MoveDocument provides a mock function with given fields: ctx, request
+func (_m *UsecaseInterface) MoveDocument(ctx context.Context, request document.RequestCopyDocument) (document.ResponseUploadDocument, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveDocument")
+	}
+
+	var r0 document.ResponseUploadDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestCopyDocument) (document.ResponseUploadDocument, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestCopyDocument) document.ResponseUploadDocument); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(document.ResponseUploadDocument)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestCopyDocument) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PrepareArchive provides a mock function with given fields: ctx, request
+func (_m *UsecaseInterface) PrepareArchive(ctx context.Context, request document.RequestArchiveDocument) (document.Archive, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrepareArchive")
+	}
+
+	var r0 document.Archive
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestArchiveDocument) (document.Archive, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestArchiveDocument) document.Archive); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(document.Archive)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestArchiveDocument) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolveDocumentId provides a mock function with given fields: ctx, id
+func (_m *UsecaseInterface) ResolveDocumentId(ctx context.Context, id string) (string, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveDocumentId")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreVersion provides a mock function with given fields: ctx, fileIdentifier, versionId
+func (_m *UsecaseInterface) RestoreVersion(ctx context.Context, fileIdentifier string, versionId string) (document.ResponseUploadDocument, error) {
+	ret := _m.Called(ctx, fileIdentifier, versionId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreVersion")
+	}
+
+	var r0 document.ResponseUploadDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (document.ResponseUploadDocument, error)); ok {
+		return rf(ctx, fileIdentifier, versionId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) document.ResponseUploadDocument); ok {
+		r0 = rf(ctx, fileIdentifier, versionId)
+	} else {
+		r0 = ret.Get(0).(document.ResponseUploadDocument)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, fileIdentifier, versionId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchDocuments provides a mock function with given fields: ctx, request
+func (_m *UsecaseInterface) SearchDocuments(ctx context.Context, request document.RequestSearchDocument) (document.ResponseSearchDocument, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchDocuments")
+	}
+
+	var r0 document.ResponseSearchDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestSearchDocument) (document.ResponseSearchDocument, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestSearchDocument) document.ResponseSearchDocument); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(document.ResponseSearchDocument)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestSearchDocument) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SignDownloadLink provides a mock function with given fields: ctx, request
+func (_m *UsecaseInterface) SignDownloadLink(ctx context.Context, request document.RequestSignDownload) (document.ResponseSignDownload, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SignDownloadLink")
+	}
+
+	var r0 document.ResponseSignDownload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestSignDownload) (document.ResponseSignDownload, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestSignDownload) document.ResponseSignDownload); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(document.ResponseSignDownload)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestSignDownload) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadArchive provides a mock function with given fields: ctx, request, file
+func (_m *UsecaseInterface) UploadArchive(ctx context.Context, request document.RequestUploadArchive, file *multipart.FileHeader) (document.ResponseUploadArchive, error) {
+	ret := _m.Called(ctx, request, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadArchive")
+	}
+
+	var r0 document.ResponseUploadArchive
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestUploadArchive, *multipart.FileHeader) (document.ResponseUploadArchive, error)); ok {
+		return rf(ctx, request, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestUploadArchive, *multipart.FileHeader) document.ResponseUploadArchive); ok {
+		r0 = rf(ctx, request, file)
+	} else {
+		r0 = ret.Get(0).(document.ResponseUploadArchive)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestUploadArchive, *multipart.FileHeader) error); ok {
+		r1 = rf(ctx, request, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UploadBase64 provides a mock function with given fields: ctx, request
 func (_m *UsecaseInterface) UploadBase64(ctx context.Context, request document.RequestUploadDocumentBase64) (document.ResponseUploadDocument, error) {
 	ret := _m.Called(ctx, request)
@@ -76,6 +404,34 @@ func (_m *UsecaseInterface) UploadBase64(ctx context.Context, request document.R
 	return r0, r1
 }
 
+// UploadBatch provides a mock function with given fields: ctx, request, files
+func (_m *UsecaseInterface) UploadBatch(ctx context.Context, request document.RequestUploadBatch, files []*multipart.FileHeader) (document.ResponseUploadBatch, error) {
+	ret := _m.Called(ctx, request, files)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadBatch")
+	}
+
+	var r0 document.ResponseUploadBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestUploadBatch, []*multipart.FileHeader) (document.ResponseUploadBatch, error)); ok {
+		return rf(ctx, request, files)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestUploadBatch, []*multipart.FileHeader) document.ResponseUploadBatch); ok {
+		r0 = rf(ctx, request, files)
+	} else {
+		r0 = ret.Get(0).(document.ResponseUploadBatch)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestUploadBatch, []*multipart.FileHeader) error); ok {
+		r1 = rf(ctx, request, files)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UploadFile provides a mock function with given fields: ctx, request, file
 func (_m *UsecaseInterface) UploadFile(ctx context.Context, request document.RequestUploadDocumentFile, file *multipart.FileHeader) (document.ResponseUploadDocument, error) {
 	ret := _m.Called(ctx, request, file)
@@ -104,6 +460,52 @@ func (_m *UsecaseInterface) UploadFile(ctx context.Context, request document.Req
 	return r0, r1
 }
 
+// UploadUrl provides a mock function with given fields: ctx, request
+func (_m *UsecaseInterface) UploadUrl(ctx context.Context, request document.RequestUploadDocumentUrl) (document.ResponseUploadDocument, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadUrl")
+	}
+
+	var r0 document.ResponseUploadDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestUploadDocumentUrl) (document.ResponseUploadDocument, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, document.RequestUploadDocumentUrl) document.ResponseUploadDocument); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(document.ResponseUploadDocument)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, document.RequestUploadDocumentUrl) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WriteArchive provides a mock function with given fields: ctx, archive, w
+func (_m *UsecaseInterface) WriteArchive(ctx context.Context, archive document.Archive, w io.Writer) error {
+	ret := _m.Called(ctx, archive, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteArchive")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, document.Archive, io.Writer) error); ok {
+		r0 = rf(ctx, archive, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUsecaseInterface creates a new instance of UsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUsecaseInterface(t interface {
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"aws-s3-bucket/models/document"
+	"context"
+	"time"
+)
+
+type RepositoryInterface interface {
+	SaveDocument(ctx context.Context, record document.Record) (err error)
+	DeleteDocument(ctx context.Context, bucket, key string, deletedAt time.Time) (err error)
+	ListDocuments(ctx context.Context, filter document.RecordFilter) (records []document.Record, err error)
+	SearchDocuments(ctx context.Context, filter document.SearchFilter) (records []document.Record, nextCursor string, err error)
+}
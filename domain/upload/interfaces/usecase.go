@@ -3,6 +3,7 @@ package interfaces
 import (
 	"aws-s3-bucket/models/document"
 	"context"
+	"io"
 	"mime/multipart"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -11,5 +12,20 @@ import (
 type UsecaseInterface interface {
 	UploadBase64(ctx context.Context, request document.RequestUploadDocumentBase64) (response document.ResponseUploadDocument, err error)
 	UploadFile(ctx context.Context, request document.RequestUploadDocumentFile, file *multipart.FileHeader) (response document.ResponseUploadDocument, err error)
-	DownloadFile(ctx context.Context, fileIdentifier string) (response *s3.GetObjectOutput, err error)
+	UploadUrl(ctx context.Context, request document.RequestUploadDocumentUrl) (response document.ResponseUploadDocument, err error)
+	UploadBatch(ctx context.Context, request document.RequestUploadBatch, files []*multipart.FileHeader) (response document.ResponseUploadBatch, err error)
+	UploadArchive(ctx context.Context, request document.RequestUploadArchive, file *multipart.FileHeader) (response document.ResponseUploadArchive, err error)
+	DownloadFile(ctx context.Context, request document.RequestDownloadDocument) (response *s3.GetObjectOutput, err error)
+	ResolveDocumentId(ctx context.Context, id string) (key string, err error)
+	SignDownloadLink(ctx context.Context, request document.RequestSignDownload) (response document.ResponseSignDownload, err error)
+	DeleteFile(ctx context.Context, fileIdentifier string) (err error)
+	CopyDocument(ctx context.Context, request document.RequestCopyDocument) (response document.ResponseUploadDocument, err error)
+	MoveDocument(ctx context.Context, request document.RequestCopyDocument) (response document.ResponseUploadDocument, err error)
+	PrepareArchive(ctx context.Context, request document.RequestArchiveDocument) (archive document.Archive, err error)
+	WriteArchive(ctx context.Context, archive document.Archive, w io.Writer) (err error)
+	GetMetadata(ctx context.Context, fileIdentifier string) (response document.ResponseDocumentMetadata, err error)
+	ListDocuments(ctx context.Context, request document.RequestListDocument) (response document.ResponseListDocument, err error)
+	SearchDocuments(ctx context.Context, request document.RequestSearchDocument) (response document.ResponseSearchDocument, err error)
+	ListVersions(ctx context.Context, fileIdentifier string) (response []document.ResponseDocumentVersion, err error)
+	RestoreVersion(ctx context.Context, fileIdentifier, versionId string) (response document.ResponseUploadDocument, err error)
 }
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// migrations hold the sql files of each driver, applied in name order, a file is never edited once released
+//
+//go:embed migrations
+var migrations embed.FS
+
+// migrate apply the migrations not yet recorded in schema_migrations, each one in its own transaction
+func migrate(ctx context.Context, db *sql.DB, driver string) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version TEXT PRIMARY KEY, applied_at TIMESTAMP NOT NULL)`)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	dir := path.Join("migrations", driver)
+	entries, err := migrations.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	r := &repository{db: db, driver: driver}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		version := strings.TrimSuffix(entry.Name(), ".sql")
+		script, err := migrations.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+		if err = r.applyMigration(ctx, version, string(script)); err != nil {
+			return fmt.Errorf("failed to migrate database %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+func (r *repository) applyMigration(ctx context.Context, version, script string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if r.driver == DriverPostgres {
+		// several instances can start together, only one of them apply the migration
+		if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('schema_migrations'))`); err != nil {
+			return err
+		}
+	}
+
+	var applied int
+	err = tx.QueryRowContext(ctx, r.rebind(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`), version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, r.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`), version, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,16 @@
+CREATE TABLE documents (
+    id              BIGSERIAL PRIMARY KEY,
+    bucket          TEXT NOT NULL,
+    document_key    TEXT NOT NULL,
+    size            BIGINT NOT NULL DEFAULT 0,
+    content_type    TEXT NOT NULL DEFAULT '',
+    checksum_sha256 TEXT NOT NULL DEFAULT '',
+    uploader        TEXT NOT NULL DEFAULT '',
+    status          TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    updated_at      TIMESTAMPTZ NOT NULL,
+    deleted_at      TIMESTAMPTZ NULL,
+    UNIQUE (bucket, document_key)
+);
+
+CREATE INDEX idx_documents_status ON documents (status);
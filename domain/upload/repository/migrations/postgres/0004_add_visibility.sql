@@ -0,0 +1 @@
+ALTER TABLE documents ADD COLUMN visibility TEXT NOT NULL DEFAULT '';
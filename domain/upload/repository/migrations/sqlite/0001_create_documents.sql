@@ -0,0 +1,16 @@
+CREATE TABLE documents (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    bucket          TEXT NOT NULL,
+    document_key    TEXT NOT NULL,
+    size            INTEGER NOT NULL DEFAULT 0,
+    content_type    TEXT NOT NULL DEFAULT '',
+    checksum_sha256 TEXT NOT NULL DEFAULT '',
+    uploader        TEXT NOT NULL DEFAULT '',
+    status          TEXT NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    updated_at      TIMESTAMP NOT NULL,
+    deleted_at      TIMESTAMP NULL,
+    UNIQUE (bucket, document_key)
+);
+
+CREATE INDEX idx_documents_status ON documents (status);
@@ -0,0 +1,20 @@
+CREATE TABLE document_tags (
+    document_id INTEGER NOT NULL REFERENCES documents (id) ON DELETE CASCADE,
+    tag         TEXT NOT NULL,
+    PRIMARY KEY (document_id, tag)
+);
+
+CREATE INDEX idx_document_tags_tag ON document_tags (tag);
+
+CREATE TABLE document_metadata (
+    document_id INTEGER NOT NULL REFERENCES documents (id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    value       TEXT NOT NULL,
+    PRIMARY KEY (document_id, name)
+);
+
+CREATE INDEX idx_document_metadata_name_value ON document_metadata (name, value);
+
+CREATE INDEX idx_documents_created_at ON documents (bucket, created_at, id);
+CREATE INDEX idx_documents_size ON documents (bucket, size, id);
+CREATE INDEX idx_documents_content_type ON documents (bucket, content_type);
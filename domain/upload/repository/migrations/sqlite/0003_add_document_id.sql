@@ -0,0 +1 @@
+ALTER TABLE documents ADD COLUMN document_id TEXT NOT NULL DEFAULT '';
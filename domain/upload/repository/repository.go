@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"aws-s3-bucket/domain/upload/interfaces"
+	"aws-s3-bucket/models/document"
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	DriverSqlite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// sqlDrivers is the database/sql driver name of each supported driver
+var sqlDrivers = map[string]string{
+	DriverSqlite:   "sqlite",
+	DriverPostgres: "pgx",
+}
+
+type repository struct {
+	db     *sql.DB
+	driver string
+}
+
+// Open connect to the database and apply the pending migrations, dsn is a file path for sqlite
+// and a connection url for postgres
+func Open(ctx context.Context, driver, dsn string) (interfaces.RepositoryInterface, *sql.DB, error) {
+	sqlDriver, ok := sqlDrivers[driver]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+
+	db, err := sql.Open(sqlDriver, dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if driver == DriverSqlite {
+		// sqlite allow a single writer, and every connection of ":memory:" is a different database
+		db.SetMaxOpenConns(1)
+	}
+
+	repo, err := NewRepository(ctx, db, driver)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return repo, db, nil
+}
+
+// NewRepository use an opened database, the pending migrations are applied before
+func NewRepository(ctx context.Context, db *sql.DB, driver string) (interfaces.RepositoryInterface, error) {
+	if _, ok := sqlDrivers[driver]; !ok {
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+	if err := migrate(ctx, db, driver); err != nil {
+		return nil, err
+	}
+	return &repository{db: db, driver: driver}, nil
+}
+
+// SaveDocument insert the record, or replace the one with the same bucket and key. a document uploaded
+// again after a delete is a new document, its creation date is reset. tags and metadata are replaced
+func (r *repository) SaveDocument(ctx context.Context, record document.Record) error {
+	// postgres keep microseconds, the same precision is kept everywhere so cursor compare equally
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	if record.Status == "" {
+		record.Status = document.RecordStatusActive
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save document record: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, r.rebind(`
+		INSERT INTO documents (bucket, document_key, document_id, size, content_type, checksum_sha256, uploader, visibility, status, created_at, updated_at, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULL)
+		ON CONFLICT (bucket, document_key) DO UPDATE SET
+			document_id = excluded.document_id,
+			size = excluded.size,
+			content_type = excluded.content_type,
+			checksum_sha256 = excluded.checksum_sha256,
+			uploader = excluded.uploader,
+			visibility = excluded.visibility,
+			status = excluded.status,
+			created_at = CASE WHEN documents.deleted_at IS NULL THEN documents.created_at ELSE excluded.created_at END,
+			updated_at = excluded.updated_at,
+			deleted_at = NULL
+		RETURNING id`),
+		record.Bucket, record.Key, record.DocumentId, record.Size, record.ContentType, record.ChecksumSHA256, record.Uploader, record.Visibility, record.Status, now, now,
+	).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("failed to save document record: %w", err)
+	}
+
+	if err = r.saveAttributes(ctx, tx, id, record); err != nil {
+		return fmt.Errorf("failed to save document record: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save document record: %w", err)
+	}
+	return nil
+}
+
+// saveAttributes replace the searchable tags and metadata of the document
+func (r *repository) saveAttributes(ctx context.Context, tx *sql.Tx, id int64, record document.Record) error {
+	if _, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM document_tags WHERE document_id = ?`), id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM document_metadata WHERE document_id = ?`), id); err != nil {
+		return err
+	}
+
+	for _, tag := range record.Tags {
+		_, err := tx.ExecContext(ctx, r.rebind(`INSERT INTO document_tags (document_id, tag) VALUES (?, ?) ON CONFLICT DO NOTHING`), id, tag)
+		if err != nil {
+			return err
+		}
+	}
+	for name, value := range record.Metadata {
+		_, err := tx.ExecContext(ctx, r.rebind(`INSERT INTO document_metadata (document_id, name, value) VALUES (?, ?, ?)`), id, name, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteDocument soft delete the record, the row is kept with its deletion date
+func (r *repository) DeleteDocument(ctx context.Context, bucket, key string, deletedAt time.Time) error {
+	deletedAt = deletedAt.UTC().Truncate(time.Microsecond)
+	_, err := r.db.ExecContext(ctx, r.rebind(`
+		UPDATE documents SET status = ?, deleted_at = ?, updated_at = ?
+		WHERE bucket = ? AND document_key = ? AND deleted_at IS NULL`),
+		document.RecordStatusDeleted, deletedAt, deletedAt, bucket, key,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete document record: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) ListDocuments(ctx context.Context, filter document.RecordFilter) ([]document.Record, error) {
+	query := `
+		SELECT ` + recordColumns + `
+		FROM documents WHERE bucket = ?`
+	args := []any{filter.Bucket}
+	if filter.Prefix != "" {
+		query += ` AND document_key LIKE ? ESCAPE '\'`
+		args = append(args, escapeLike(filter.Prefix)+"%")
+	}
+	if !filter.IncludeDeleted {
+		query += ` AND deleted_at IS NULL`
+	}
+	if filter.ExcludeVisibility != "" {
+		query += ` AND visibility <> ?`
+		args = append(args, filter.ExcludeVisibility)
+	}
+	query += ` ORDER BY document_key LIMIT ? OFFSET ?`
+	args = append(args, filter.Limit, filter.Offset)
+
+	records, err := r.queryRecords(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list document records: %w", err)
+	}
+	return records, nil
+}
+
+const recordColumns = `id, bucket, document_key, document_id, size, content_type, checksum_sha256, uploader, visibility, status, created_at, updated_at, deleted_at`
+
+// queryRecords select recordColumns of documents then load their tags and metadata
+func (r *repository) queryRecords(ctx context.Context, query string, args ...any) ([]document.Record, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []document.Record{}
+	for rows.Next() {
+		var record document.Record
+		var deletedAt sql.NullTime
+		err = rows.Scan(&record.Id, &record.Bucket, &record.Key, &record.DocumentId, &record.Size, &record.ContentType, &record.ChecksumSHA256,
+			&record.Uploader, &record.Visibility, &record.Status, &record.CreatedAt, &record.UpdatedAt, &deletedAt)
+		if err != nil {
+			return nil, err
+		}
+		record.CreatedAt, record.UpdatedAt = record.CreatedAt.UTC(), record.UpdatedAt.UTC()
+		if deletedAt.Valid {
+			deletedAt.Time = deletedAt.Time.UTC()
+			record.DeletedAt = &deletedAt.Time
+		}
+		records = append(records, record)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = r.loadAttributes(ctx, records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// loadAttributes fill tags and metadata of the records with one query each
+func (r *repository) loadAttributes(ctx context.Context, records []document.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	index := make(map[int64]*document.Record, len(records))
+	ids := make([]any, 0, len(records))
+	for i := range records {
+		index[records[i].Id] = &records[i]
+		ids = append(ids, records[i].Id)
+	}
+	in := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(`SELECT document_id, tag FROM document_tags WHERE document_id IN (`+in+`) ORDER BY tag`), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var tag string
+		if err = rows.Scan(&id, &tag); err != nil {
+			return err
+		}
+		index[id].Tags = append(index[id].Tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = r.db.QueryContext(ctx, r.rebind(`SELECT document_id, name, value FROM document_metadata WHERE document_id IN (`+in+`)`), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var name, value string
+		if err = rows.Scan(&id, &name, &value); err != nil {
+			return err
+		}
+		if index[id].Metadata == nil {
+			index[id].Metadata = map[string]string{}
+		}
+		index[id].Metadata[name] = value
+	}
+	return rows.Err()
+}
+
+// rebind replace the "?" placeholders by the numbered ones of postgres
+func (r *repository) rebind(query string) string {
+	if r.driver != DriverPostgres {
+		return query
+	}
+
+	var builder strings.Builder
+	n := 0
+	for _, char := range query {
+		if char == '?' {
+			n++
+			builder.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
+
+// escapeLike escape the wildcards of LIKE so the prefix is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
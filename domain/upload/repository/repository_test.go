@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/stretchr/testify/require"
+)
+
+func initRepositoryUnitTest(t *testing.T) *repository {
+	repo, db, err := Open(context.Background(), DriverSqlite, ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// applying the migrations again is a no-op
+	_, err = NewRepository(context.Background(), db, DriverSqlite)
+	require.NoError(t, err)
+
+	return repo.(*repository)
+}
+
+func Test_Open_UnsupportedDriver(t *testing.T) {
+	_, _, err := Open(context.Background(), "mysql", "")
+	require.ErrorContains(t, err, "unsupported database driver")
+}
+
+func Test_Rebind(t *testing.T) {
+	query := "SELECT * FROM documents WHERE bucket = ? AND document_key = ?"
+	require.Equal(t, query, (&repository{driver: DriverSqlite}).rebind(query))
+	require.Equal(t, "SELECT * FROM documents WHERE bucket = $1 AND document_key = $2", (&repository{driver: DriverPostgres}).rebind(query))
+}
+
+func Test_Repository_Documents(t *testing.T) {
+	repo := initRepositoryUnitTest(t)
+	ctx := context.Background()
+
+	save := func(key string, size int64) {
+		require.NoError(t, repo.SaveDocument(ctx, document.Record{
+			Key: key, Bucket: "test-bucket", Size: size, ContentType: "text/plain", ChecksumSHA256: "sum", Uploader: "alice",
+		}))
+	}
+	list := func(filter document.RecordFilter) []document.Record {
+		filter.Bucket, filter.Limit = "test-bucket", 10
+		records, err := repo.ListDocuments(ctx, filter)
+		require.NoError(t, err)
+		return records
+	}
+
+	save("data/b.txt", 10)
+	save("data/a.txt", 20)
+	save("data_x/c.txt", 30)
+	save("other/d.txt", 40)
+
+	records := list(document.RecordFilter{Prefix: "data/"})
+	require.Len(t, records, 2)
+	require.Equal(t, "data/a.txt", records[0].Key)
+	require.Equal(t, int64(20), records[0].Size)
+	require.Equal(t, "alice", records[0].Uploader)
+	require.Equal(t, document.RecordStatusActive, records[0].Status)
+	require.False(t, records[0].CreatedAt.IsZero())
+	require.Nil(t, records[0].DeletedAt)
+
+	// "_" of the prefix is not a wildcard
+	require.Len(t, list(document.RecordFilter{Prefix: "data_"}), 1)
+	require.Len(t, list(document.RecordFilter{}), 4)
+
+	page, err := repo.ListDocuments(ctx, document.RecordFilter{Bucket: "test-bucket", Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"data/b.txt", "data_x/c.txt"}, []string{page[0].Key, page[1].Key})
+
+	require.NoError(t, repo.DeleteDocument(ctx, "test-bucket", "data/a.txt", time.Now()))
+	require.Len(t, list(document.RecordFilter{Prefix: "data/"}), 1)
+
+	records = list(document.RecordFilter{Prefix: "data/", IncludeDeleted: true})
+	require.Len(t, records, 2)
+	require.Equal(t, document.RecordStatusDeleted, records[0].Status)
+	require.NotNil(t, records[0].DeletedAt)
+	created := records[0].CreatedAt
+
+	// uploading again revive the document as a new one
+	time.Sleep(10 * time.Millisecond)
+	save("data/a.txt", 25)
+	records = list(document.RecordFilter{Prefix: "data/a"})
+	require.Len(t, records, 1)
+	require.Equal(t, int64(25), records[0].Size)
+	require.Equal(t, document.RecordStatusActive, records[0].Status)
+	require.Nil(t, records[0].DeletedAt)
+	require.True(t, records[0].CreatedAt.After(created))
+}
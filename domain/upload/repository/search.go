@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"aws-s3-bucket/models/document"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultSearchSort = "-created_at"
+
+// sortColumns is the column of each sort, id break ties so the order is total
+var sortColumns = map[string]string{
+	"key":        "document_key",
+	"size":       "size",
+	"created_at": "created_at",
+}
+
+// searchCursor is the position after the last document of a page, encoded as url safe base64 json
+type searchCursor struct {
+	Sort      string    `json:"s"`
+	Id        int64     `json:"i"`
+	Key       string    `json:"k,omitempty"`
+	Size      int64     `json:"z,omitempty"`
+	CreatedAt time.Time `json:"c,omitempty"`
+}
+
+// SearchDocuments find the active documents matching every filter, one page at a time with a keyset cursor
+// so pages stay stable while documents are added
+func (r *repository) SearchDocuments(ctx context.Context, filter document.SearchFilter) ([]document.Record, string, error) {
+	if filter.Sort == "" {
+		filter.Sort = defaultSearchSort
+	}
+	name, descending := strings.TrimPrefix(filter.Sort, "-"), strings.HasPrefix(filter.Sort, "-")
+	column, ok := sortColumns[name]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: unknown sort %q", document.ErrInvalidSearch, filter.Sort)
+	}
+
+	query := `SELECT ` + recordColumns + ` FROM documents WHERE bucket = ? AND deleted_at IS NULL`
+	args := []any{filter.Bucket}
+
+	if filter.Name != "" {
+		query += ` AND LOWER(document_key) LIKE ? ESCAPE '\'`
+		args = append(args, "%"+escapeLike(strings.ToLower(filter.Name))+"%")
+	}
+	if mainType, ok := strings.CutSuffix(filter.ContentType, "/*"); ok {
+		query += ` AND content_type LIKE ? ESCAPE '\'`
+		args = append(args, escapeLike(mainType)+"/%")
+	} else if filter.ContentType != "" {
+		query += ` AND content_type = ?`
+		args = append(args, filter.ContentType)
+	}
+	if filter.ExcludeVisibility != "" {
+		query += ` AND visibility <> ?`
+		args = append(args, filter.ExcludeVisibility)
+	}
+	if filter.MinSize > 0 {
+		query += ` AND size >= ?`
+		args = append(args, filter.MinSize)
+	}
+	if filter.MaxSize > 0 {
+		query += ` AND size <= ?`
+		args = append(args, filter.MaxSize)
+	}
+	if filter.UploadedAfter != nil {
+		query += ` AND created_at >= ?`
+		args = append(args, filter.UploadedAfter.UTC())
+	}
+	if filter.UploadedBefore != nil {
+		query += ` AND created_at < ?`
+		args = append(args, filter.UploadedBefore.UTC())
+	}
+	for _, tag := range filter.Tags {
+		query += ` AND EXISTS (SELECT 1 FROM document_tags t WHERE t.document_id = documents.id AND t.tag = ?)`
+		args = append(args, tag)
+	}
+	names := make([]string, 0, len(filter.Metadata))
+	for name := range filter.Metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		query += ` AND EXISTS (SELECT 1 FROM document_metadata m WHERE m.document_id = documents.id AND m.name = ? AND m.value = ?)`
+		args = append(args, name, filter.Metadata[name])
+	}
+
+	operator, direction := ">", "ASC"
+	if descending {
+		operator, direction = "<", "DESC"
+	}
+	if filter.Cursor != "" {
+		cursor, err := decodeCursor(filter.Cursor)
+		if err != nil || cursor.Sort != filter.Sort {
+			return nil, "", fmt.Errorf("%w: invalid cursor", document.ErrInvalidSearch)
+		}
+		query += fmt.Sprintf(` AND (%s %s ? OR (%s = ? AND id %s ?))`, column, operator, column, operator)
+		value := cursor.value(name)
+		args = append(args, value, value, cursor.Id)
+	}
+
+	// one more document tell whether there is a next page
+	query += fmt.Sprintf(` ORDER BY %s %s, id %s LIMIT ?`, column, direction, direction)
+	args = append(args, filter.Limit+1)
+
+	records, err := r.queryRecords(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to search document records: %w", err)
+	}
+	if len(records) <= filter.Limit {
+		return records, "", nil
+	}
+
+	records = records[:filter.Limit]
+	last := records[len(records)-1]
+	next := encodeCursor(searchCursor{Sort: filter.Sort, Id: last.Id, Key: last.Key, Size: last.Size, CreatedAt: last.CreatedAt})
+	return records, next, nil
+}
+
+func (c searchCursor) value(sort string) any {
+	switch sort {
+	case "key":
+		return c.Key
+	case "size":
+		return c.Size
+	}
+	return c.CreatedAt.UTC()
+}
+
+func encodeCursor(cursor searchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (cursor searchCursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &cursor)
+	return
+}
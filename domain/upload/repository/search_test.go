@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Repository_SearchDocuments(t *testing.T) {
+	repo := initRepositoryUnitTest(t)
+	ctx := context.Background()
+
+	seed := []document.Record{
+		{Key: "invoices/2024-01.pdf", DocumentId: "0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70", Size: 300, ContentType: "application/pdf", Tags: []string{"finance", "invoice"}, Metadata: map[string]string{"owner": "alice"}},
+		{Key: "invoices/2024-02.pdf", Size: 100, ContentType: "application/pdf", Tags: []string{"invoice"}, Metadata: map[string]string{"owner": "bob"}},
+		{Key: "photos/Invoice_scan.png", Size: 500, ContentType: "image/png", Tags: []string{"finance"}},
+		{Key: "photos/cat.jpg", Size: 200, ContentType: "image/jpeg", Visibility: "private"},
+		{Key: "notes/readme.txt", Size: 50, ContentType: "text/plain"},
+	}
+	for _, record := range seed {
+		record.Bucket = "test-bucket"
+		require.NoError(t, repo.SaveDocument(ctx, record))
+		time.Sleep(2 * time.Millisecond)
+	}
+	require.NoError(t, repo.DeleteDocument(ctx, "test-bucket", "notes/readme.txt", time.Now()))
+
+	keys := func(records []document.Record) []string {
+		keys := make([]string, 0, len(records))
+		for _, record := range records {
+			keys = append(keys, record.Key)
+		}
+		return keys
+	}
+	search := func(filter document.SearchFilter) []string {
+		filter.Bucket = "test-bucket"
+		if filter.Limit == 0 {
+			filter.Limit = 10
+		}
+		records, next, err := repo.SearchDocuments(ctx, filter)
+		require.NoError(t, err)
+		require.Empty(t, next)
+		return keys(records)
+	}
+
+	// newest first by default, deleted document is left out
+	require.Equal(t, []string{"photos/cat.jpg", "photos/Invoice_scan.png", "invoices/2024-02.pdf", "invoices/2024-01.pdf"}, search(document.SearchFilter{}))
+	require.Equal(t, []string{"photos/Invoice_scan.png", "invoices/2024-02.pdf", "invoices/2024-01.pdf"}, search(document.SearchFilter{Name: "INVOICE"}))
+	require.Equal(t, []string{"photos/cat.jpg", "photos/Invoice_scan.png"}, search(document.SearchFilter{ContentType: "image/*"}))
+	require.Equal(t, []string{"invoices/2024-01.pdf", "invoices/2024-02.pdf"}, search(document.SearchFilter{ContentType: "application/pdf", Sort: "key"}))
+	require.Equal(t, []string{"invoices/2024-01.pdf", "photos/cat.jpg"}, search(document.SearchFilter{MinSize: 200, MaxSize: 300, Sort: "-size"}))
+	require.Equal(t, []string{"invoices/2024-01.pdf"}, search(document.SearchFilter{Tags: []string{"finance", "invoice"}}))
+	require.Equal(t, []string{"invoices/2024-02.pdf"}, search(document.SearchFilter{Metadata: map[string]string{"owner": "bob"}}))
+	require.Empty(t, search(document.SearchFilter{Tags: []string{"unknown"}}))
+	require.Equal(t, []string{"photos/Invoice_scan.png", "invoices/2024-02.pdf", "invoices/2024-01.pdf"}, search(document.SearchFilter{ExcludeVisibility: "private"}))
+
+	all, _, err := repo.SearchDocuments(ctx, document.SearchFilter{Bucket: "test-bucket", Sort: "created_at", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, "0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70", all[0].DocumentId)
+	require.Equal(t, []string{"finance", "invoice"}, all[0].Tags)
+	require.Equal(t, map[string]string{"owner": "alice"}, all[0].Metadata)
+	require.Equal(t, "private", all[3].Visibility)
+	require.Equal(t, []string{"invoices/2024-02.pdf", "photos/Invoice_scan.png"}, search(document.SearchFilter{
+		UploadedAfter: &all[1].CreatedAt, UploadedBefore: &all[3].CreatedAt, Sort: "created_at",
+	}))
+
+	for _, sort := range []string{"key", "-key", "size", "-size", "created_at", "-created_at"} {
+		expected := search(document.SearchFilter{Sort: sort})
+
+		var paged []string
+		cursor := ""
+		for {
+			records, next, err := repo.SearchDocuments(ctx, document.SearchFilter{Bucket: "test-bucket", Sort: sort, Limit: 3, Cursor: cursor})
+			require.NoError(t, err)
+			paged = append(paged, keys(records)...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		require.Equal(t, expected, paged, sort)
+	}
+
+	_, next, err := repo.SearchDocuments(ctx, document.SearchFilter{Bucket: "test-bucket", Sort: "key", Limit: 1})
+	require.NoError(t, err)
+	_, _, err = repo.SearchDocuments(ctx, document.SearchFilter{Bucket: "test-bucket", Sort: "size", Limit: 1, Cursor: next})
+	require.ErrorIs(t, err, document.ErrInvalidSearch)
+	_, _, err = repo.SearchDocuments(ctx, document.SearchFilter{Bucket: "test-bucket", Limit: 1, Cursor: "not-a-cursor"})
+	require.ErrorIs(t, err, document.ErrInvalidSearch)
+	_, _, err = repo.SearchDocuments(ctx, document.SearchFilter{Bucket: "test-bucket", Limit: 1, Sort: "uploader"})
+	require.ErrorIs(t, err, document.ErrInvalidSearch)
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"archive/zip"
+	"aws-s3-bucket/models/document"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	defaultArchiveMaxFiles = 1000
+	defaultArchiveMaxSize  = 1 << 30
+)
+
+// internalPrefixes is storage layout of the service itself, never served as document
+var internalPrefixes = []string{renditionPrefix, originalPrefix, blobPrefix, refPrefix, idPrefix}
+
+func isInternalKey(key string) bool {
+	for _, prefix := range internalPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveLimits is max file count and total size of archive, configured by ARCHIVE_MAX_FILES and ARCHIVE_MAX_SIZE (bytes)
+func archiveLimits() (maxFiles int, maxSize int64) {
+	maxFiles, err := strconv.Atoi(os.Getenv("ARCHIVE_MAX_FILES"))
+	if err != nil || maxFiles <= 0 {
+		maxFiles = defaultArchiveMaxFiles
+	}
+	maxSize, err = strconv.ParseInt(os.Getenv("ARCHIVE_MAX_SIZE"), 10, 64)
+	if err != nil || maxSize <= 0 {
+		maxSize = defaultArchiveMaxSize
+	}
+	return
+}
+
+// PrepareArchive resolve the documents and check the limits before anything is streamed to the client
+func (u *usecase) PrepareArchive(ctx context.Context, request document.RequestArchiveDocument) (archive document.Archive, err error) {
+
+	// the documents are streamed without signed link once the response started, a document that cannot be
+	// downloaded without one is refused before
+	if signedLinksRequired() {
+		return archive, fmt.Errorf("%w: signed link is required", document.ErrInvalidSignature)
+	}
+	if !downloadByKeyEnabled() {
+		return archive, document.ErrDocumentNotFound
+	}
+
+	maxFiles, maxSize := archiveLimits()
+	bucketName := os.Getenv("BUCKET_NAME")
+
+	add := func(key string, size int64) error {
+		archive.Entries = append(archive.Entries, document.ArchiveEntry{Key: key, Size: size})
+		archive.TotalSize += size
+		if len(archive.Entries) > maxFiles {
+			return fmt.Errorf("%w: more than %d files", document.ErrArchiveTooLarge, maxFiles)
+		}
+		if archive.TotalSize > maxSize {
+			return fmt.Errorf("%w: more than %d bytes", document.ErrArchiveTooLarge, maxSize)
+		}
+		return nil
+	}
+
+	// entry is named relatively to the folder of the prefix, "folder/inv" keep "invoice.pdf" not "oice.pdf"
+	base := request.Prefix[:strings.LastIndex(request.Prefix, "/")+1]
+	if request.Prefix != "" {
+		input := &s3.ListObjectsV2Input{
+			Bucket: aws.String(bucketName),
+			Prefix: aws.String(request.Prefix),
+		}
+		for {
+			output, err := u.s3Client.ListObjectsV2(ctx, input)
+			if err != nil {
+				return document.Archive{}, fmt.Errorf("failed to list documents: %w", err)
+			}
+			for _, object := range output.Contents {
+				key := aws.ToString(object.Key)
+				if strings.HasSuffix(key, "/") || isInternalKey(key) {
+					continue
+				}
+				// listing has no metadata, the visibility and the size of deduplicated document are only known from the object
+				head, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+					Bucket: aws.String(bucketName),
+					Key:    aws.String(key),
+				})
+				if isNotFound(err) {
+					continue
+				}
+				if err != nil {
+					return document.Archive{}, fmt.Errorf("failed to check document: %w", err)
+				}
+				if err = checkVisibility(head.Metadata, false); err != nil {
+					return document.Archive{}, fmt.Errorf("%w: %s", err, key)
+				}
+				if err = add(key, documentSize(head)); err != nil {
+					return document.Archive{}, err
+				}
+			}
+			if !aws.ToBool(output.IsTruncated) {
+				break
+			}
+			input.ContinuationToken = output.NextContinuationToken
+		}
+	} else {
+		for _, key := range request.Keys {
+			if isInternalKey(key) {
+				return document.Archive{}, fmt.Errorf("%w: %s", document.ErrDocumentNotFound, key)
+			}
+			head, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+				Bucket: aws.String(bucketName),
+				Key:    aws.String(key),
+			})
+			if isNotFound(err) {
+				return document.Archive{}, fmt.Errorf("%w: %s", document.ErrDocumentNotFound, key)
+			}
+			if err != nil {
+				return document.Archive{}, fmt.Errorf("failed to check document: %w", err)
+			}
+			if err = checkVisibility(head.Metadata, false); err != nil {
+				return document.Archive{}, fmt.Errorf("%w: %s", err, key)
+			}
+			if err = add(key, documentSize(head)); err != nil {
+				return document.Archive{}, err
+			}
+		}
+		base = commonDir(request.Keys)
+	}
+
+	if len(archive.Entries) == 0 {
+		return document.Archive{}, document.ErrDocumentNotFound
+	}
+	for i := range archive.Entries {
+		archive.Entries[i].Name = strings.TrimPrefix(archive.Entries[i].Key, base)
+	}
+
+	return archive, nil
+}
+
+// WriteArchive stream every document of the archive as zip entry into w, one object at a time
+func (u *usecase) WriteArchive(ctx context.Context, archive document.Archive, w io.Writer) (err error) {
+
+	_, maxSize := archiveLimits()
+	zipWriter := zip.NewWriter(w)
+
+	var written int64
+	for _, entry := range archive.Entries {
+		response, err := u.DownloadFile(ctx, document.RequestDownloadDocument{Key: entry.Key})
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", entry.Key, err)
+		}
+
+		header := &zip.FileHeader{
+			Name:     entry.Name,
+			Method:   zip.Deflate,
+			Modified: aws.ToTime(response.LastModified),
+		}
+		if header.Modified.IsZero() {
+			header.Modified = time.Now()
+		}
+		file, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			response.Body.Close()
+			return fmt.Errorf("failed to archive %s: %w", entry.Key, err)
+		}
+
+		// the object can change after PrepareArchive, keep enforcing the size while streaming
+		n, err := io.Copy(file, io.LimitReader(response.Body, maxSize-written+1))
+		response.Body.Close()
+		written += n
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", entry.Key, err)
+		}
+		if written > maxSize {
+			return fmt.Errorf("%w: more than %d bytes", document.ErrArchiveTooLarge, maxSize)
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+// documentSize is the size of the content, a pointer of deduplicated document is only its manifest
+func documentSize(head *s3.HeadObjectOutput) int64 {
+	if dedupeSize, err := strconv.ParseInt(head.Metadata[metaDedupeSize], 10, 64); err == nil {
+		return dedupeSize
+	}
+	return aws.ToInt64(head.ContentLength)
+}
+
+// commonDir is the longest directory shared by every key, ex: a/b/c.pdf and a/d.pdf -> a/
+func commonDir(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+
+	dir := path.Dir(keys[0]) + "/"
+	for _, key := range keys[1:] {
+		for dir != "./" && !strings.HasPrefix(key, dir) {
+			dir = path.Dir(strings.TrimSuffix(dir, "/")) + "/"
+		}
+	}
+	if dir == "./" {
+		return ""
+	}
+	return dir
+}
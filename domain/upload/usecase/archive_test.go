@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CommonDir(t *testing.T) {
+	require.Equal(t, "a/", commonDir([]string{"a/b/c.pdf", "a/d.pdf"}))
+	require.Equal(t, "a/b/", commonDir([]string{"a/b/c.pdf"}))
+	require.Equal(t, "", commonDir([]string{"a/b.pdf", "c/d.pdf"}))
+	require.Equal(t, "", commonDir([]string{"b.pdf"}))
+}
+
+func Test_PrepareArchive(t *testing.T) {
+	os.Setenv("ARCHIVE_MAX_FILES", "3")
+	defer os.Unsetenv("ARCHIVE_MAX_FILES")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	type expected struct {
+		err     error
+		archive document.Archive
+	}
+	tests := []struct {
+		name     string
+		request  document.RequestArchiveDocument
+		prepare  func()
+		expected expected
+	}{
+		{
+			name:    "Prefix",
+			request: document.RequestArchiveDocument{Prefix: "customer/inv"},
+			prepare: func() {
+				mockS3Client.On("ListObjectsV2", mock.Anything, mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+					return input.ContinuationToken == nil
+				})).Return(&s3.ListObjectsV2Output{
+					Contents: []types.Object{
+						{Key: aws.String("customer/invoice-1.pdf"), Size: aws.Int64(10)},
+						{Key: aws.String("customer/invoices/"), Size: aws.Int64(0)},
+					},
+					IsTruncated:           aws.Bool(true),
+					NextContinuationToken: aws.String("next"),
+				}, nil).Once()
+				mockS3Client.On("ListObjectsV2", mock.Anything, mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+					return aws.ToString(input.ContinuationToken) == "next"
+				})).Return(&s3.ListObjectsV2Output{
+					Contents: []types.Object{{Key: aws.String("customer/invoices/2.pdf"), Size: aws.Int64(1)}},
+				}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("customer/invoice-1.pdf")).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(10)}, nil).Once()
+				// the listed size of a deduplicated document is the one of its pointer
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("customer/invoices/2.pdf")).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(1),
+					Metadata:      map[string]string{metaVisibility: visibilityPublic, metaDedupeSize: "20"},
+				}, nil).Once()
+			},
+			expected: expected{
+				archive: document.Archive{
+					Entries: []document.ArchiveEntry{
+						{Key: "customer/invoice-1.pdf", Name: "invoice-1.pdf", Size: 10},
+						{Key: "customer/invoices/2.pdf", Name: "invoices/2.pdf", Size: 20},
+					},
+					TotalSize: 30,
+				},
+			},
+		},
+		{
+			name:    "PrefixTooManyFiles",
+			request: document.RequestArchiveDocument{Prefix: "customer/"},
+			prepare: func() {
+				mockS3Client.On("ListObjectsV2", mock.Anything, mock.Anything).Return(&s3.ListObjectsV2Output{
+					Contents: []types.Object{
+						{Key: aws.String("customer/1.pdf")}, {Key: aws.String("customer/2.pdf")},
+						{Key: aws.String("customer/3.pdf")}, {Key: aws.String("customer/4.pdf")},
+					},
+				}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, mock.Anything).Return(&s3.HeadObjectOutput{}, nil).Times(4)
+			},
+			expected: expected{
+				err: fmt.Errorf("%w: more than 3 files", document.ErrArchiveTooLarge),
+			},
+		},
+		{
+			name:    "Keys",
+			request: document.RequestArchiveDocument{Keys: []string{"customer/a/1.pdf", "customer/2.pdf"}},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("customer/a/1.pdf")).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(10)}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("customer/2.pdf")).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(100),
+					Metadata:      map[string]string{metaDedupeSize: "20"},
+				}, nil).Once()
+			},
+			expected: expected{
+				archive: document.Archive{
+					Entries: []document.ArchiveEntry{
+						{Key: "customer/a/1.pdf", Name: "a/1.pdf", Size: 10},
+						{Key: "customer/2.pdf", Name: "2.pdf", Size: 20},
+					},
+					TotalSize: 30,
+				},
+			},
+		},
+		{
+			name:    "KeyNotFound",
+			request: document.RequestArchiveDocument{Keys: []string{"customer/1.pdf"}},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("customer/1.pdf")).Return(nil, &types.NotFound{}).Once()
+			},
+			expected: expected{
+				err: fmt.Errorf("%w: %s", document.ErrDocumentNotFound, "customer/1.pdf"),
+			},
+		},
+		{
+			name:    "PrefixPrivate",
+			request: document.RequestArchiveDocument{Prefix: "customer/"},
+			prepare: func() {
+				mockS3Client.On("ListObjectsV2", mock.Anything, mock.Anything).Return(&s3.ListObjectsV2Output{
+					Contents: []types.Object{{Key: aws.String("customer/1.pdf"), Size: aws.Int64(10)}},
+				}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("customer/1.pdf")).Return(&s3.HeadObjectOutput{
+					Metadata: map[string]string{metaVisibility: visibilityPrivate},
+				}, nil).Once()
+			},
+			expected: expected{
+				err: fmt.Errorf("%w: %s", fmt.Errorf("%w: private document requires a signed link", document.ErrInvalidSignature), "customer/1.pdf"),
+			},
+		},
+		{
+			name:    "KeyPrivate",
+			request: document.RequestArchiveDocument{Keys: []string{"customer/1.pdf"}},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("customer/1.pdf")).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(10),
+					Metadata:      map[string]string{metaVisibility: visibilityPrivate},
+				}, nil).Once()
+			},
+			expected: expected{
+				err: fmt.Errorf("%w: %s", fmt.Errorf("%w: private document requires a signed link", document.ErrInvalidSignature), "customer/1.pdf"),
+			},
+		},
+		{
+			name:    "InternalKey",
+			request: document.RequestArchiveDocument{Keys: []string{"blobs/abc"}},
+			prepare: func() {},
+			expected: expected{
+				err: fmt.Errorf("%w: %s", document.ErrDocumentNotFound, "blobs/abc"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			archive, err := usecase.PrepareArchive(nil, tt.request)
+
+			require.Equal(t, tt.expected.err, err)
+			require.Equal(t, tt.expected.archive, archive)
+		})
+	}
+}
+
+func Test_PrepareArchive_Refused(t *testing.T) {
+	usecase, _ := initUseCaseUnitTest(t)
+	request := document.RequestArchiveDocument{Keys: []string{"customer/1.pdf"}}
+
+	os.Setenv("DOWNLOAD_SIGNED_ONLY", "true")
+	_, err := usecase.PrepareArchive(nil, request)
+	os.Unsetenv("DOWNLOAD_SIGNED_ONLY")
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+
+	os.Setenv("DOWNLOAD_BY_KEY", "false")
+	_, err = usecase.PrepareArchive(nil, request)
+	os.Unsetenv("DOWNLOAD_BY_KEY")
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+}
+
+func Test_WriteArchive(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("customer/1.pdf")).Return(&s3.GetObjectOutput{
+		Body: io.NopCloser(bytes.NewReader([]byte("first"))),
+	}, nil).Once()
+	mockS3Client.On("GetObject", mock.Anything, matchKey("customer/a/2.pdf")).Return(&s3.GetObjectOutput{
+		Body: io.NopCloser(bytes.NewReader([]byte("second"))),
+	}, nil).Once()
+
+	var buf bytes.Buffer
+	err := usecase.WriteArchive(nil, document.Archive{Entries: []document.ArchiveEntry{
+		{Key: "customer/1.pdf", Name: "1.pdf"},
+		{Key: "customer/a/2.pdf", Name: "a/2.pdf"},
+	}}, &buf)
+	require.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, reader.File, 2)
+
+	contents := map[string]string{}
+	for _, file := range reader.File {
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, _ := io.ReadAll(f)
+		contents[file.Name] = string(content)
+	}
+	require.Equal(t, map[string]string{"1.pdf": "first", "a/2.pdf": "second"}, contents)
+
+	// object grow after prepare
+	os.Setenv("ARCHIVE_MAX_SIZE", "3")
+	defer os.Unsetenv("ARCHIVE_MAX_SIZE")
+	mockS3Client.On("GetObject", mock.Anything, matchKey("customer/1.pdf")).Return(&s3.GetObjectOutput{
+		Body: io.NopCloser(bytes.NewReader([]byte("first"))),
+	}, nil).Once()
+
+	err = usecase.WriteArchive(nil, document.Archive{Entries: []document.ArchiveEntry{{Key: "customer/1.pdf", Name: "1.pdf"}}}, io.Discard)
+	require.Equal(t, fmt.Errorf("%w: more than 3 bytes", document.ErrArchiveTooLarge), err)
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"strings"
+)
+
+// base64SpoolSize is the decoded size beyond which a base64 document is decoded to a temporary file,
+// so a large document is not held twice in memory
+const base64SpoolSize = 8 << 20
+
+// decodeBase64 decode the checked payload, cleanup release the temporary file of a large document
+func decodeBase64(payload string) (body io.ReadSeeker, cleanup func(), err error) {
+	decoder := utils.NewBase64Decoder(payload)
+	if base64.StdEncoding.DecodedLen(len(payload)) <= base64SpoolSize {
+		decoded, err := io.ReadAll(decoder)
+		if err != nil {
+			return nil, nil, base64Error(err)
+		}
+		return bytes.NewReader(decoded), func() {}, nil
+	}
+
+	file, err := os.CreateTemp("", "base64-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	cleanup = func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+	if _, err = io.Copy(file, decoder); err != nil {
+		cleanup()
+		return nil, nil, base64Error(err)
+	}
+	if err = rewind(file); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return file, cleanup, nil
+}
+
+// base64Error report the payload that is not valid base64 as a client error
+func base64Error(err error) error {
+	var invalid *utils.Base64Error
+	if errors.As(err, &invalid) {
+		return fmt.Errorf("%w: %s", document.ErrInvalidBase64, invalid.Reason)
+	}
+	return fmt.Errorf("failed to decode document: %w", err)
+}
+
+// base64ContentType is the content type field of the request, or else the media type of the data uri
+func base64ContentType(declared, dataUri string) (string, error) {
+	if declared == "" {
+		return dataUri, nil
+	}
+	if _, _, err := mime.ParseMediaType(declared); err != nil {
+		return "", fmt.Errorf("%w: content type %q is not valid", document.ErrInvalidBase64, declared)
+	}
+	return declared, nil
+}
+
+// base64Extension keep the subtype of the data uri as extension as it always did, a content type of
+// the request or sniffed use its usual extension
+func base64Extension(contentType, dataUri string) string {
+	if contentType == dataUri {
+		_, subtype, _ := strings.Cut(dataUri, "/")
+		return "." + subtype
+	}
+	return typeExtension(contentType)
+}
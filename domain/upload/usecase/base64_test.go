@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pngPixel is a 1x1 png
+const pngPixel = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAIAAACQd1PeAAAAA3NCSVQICAjb4U/gAAAADElEQVQImWMwMTEBAAE8AJ1tV5pJAAAAAElFTkSuQmCC"
+
+func Test_UploadBase64_Variants(t *testing.T) {
+	png, err := base64.StdEncoding.DecodeString(pngPixel)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		base64      string
+		contentType string
+		key         string
+		expected    string
+	}{
+		{name: "bare base64 is sniffed", base64: pngPixel, key: "data/example.png", expected: "image/png"},
+		{name: "url safe without padding", base64: base64.RawURLEncoding.EncodeToString(png), key: "data/example.png", expected: "image/png"},
+		{name: "content type field", base64: base64.StdEncoding.EncodeToString([]byte("hello")), contentType: "text/plain", key: "data/example.txt", expected: "text/plain"},
+		{name: "content type field over data uri", base64: "data:application/octet-stream;base64," + pngPixel, contentType: "image/png", key: "data/example.png", expected: "image/png"},
+		{name: "data uri keep its subtype", base64: "data:image/jpeg;base64,/9j/", key: "data/example.jpeg", expected: "image/jpeg"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			usecase, mockS3Client := initUseCaseUnitTest(t)
+			mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+				return aws.ToString(input.Key) == test.key && aws.ToString(input.ContentType) == test.expected
+			})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+			response, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+				DocumentKey:    "data",
+				DocumentName:   "example",
+				DocumentBase64: test.base64,
+				ContentType:    test.contentType,
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.key, response.Key)
+		})
+	}
+}
+
+func Test_UploadBase64_Invalid(t *testing.T) {
+	usecase, _ := initUseCaseUnitTest(t)
+
+	_, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:image/png;base64,iVBOR*w0KG",
+	})
+	require.ErrorIs(t, err, document.ErrInvalidBase64)
+	require.EqualError(t, err, `invalid base64: illegal character '*' at offset 5`)
+
+	_, err = usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: pngPixel,
+		ContentType:    "image png",
+	})
+	require.ErrorIs(t, err, document.ErrInvalidBase64)
+}
+
+func Test_decodeBase64_Spool(t *testing.T) {
+	content := strings.Repeat("0123456789abcdef", base64SpoolSize/16+1)
+
+	body, cleanup, err := decodeBase64(base64.StdEncoding.EncodeToString([]byte(content)))
+	require.NoError(t, err)
+	file, ok := body.(*os.File)
+	require.True(t, ok)
+
+	decoded, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.Equal(t, content, string(decoded))
+
+	cleanup()
+	_, err = os.Stat(file.Name())
+	require.True(t, os.IsNotExist(err))
+}
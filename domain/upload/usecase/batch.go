@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/constant"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultBatchConcurrency = 4
+	defaultBatchMaxFiles    = 50
+)
+
+// batchLimits is the worker count and max files of a batch, configured by UPLOAD_BATCH_CONCURRENCY and UPLOAD_BATCH_MAX_FILES
+func batchLimits() (concurrency, maxFiles int) {
+	concurrency, err := strconv.Atoi(os.Getenv("UPLOAD_BATCH_CONCURRENCY"))
+	if err != nil || concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	maxFiles, err = strconv.Atoi(os.Getenv("UPLOAD_BATCH_MAX_FILES"))
+	if err != nil || maxFiles <= 0 {
+		maxFiles = defaultBatchMaxFiles
+	}
+	return
+}
+
+// UploadBatch upload every file under document_key with a bounded worker pool, a file that fail does not stop the others
+// unless the batch is atomic, then the documents already uploaded are deleted
+func (u *usecase) UploadBatch(ctx context.Context, request document.RequestUploadBatch, files []*multipart.FileHeader) (response document.ResponseUploadBatch, err error) {
+
+	concurrency, maxFiles := batchLimits()
+	if len(files) == 0 || len(files) > maxFiles {
+		return response, fmt.Errorf("%w: between 1 and %d files expected", document.ErrInvalidBatch, maxFiles)
+	}
+	if len(request.DocumentNames) != 0 && len(request.DocumentNames) != len(files) {
+		return response, fmt.Errorf("%w: %d document names for %d files", document.ErrInvalidBatch, len(request.DocumentNames), len(files))
+	}
+	for _, checksums := range [][]string{request.ChecksumSHA256, request.ChecksumCRC32C} {
+		if len(checksums) != 0 && len(checksums) != len(files) {
+			return response, fmt.Errorf("%w: %d checksums for %d files", document.ErrInvalidBatch, len(checksums), len(files))
+		}
+	}
+	// checked before any upload, an atomic batch would otherwise upload the files before it
+	for i, file := range files {
+		if batchName(request, i, file) == "" {
+			return response, fmt.Errorf("%w: file %q has no document name", document.ErrInvalidBatch, file.Filename)
+		}
+	}
+
+	response.Documents = make([]document.ResponseBatchDocument, len(files))
+	jobs := make(chan int)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for range min(concurrency, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := &response.Documents[i]
+				result.File = files[i].Filename
+				if request.Atomic && failed.Load() {
+					result.Error = "not uploaded, another file of the batch failed"
+					continue
+				}
+
+				// upload in progress is not cancelled, it could be stored without being known to rollback
+				uploaded, err := u.UploadFile(ctx, batchDocument(request, i, files[i]), files[i])
+				if err != nil {
+					result.Error = err.Error()
+					failed.Store(true)
+					continue
+				}
+				result.Document = &uploaded
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, result := range response.Documents {
+		if result.Document != nil {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	if request.Atomic && response.Failed > 0 {
+		u.rollbackBatch(context.WithoutCancel(ctx), &response)
+	}
+
+	return response, nil
+}
+
+// batchName is the document name of the i-th file, its file name without extension when no name is given
+func batchName(request document.RequestUploadBatch, i int, file *multipart.FileHeader) string {
+	if len(request.DocumentNames) != 0 {
+		return request.DocumentNames[i]
+	}
+	return strings.TrimSuffix(path.Base(filepath.ToSlash(file.Filename)), filepath.Ext(file.Filename))
+}
+
+// batchDocument is the upload request of the i-th file, with the attributes shared by the batch.
+// atomic batch never replace existing document, deleting it on rollback would lose the previous content
+func batchDocument(request document.RequestUploadBatch, i int, file *multipart.FileHeader) document.RequestUploadDocumentFile {
+	name := batchName(request, i, file)
+
+	overwrite := request.Overwrite
+	if request.Atomic {
+		key := fmt.Sprintf("%s/%s%s", request.DocumentKey, name, filepath.Ext(file.Filename))
+		// an unknown policy fail the upload of the file itself
+		if policy, err := overwritePolicy(overwrite, key); err == nil && policy == constant.OVERWRITE_ALLOW {
+			overwrite = constant.OVERWRITE_REJECT
+		}
+	}
+
+	upload := document.RequestUploadDocumentFile{
+		DocumentKey:  request.DocumentKey,
+		DocumentName: name,
+		Overwrite:    overwrite,
+		Tags:         request.Tags,
+		Metadata:     request.Metadata,
+		Visibility:   request.Visibility,
+	}
+	if len(request.ChecksumSHA256) != 0 {
+		upload.ChecksumSHA256 = request.ChecksumSHA256[i]
+	}
+	if len(request.ChecksumCRC32C) != 0 {
+		upload.ChecksumCRC32C = request.ChecksumCRC32C[i]
+	}
+	return upload
+}
+
+// rollbackBatch delete the documents uploaded by the batch, a document that cannot be deleted keep its result with the error
+func (u *usecase) rollbackBatch(ctx context.Context, response *document.ResponseUploadBatch) {
+	response.RolledBack = true
+	for i := range response.Documents {
+		result := &response.Documents[i]
+		if result.Document == nil {
+			continue
+		}
+		key, err := u.uploadedKey(ctx, *result.Document)
+		if err == nil {
+			err = u.DeleteFile(ctx, key)
+		}
+		if err != nil {
+			result.Error = fmt.Sprintf("rollback failed: %s", err.Error())
+			response.RolledBack = false
+			continue
+		}
+		result.Document = nil
+		result.Error = "rolled back, another file of the batch failed"
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"mime/multipart"
+	"os"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createMultipartFiles(t *testing.T, filenames ...string) []*multipart.FileHeader {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for _, filename := range filenames {
+		part, err := writer.CreateFormFile("files", filename)
+		require.NoError(t, err)
+		part.Write([]byte("content of " + filename))
+	}
+	writer.Close()
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form.File["files"]
+}
+
+func Test_UploadBatch(t *testing.T) {
+	os.Setenv("UPLOAD_BATCH_MAX_FILES", "3")
+	defer os.Unsetenv("UPLOAD_BATCH_MAX_FILES")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	_, err := usecase.UploadBatch(context.Background(), document.RequestUploadBatch{DocumentKey: "folder"}, createMultipartFiles(t, "a.txt", "b.txt", "c.txt", "d.txt"))
+	require.ErrorIs(t, err, document.ErrInvalidBatch)
+
+	_, err = usecase.UploadBatch(context.Background(), document.RequestUploadBatch{DocumentKey: "folder", DocumentNames: []string{"a"}}, createMultipartFiles(t, "a.txt", "b.txt"))
+	require.ErrorIs(t, err, document.ErrInvalidBatch)
+
+	mockS3Client.On("PutObject", mock.Anything, matchKey("folder/first.txt")).Return(&s3.PutObjectOutput{}, nil).Once()
+	mockS3Client.On("PutObject", mock.Anything, matchKey("folder/second.txt")).Return(nil, errors.New("connection error")).Once()
+	mockS3Client.On("PutObject", mock.Anything, matchKey("folder/third.txt")).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadBatch(context.Background(), document.RequestUploadBatch{
+		DocumentKey:   "folder",
+		DocumentNames: []string{"first", "second", "third"},
+	}, createMultipartFiles(t, "a.txt", "b.txt", "c.txt"))
+
+	require.NoError(t, err)
+	require.Equal(t, 2, response.Succeeded)
+	require.Equal(t, 1, response.Failed)
+	require.False(t, response.RolledBack)
+	require.Equal(t, "a.txt", response.Documents[0].File)
+	require.Equal(t, "folder/first.txt", response.Documents[0].Document.Key)
+	require.Equal(t, "failed to upload file: connection error", response.Documents[1].Error)
+	require.Equal(t, "folder/third.txt", response.Documents[2].Document.Key)
+}
+
+func Test_UploadBatch_Atomic(t *testing.T) {
+	os.Setenv("UPLOAD_BATCH_CONCURRENCY", "1")
+	defer os.Unsetenv("UPLOAD_BATCH_CONCURRENCY")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	// existing document is never overwritten by atomic batch
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.ToString(input.Key) == "folder/a.txt" && aws.ToString(input.IfNoneMatch) == "*"
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+	mockS3Client.On("PutObject", mock.Anything, matchKey("folder/b.txt")).Return(nil, &smithy.GenericAPIError{Code: "PreconditionFailed"}).Once()
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("folder/a.txt")).Return(&s3.HeadObjectOutput{}, nil).Once()
+	mockS3Client.On("DeleteObject", mock.Anything, matchKey("folder/a.txt")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadBatch(context.Background(), document.RequestUploadBatch{DocumentKey: "folder", Atomic: true},
+		createMultipartFiles(t, "a.txt", "b.txt", "c.txt"))
+
+	require.NoError(t, err)
+	require.True(t, response.RolledBack)
+	require.Equal(t, 1, response.Succeeded)
+	require.Equal(t, 2, response.Failed)
+	require.Nil(t, response.Documents[0].Document)
+	require.Equal(t, "rolled back, another file of the batch failed", response.Documents[0].Error)
+	require.Equal(t, document.ErrDocumentAlreadyExists.Error(), response.Documents[1].Error)
+	require.Equal(t, "not uploaded, another file of the batch failed", response.Documents[2].Error)
+}
+
+func Test_UploadBatch_Attributes(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	// the attributes of the batch are applied to every file, the checksums by position
+	sum := sha256.Sum256([]byte("content of a.txt"))
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.ToString(input.Key) == "folder/a.txt" && input.Metadata[metaVisibility] == visibilityPrivate &&
+			input.Metadata[metaTags] == "invoice" && input.Metadata["client"] == "acme"
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadBatch(context.Background(), document.RequestUploadBatch{
+		DocumentKey:    "folder",
+		Tags:           []string{"invoice"},
+		Metadata:       map[string]string{"client": "acme"},
+		Visibility:     visibilityPrivate,
+		ChecksumSHA256: []string{hex.EncodeToString(sum[:]), hex.EncodeToString(sum[:])},
+	}, createMultipartFiles(t, "a.txt", "b.txt"))
+
+	require.NoError(t, err)
+	require.Equal(t, 1, response.Succeeded)
+	require.Equal(t, document.ErrChecksumMismatch.Error(), response.Documents[1].Error)
+
+	_, err = usecase.UploadBatch(context.Background(), document.RequestUploadBatch{
+		DocumentKey:    "folder",
+		ChecksumSHA256: []string{hex.EncodeToString(sum[:])},
+	}, createMultipartFiles(t, "a.txt", "b.txt"))
+	require.ErrorIs(t, err, document.ErrInvalidBatch)
+}
+
+func Test_UploadBatch_EmptyName(t *testing.T) {
+	usecase, _ := initUseCaseUnitTest(t)
+
+	// nothing is uploaded, not even the files with a name
+	_, err := usecase.UploadBatch(context.Background(), document.RequestUploadBatch{DocumentKey: "folder", Atomic: true},
+		createMultipartFiles(t, "a.txt", ".png"))
+	require.ErrorIs(t, err, document.ErrInvalidBatch)
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"aws-s3-bucket/domain/upload/interfaces"
+	"aws-s3-bucket/models/document"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// WithCache serve hot downloads from the cache, S3 is only asked to revalidate them once the ttl elapsed
+func WithCache(cache interfaces.CacheInterface) Option {
+	return func(u *usecase) {
+		u.cache = cache
+	}
+}
+
+// cachedGetObject get the whole latest object through the cache, a range or a version always reach S3
+func (u *usecase) cachedGetObject(ctx context.Context, input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	if u.cache == nil || input.Range != nil || input.VersionId != nil || input.IfNoneMatch != nil {
+		return u.s3Client.GetObject(ctx, input)
+	}
+	bucket, key := aws.ToString(input.Bucket), aws.ToString(input.Key)
+
+	object, body, cached := u.cache.Get(bucket, key)
+	if cached && !object.Stale {
+		return cachedOutput(object, body), nil
+	}
+
+	fetch := input
+	if cached {
+		revalidate := *input
+		revalidate.IfNoneMatch = aws.String(object.ETag)
+		fetch = &revalidate
+	}
+	output, err := u.s3Client.GetObject(ctx, fetch)
+	if cached && isNotModified(err) {
+		u.cache.Revalidated(bucket, key)
+		return cachedOutput(object, body), nil
+	}
+	if cached {
+		body.Close()
+	}
+	if err != nil || output.ContentLength == nil || !u.cache.Admit(*output.ContentLength) {
+		return output, err
+	}
+
+	defer output.Body.Close()
+	object = document.CachedObject{
+		ETag:            aws.ToString(output.ETag),
+		Size:            aws.ToInt64(output.ContentLength),
+		ContentType:     aws.ToString(output.ContentType),
+		ContentEncoding: aws.ToString(output.ContentEncoding),
+		CacheControl:    aws.ToString(output.CacheControl),
+		ChecksumSHA256:  aws.ToString(output.ChecksumSHA256),
+		ChecksumCRC32C:  aws.ToString(output.ChecksumCRC32C),
+		LastModified:    output.LastModified,
+		Expires:         aws.ToString(output.ExpiresString),
+		Metadata:        output.Metadata,
+	}
+	if body, err = u.cache.Set(bucket, key, object, output.Body); err != nil {
+		// the cache is only an optimization, the body was partly read by it so the object is fetched again
+		log.Warnf("failed to cache %s/%s: %s", bucket, key, err.Error())
+		return u.s3Client.GetObject(ctx, input)
+	}
+	return cachedOutput(object, body), nil
+}
+
+// cachedOutput is the object as S3 would answer it, metadata is copied as callers may change it
+func cachedOutput(object document.CachedObject, body io.ReadCloser) *s3.GetObjectOutput {
+	return &s3.GetObjectOutput{
+		Body:            body,
+		ETag:            optionalString(object.ETag),
+		ContentLength:   aws.Int64(object.Size),
+		ContentType:     optionalString(object.ContentType),
+		ContentEncoding: optionalString(object.ContentEncoding),
+		CacheControl:    optionalString(object.CacheControl),
+		ChecksumSHA256:  optionalString(object.ChecksumSHA256),
+		ChecksumCRC32C:  optionalString(object.ChecksumCRC32C),
+		LastModified:    object.LastModified,
+		ExpiresString:   optionalString(object.Expires),
+		Metadata:        withMetadata(nil, object.Metadata),
+	}
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
+
+// isNotModified is the answer of S3 to a conditional get matching the etag
+func isNotModified(err error) bool {
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) && responseErr.HTTPStatusCode() == http.StatusNotModified {
+		return true
+	}
+	return apiErrorCode(err) == "NotModified"
+}
+
+// invalidateCache drop the cached object after it changed, nothing to do without cache
+func (u *usecase) invalidateCache(bucket, key string) {
+	if u.cache != nil {
+		u.cache.Delete(bucket, key)
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// cacheControlRule is the Cache-Control of the documents under a key prefix, or of a content type
+type cacheControlRule struct {
+	prefix       string
+	contentType  string
+	cacheControl string
+}
+
+// cacheControlRules is configured by CACHE_CONTROL_RULES as ";" separated match=value, match is
+// prefix:<key prefix>, type:<content type> where image/* match every image, or * for every document.
+// the first matching rule win, ex: prefix:logos/=public, max-age=604800;type:image/*=public, max-age=3600
+func cacheControlRules() []cacheControlRule {
+	var rules []cacheControlRule
+	for _, entry := range strings.Split(os.Getenv("CACHE_CONTROL_RULES"), ";") {
+		match, cacheControl, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || strings.TrimSpace(cacheControl) == "" {
+			continue
+		}
+		rule := cacheControlRule{cacheControl: strings.TrimSpace(cacheControl)}
+		switch match = strings.TrimSpace(match); {
+		case match == "*":
+		case strings.HasPrefix(match, "prefix:"):
+			rule.prefix = strings.TrimPrefix(match, "prefix:")
+		case strings.HasPrefix(match, "type:"):
+			rule.contentType = strings.ToLower(strings.TrimPrefix(match, "type:"))
+		default:
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func (r cacheControlRule) match(key, contentType string) bool {
+	if r.prefix != "" {
+		return strings.HasPrefix(key, r.prefix)
+	}
+	if r.contentType != "" {
+		// parameters as charset are not part of the type
+		contentType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+		matched, _ := path.Match(r.contentType, contentType)
+		return matched
+	}
+	return true
+}
+
+// withCacheControl honor the Cache-Control stored on the object, otherwise the visibility decide
+// and last the configured rules
+func withCacheControl(response *s3.GetObjectOutput, key string) *s3.GetObjectOutput {
+	if response.CacheControl != nil {
+		return response
+	}
+	if cacheControl := visibilityCacheControl(response.Metadata); cacheControl != "" {
+		response.CacheControl = aws.String(cacheControl)
+		return response
+	}
+	for _, rule := range cacheControlRules() {
+		if rule.match(key, aws.ToString(response.ContentType)) {
+			response.CacheControl = aws.String(rule.cacheControl)
+			break
+		}
+	}
+	return response
+}
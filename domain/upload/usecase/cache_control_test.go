@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DownloadFile_CacheControl(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	os.Setenv("DOWNLOAD_SIGNING_KEYS", "k1:secret")
+	defer os.Unsetenv("DOWNLOAD_SIGNING_KEYS")
+	os.Setenv("CACHE_CONTROL_RULES", "prefix:logos/=public, max-age=604800; type:image/*=public, max-age=3600;invalid;*=no-cache")
+	defer os.Unsetenv("CACHE_CONTROL_RULES")
+
+	tests := []struct {
+		name     string
+		key      string
+		output   s3.GetObjectOutput
+		signed   bool
+		expected string
+	}{
+		{name: "Prefix", key: "logos/brand.png", output: s3.GetObjectOutput{ContentType: aws.String("image/png")}, expected: "public, max-age=604800"},
+		{name: "ContentType", key: "photos/cat.jpg", output: s3.GetObjectOutput{ContentType: aws.String("image/jpeg")}, expected: "public, max-age=3600"},
+		{name: "Fallback", key: "data/report.csv", output: s3.GetObjectOutput{ContentType: aws.String("text/csv; charset=utf-8")}, expected: "no-cache"},
+		{
+			name:     "Stored",
+			key:      "logos/stored.png",
+			output:   s3.GetObjectOutput{ContentType: aws.String("image/png"), CacheControl: aws.String("max-age=60")},
+			expected: "max-age=60",
+		},
+		{
+			name:     "Private",
+			key:      "logos/private.png",
+			output:   s3.GetObjectOutput{ContentType: aws.String("image/png"), Metadata: map[string]string{metaVisibility: visibilityPrivate}},
+			signed:   true,
+			expected: "private, no-store",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := test.output
+			output.Body = io.NopCloser(strings.NewReader("content"))
+			mockS3Client.On("GetObject", mock.Anything, matchKey(test.key)).Return(&output, nil).Once()
+
+			request := document.RequestDownloadDocument{Key: test.key}
+			if test.signed {
+				link, err := usecase.SignDownloadLink(context.Background(), document.RequestSignDownload{Key: test.key})
+				require.NoError(t, err)
+				request = linkRequest(t, link.Url, "")
+			}
+
+			response, err := usecase.DownloadFile(context.Background(), request)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, aws.ToString(response.CacheControl))
+		})
+	}
+}
+
+func Test_DownloadFile_DecompressedValidators(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	compressed, err := utils.Compress([]byte(testCsv), utils.EncodingGzip)
+	require.NoError(t, err)
+	mockS3Client.On("GetObject", mock.Anything, matchKey("exports/report.csv")).Return(&s3.GetObjectOutput{
+		Body:            io.NopCloser(strings.NewReader(string(compressed))),
+		ContentType:     aws.String("text/csv"),
+		ContentEncoding: aws.String("gzip"),
+		ETag:            aws.String(`"abc"`),
+		Metadata:        map[string]string{metaUncompressedSize: "2900"},
+	}, nil).Once()
+
+	response, err := usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "exports/report.csv"})
+	require.NoError(t, err)
+	require.Equal(t, `W/"abc"`, aws.ToString(response.ETag))
+	require.Equal(t, "Accept-Encoding", response.ResultMetadata.Get(document.ResultVary{}))
+}
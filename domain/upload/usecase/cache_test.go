@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"aws-s3-bucket/domain/upload/cache"
+	"aws-s3-bucket/domain/upload/interfaces"
+	"aws-s3-bucket/domain/upload/interfaces/mocks"
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func initCacheUnitTest(t *testing.T, ttl time.Duration) (interfaces.UsecaseInterface, *mocks.S3Interface) {
+	_, mockS3Client := initUseCaseUnitTest(t)
+	downloadCache, err := cache.New(cache.Config{TTL: ttl, MemorySize: 1 << 10, MemoryMaxObject: 1 << 10})
+	require.NoError(t, err)
+	return NewUsecase(mockS3Client, WithCache(downloadCache)), mockS3Client
+}
+
+func objectOutput(content string) *s3.GetObjectOutput {
+	return &s3.GetObjectOutput{
+		Body:          io.NopCloser(strings.NewReader(content)),
+		ETag:          aws.String(`"v1"`),
+		ContentType:   aws.String("text/plain"),
+		ContentLength: aws.Int64(int64(len(content))),
+	}
+}
+
+func download(t *testing.T, usecase interfaces.UsecaseInterface, key string) string {
+	response, err := usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: key})
+	require.NoError(t, err)
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Equal(t, "text/plain", aws.ToString(response.ContentType))
+	return string(content)
+}
+
+func Test_DownloadFile_Cached(t *testing.T) {
+	usecase, mockS3Client := initCacheUnitTest(t, time.Hour)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/logo.txt")).Return(objectOutput("logo"), nil).Once()
+	require.Equal(t, "logo", download(t, usecase, "data/logo.txt"))
+	require.Equal(t, "logo", download(t, usecase, "data/logo.txt"))
+
+	// upload replace the cached object
+	mockS3Client.On("PutObject", mock.Anything, mock.Anything).Return(&s3.PutObjectOutput{}, nil).Once()
+	_, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "logo",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+	})
+	require.NoError(t, err)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/logo.txt")).Return(objectOutput("new logo"), nil).Once()
+	require.Equal(t, "new logo", download(t, usecase, "data/logo.txt"))
+
+	// range is not cached
+	mockS3Client.On("GetObject", mock.Anything, mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return aws.ToString(input.Range) == "bytes=0-1"
+	})).Return(objectOutput("ne"), nil).Once()
+	_, err = usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/logo.txt", Range: "bytes=0-1"})
+	require.NoError(t, err)
+}
+
+func Test_DownloadFile_CacheRevalidate(t *testing.T) {
+	usecase, mockS3Client := initCacheUnitTest(t, 0)
+
+	mockS3Client.On("GetObject", mock.Anything, mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return aws.ToString(input.Key) == "data/logo.txt" && input.IfNoneMatch == nil
+	})).Return(objectOutput("logo"), nil).Once()
+	require.Equal(t, "logo", download(t, usecase, "data/logo.txt"))
+
+	notModified := func(input *s3.GetObjectInput) bool {
+		return aws.ToString(input.Key) == "data/logo.txt" && aws.ToString(input.IfNoneMatch) == `"v1"`
+	}
+	mockS3Client.On("GetObject", mock.Anything, mock.MatchedBy(notModified)).Return(nil, &smithy.GenericAPIError{Code: "NotModified"}).Once()
+	require.Equal(t, "logo", download(t, usecase, "data/logo.txt"))
+
+	mockS3Client.On("GetObject", mock.Anything, mock.MatchedBy(notModified)).Return(objectOutput("new logo"), nil).Once()
+	require.Equal(t, "new logo", download(t, usecase, "data/logo.txt"))
+}
+
+func Test_DownloadFile_CacheSetFailed(t *testing.T) {
+	_, mockS3Client := initUseCaseUnitTest(t)
+	mockCache := mocks.NewCacheInterface(t)
+	usecase := NewUsecase(mockS3Client, WithCache(mockCache))
+
+	mockCache.On("Get", "test-bucket", "data/logo.txt").Return(document.CachedObject{}, nil, false).Once()
+	mockCache.On("Admit", int64(4)).Return(true).Once()
+	mockCache.On("Set", "test-bucket", "data/logo.txt", mock.Anything, mock.Anything).Return(nil, errors.New("no space left on device")).Once()
+
+	// the object is fetched again and served without the cache
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/logo.txt")).Return(objectOutput("logo"), nil).Once()
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/logo.txt")).Return(objectOutput("logo"), nil).Once()
+	require.Equal(t, "logo", download(t, usecase, "data/logo.txt"))
+}
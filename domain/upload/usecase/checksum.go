@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// checksumAlgorithm is algorithm sent to S3 with every object, configured by CHECKSUM_ALGORITHM (SHA256 or CRC32C)
+func checksumAlgorithm() types.ChecksumAlgorithm {
+	switch strings.ToUpper(os.Getenv("CHECKSUM_ALGORITHM")) {
+	case "SHA256":
+		return types.ChecksumAlgorithmSha256
+	case "CRC32C":
+		return types.ChecksumAlgorithmCrc32c
+	}
+	return ""
+}
+
+// computeChecksum hash the document once and compare it with checksum sent by client
+func computeChecksum(doc upload) (checksum utils.Checksum, err error) {
+	algorithm := checksumAlgorithm()
+	withSHA256 := algorithm == types.ChecksumAlgorithmSha256 || doc.expectedSHA256 != "" || dedupeEnabled()
+	withCRC32C := algorithm == types.ChecksumAlgorithmCrc32c || doc.expectedCRC32C != ""
+	if !withSHA256 && !withCRC32C {
+		return
+	}
+
+	checksum, err = utils.ComputeChecksum(doc.body, withSHA256, withCRC32C)
+	if err != nil {
+		err = fmt.Errorf("failed to compute checksum: %w", err)
+		return
+	}
+	if err = rewind(doc.body); err != nil {
+		return
+	}
+
+	if err = matchChecksum(doc.expectedSHA256, sha256.Size, checksum.SHA256); err != nil {
+		return
+	}
+	err = matchChecksum(doc.expectedCRC32C, crc32.Size, checksum.CRC32C)
+	return
+}
+
+func matchChecksum(expected string, size int, actual []byte) error {
+	if expected == "" {
+		return nil
+	}
+	decoded, err := utils.DecodeChecksum(expected, size)
+	if err != nil {
+		return document.ErrInvalidChecksum
+	}
+	if !bytes.Equal(decoded, actual) {
+		return document.ErrChecksumMismatch
+	}
+	return nil
+}
+
+// withChecksum let S3 verify the content we send
+func withChecksum(input *s3.PutObjectInput, checksum utils.Checksum) *s3.PutObjectInput {
+	switch checksumAlgorithm() {
+	case types.ChecksumAlgorithmSha256:
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+		input.ChecksumSHA256 = aws.String(base64.StdEncoding.EncodeToString(checksum.SHA256))
+	case types.ChecksumAlgorithmCrc32c:
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmCrc32c
+		input.ChecksumCRC32C = aws.String(base64.StdEncoding.EncodeToString(checksum.CRC32C))
+	}
+	return input
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// checksum of "This is test content"
+const (
+	testContentSHA256       = "726df8bcc21cb319dde031e10a3ab40ee5ce4979cef01451a9be341fec8e8153"
+	testContentSHA256Base64 = "cm34vMIcsxnd4DHhCjq0DuXOSXnO8BRRqb40H+yOgVM="
+	testContentCRC32CBase64 = "/t6Slg=="
+)
+
+func Test_UploadBase64_Checksum(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	defer os.Unsetenv("CHECKSUM_ALGORITHM")
+
+	type args struct {
+		algorithm string
+		sha256    string
+		crc32c    string
+	}
+	type expected struct {
+		err      error
+		response document.ResponseUploadDocument
+	}
+	tests := []struct {
+		name     string
+		args     args
+		prepare  func()
+		expected expected
+	}{
+		{
+			name: "SHA256_SentToS3",
+			args: args{algorithm: "SHA256", sha256: testContentSHA256Base64},
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+					return input.ChecksumAlgorithm == types.ChecksumAlgorithmSha256 && aws.ToString(input.ChecksumSHA256) == testContentSHA256Base64
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadDocument{
+					DocumentUrl:    fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), "data/example.txt"),
+					Key:            "data/example.txt",
+					Bucket:         "test-bucket",
+					Size:           20,
+					ContentType:    "text/txt",
+					ChecksumSHA256: testContentSHA256Base64,
+				},
+			},
+		},
+		{
+			name: "CRC32C_SentToS3",
+			args: args{algorithm: "crc32c", crc32c: "fede9296"},
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+					return input.ChecksumAlgorithm == types.ChecksumAlgorithmCrc32c && aws.ToString(input.ChecksumCRC32C) == testContentCRC32CBase64
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadDocument{
+					DocumentUrl:    fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), "data/example.txt"),
+					Key:            "data/example.txt",
+					Bucket:         "test-bucket",
+					Size:           20,
+					ContentType:    "text/txt",
+					ChecksumCRC32C: testContentCRC32CBase64,
+				},
+			},
+		},
+		{
+			name: "SHA256_Mismatch",
+			args: args{sha256: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="},
+			expected: expected{
+				err: document.ErrChecksumMismatch,
+			},
+		},
+		{
+			name: "CRC32C_InvalidFormat",
+			args: args{crc32c: "not-a-checksum"},
+			expected: expected{
+				err: document.ErrInvalidChecksum,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("CHECKSUM_ALGORITHM", tt.args.algorithm)
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+
+			response, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+				DocumentKey:    "data",
+				DocumentName:   "example",
+				DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+				ChecksumSHA256: tt.args.sha256,
+				ChecksumCRC32C: tt.args.crc32c,
+			})
+
+			require.Equal(t, tt.expected.err, err)
+			require.Equal(t, tt.expected.response, response)
+		})
+	}
+}
+
+func Test_DownloadFile_VerifyChecksum(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	mockS3Client.On("GetObject", mock.Anything, mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+		return input.ChecksumMode == types.ChecksumModeEnabled
+	})).Return(&s3.GetObjectOutput{
+		Body:           io.NopCloser(bytes.NewReader([]byte("This is corrupted content"))),
+		ChecksumSHA256: aws.String(testContentSHA256Base64),
+	}, nil).Once()
+
+	response, err := usecase.DownloadFile(nil, document.RequestDownloadDocument{Key: "data/example.txt"})
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(response.Body)
+	require.Equal(t, fmt.Errorf("checksum mismatch"), err)
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	metaUncompressedSize = "uncompressed-size"
+
+	defaultCompressionMinSize = 1024
+)
+
+// compressionEncoding is COMPRESSION_ENCODING (gzip or br), empty disable the compression of stored object
+func compressionEncoding() string {
+	switch encoding := strings.ToLower(os.Getenv("COMPRESSION_ENCODING")); encoding {
+	case utils.EncodingGzip, utils.EncodingBrotli:
+		return encoding
+	}
+	return ""
+}
+
+// compressible tell whether content type is listed in COMPRESSION_CONTENT_TYPES (ex: "application/json,text/*"),
+// every text content type by default
+func compressible(contentType string) bool {
+	contentType = mediaType(contentType)
+	allowed := os.Getenv("COMPRESSION_CONTENT_TYPES")
+	if allowed == "" {
+		return isTextContentType(contentType)
+	}
+	for _, candidate := range strings.Split(allowed, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == contentType || (strings.HasSuffix(candidate, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(candidate, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressUpload replace the body of compressible document bigger than COMPRESSION_MIN_SIZE by its compressed bytes,
+// the document is kept as is when compression does not make it smaller
+func compressUpload(doc upload) (upload, error) {
+	encoding := compressionEncoding()
+	if encoding == "" || !compressible(doc.contentType) {
+		return doc, nil
+	}
+
+	minSize, err := strconv.ParseInt(os.Getenv("COMPRESSION_MIN_SIZE"), 10, 64)
+	if err != nil || minSize < 0 {
+		minSize = defaultCompressionMinSize
+	}
+	size, err := contentLength(doc.body)
+	if err != nil || size < minSize {
+		return doc, err
+	}
+
+	data, err := io.ReadAll(doc.body)
+	if err != nil {
+		return doc, fmt.Errorf("failed to read document: %w", err)
+	}
+	if err = rewind(doc.body); err != nil {
+		return doc, err
+	}
+
+	compressed, err := utils.Compress(data, encoding)
+	if err != nil {
+		return doc, err
+	}
+	if len(compressed) >= len(data) {
+		return doc, nil
+	}
+
+	// the checksum sent by client is about the uncompressed bytes, S3 get the one of the compressed bytes
+	if doc, err = verifyClientChecksum(doc, data); err != nil {
+		return doc, err
+	}
+
+	doc.body = bytes.NewReader(compressed)
+	doc.contentEncoding = encoding
+	doc.metadata = withMetadata(doc.metadata, map[string]string{
+		metaUncompressedSize: strconv.Itoa(len(data)),
+	})
+	return doc, nil
+}
+
+// verifyClientChecksum compare checksum sent by client with data before the body is transformed,
+// they are cleared so they are not compared anymore with the transformed body
+func verifyClientChecksum(doc upload, data []byte) (upload, error) {
+	if doc.expectedSHA256 == "" && doc.expectedCRC32C == "" {
+		return doc, nil
+	}
+
+	checksum, err := utils.ComputeChecksum(bytes.NewReader(data), doc.expectedSHA256 != "", doc.expectedCRC32C != "")
+	if err != nil {
+		return doc, fmt.Errorf("failed to compute checksum: %w", err)
+	}
+	if err = matchChecksum(doc.expectedSHA256, sha256.Size, checksum.SHA256); err != nil {
+		return doc, err
+	}
+	if err = matchChecksum(doc.expectedCRC32C, crc32.Size, checksum.CRC32C); err != nil {
+		return doc, err
+	}
+	doc.expectedSHA256, doc.expectedCRC32C = "", ""
+	return doc, nil
+}
+
+// decodeResponse serve the stored bytes when client accept their encoding, otherwise decompress them
+// and apply the requested range on the decompressed content
+func (u *usecase) decodeResponse(ctx context.Context, input *s3.GetObjectInput, request document.RequestDownloadDocument, response *s3.GetObjectOutput) (*s3.GetObjectOutput, error) {
+	encoding := aws.ToString(response.ContentEncoding)
+	if (encoding != utils.EncodingGzip && encoding != utils.EncodingBrotli) || utils.AcceptsEncoding(request.AcceptEncoding, encoding) {
+		return response, nil
+	}
+
+	uncompressedSize, sizeErr := strconv.ParseInt(response.Metadata[metaUncompressedSize], 10, 64)
+	if response.ContentRange != nil {
+		// the range is about compressed bytes, fetch the whole object again
+		response.Body.Close()
+		var err error
+		if response, err = u.getObject(ctx, &s3.GetObjectInput{
+			Bucket:       input.Bucket,
+			Key:          input.Key,
+			VersionId:    input.VersionId,
+			ChecksumMode: types.ChecksumModeEnabled,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := utils.NewDecompressReader(
+		utils.NewVerifyingReader(response.Body, aws.ToString(response.ChecksumSHA256), aws.ToString(response.ChecksumCRC32C)), encoding)
+	if err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+
+	response.Body = body
+	response.ContentEncoding = nil
+	response.ChecksumSHA256, response.ChecksumCRC32C = nil, nil
+	// another Accept-Encoding get the compressed bytes, the decompressed content is only equivalent to them
+	response.ResultMetadata.Set(document.ResultVary{}, "Accept-Encoding")
+	if etag := aws.ToString(response.ETag); etag != "" && !strings.HasPrefix(etag, "W/") {
+		response.ETag = aws.String("W/" + etag)
+	}
+	response.ContentLength = nil
+	if sizeErr == nil {
+		response.ContentLength = aws.Int64(uncompressedSize)
+	}
+
+	// range on decompressed content need its total size, ignored otherwise
+	if request.Range == "" || sizeErr != nil {
+		return response, nil
+	}
+	start, end, ok, err := utils.ParseRange(request.Range, uncompressedSize)
+	if errors.Is(err, utils.ErrRangeNotSatisfiable) {
+		body.Close()
+		return nil, fmt.Errorf("%w: %s", document.ErrRangeNotSatisfiable, request.Range)
+	}
+	if !ok {
+		return response, nil
+	}
+	if _, err = io.CopyN(io.Discard, body, start); err != nil {
+		body.Close()
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	response.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(body, end-start+1), body}
+	response.ContentLength = aws.Int64(end - start + 1)
+	response.ContentRange = aws.String(fmt.Sprintf("bytes %d-%d/%d", start, end, uncompressedSize))
+	return response, nil
+}
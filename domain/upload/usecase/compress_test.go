@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testCsv = strings.Repeat("id,name,amount\n1,example,100\n", 100)
+
+func Test_Compressible(t *testing.T) {
+	require.True(t, compressible("application/json"))
+	require.True(t, compressible("text/csv; charset=utf-8"))
+	require.False(t, compressible("image/png"))
+
+	os.Setenv("COMPRESSION_CONTENT_TYPES", "application/json, text/*")
+	defer os.Unsetenv("COMPRESSION_CONTENT_TYPES")
+
+	require.True(t, compressible("text/csv"))
+	require.False(t, compressible("application/xml"))
+}
+
+func Test_CompressUpload(t *testing.T) {
+	os.Setenv("COMPRESSION_ENCODING", "br")
+	defer os.Unsetenv("COMPRESSION_ENCODING")
+
+	doc, err := compressUpload(upload{key: "exports/a.csv", body: strings.NewReader(testCsv), contentType: "text/csv"})
+	require.NoError(t, err)
+	require.Equal(t, utils.EncodingBrotli, doc.contentEncoding)
+	require.Equal(t, "2900", doc.metadata[metaUncompressedSize])
+
+	// too small to be worth it
+	doc, err = compressUpload(upload{key: "exports/a.csv", body: strings.NewReader("id\n1\n"), contentType: "text/csv"})
+	require.NoError(t, err)
+	require.Empty(t, doc.contentEncoding)
+
+	doc, err = compressUpload(upload{key: "exports/a.png", body: strings.NewReader(testCsv), contentType: "image/png"})
+	require.NoError(t, err)
+	require.Empty(t, doc.contentEncoding)
+
+	// checksum of client is about the uncompressed bytes
+	sum := sha256.Sum256([]byte(testCsv))
+	doc, err = compressUpload(upload{key: "exports/a.csv", body: strings.NewReader(testCsv), contentType: "text/csv", expectedSHA256: base64.StdEncoding.EncodeToString(sum[:])})
+	require.NoError(t, err)
+	require.Empty(t, doc.expectedSHA256)
+
+	_, err = compressUpload(upload{key: "exports/a.csv", body: strings.NewReader(testCsv), contentType: "text/csv", expectedSHA256: base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))})
+	require.ErrorIs(t, err, document.ErrChecksumMismatch)
+}
+
+func Test_UploadBase64_Compressed(t *testing.T) {
+	os.Setenv("COMPRESSION_ENCODING", "gzip")
+	defer os.Unsetenv("COMPRESSION_ENCODING")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	var stored []byte
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		stored, _ = io.ReadAll(input.Body)
+		return aws.ToString(input.ContentEncoding) == "gzip" && aws.ToString(input.ContentType) == "text/csv"
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "exports",
+		DocumentName:   "report",
+		DocumentBase64: "data:text/csv;base64," + base64.StdEncoding.EncodeToString([]byte(testCsv)),
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "gzip", response.ContentEncoding)
+	require.Equal(t, int64(len(testCsv)), response.Size)
+
+	reader, err := utils.NewDecompressReader(io.NopCloser(bytes.NewReader(stored)), "gzip")
+	require.NoError(t, err)
+	decompressed, _ := io.ReadAll(reader)
+	require.Equal(t, testCsv, string(decompressed))
+}
+
+func Test_DownloadFile_Compressed(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	compressed, err := utils.Compress([]byte(testCsv), utils.EncodingGzip)
+	require.NoError(t, err)
+	stored := func() *s3.GetObjectOutput {
+		return &s3.GetObjectOutput{
+			Body:            io.NopCloser(bytes.NewReader(compressed)),
+			ContentType:     aws.String("text/csv"),
+			ContentEncoding: aws.String("gzip"),
+			ContentLength:   aws.Int64(int64(len(compressed))),
+			Metadata:        map[string]string{metaUncompressedSize: "2900"},
+		}
+	}
+	withRange := func(byteRange string) interface{} {
+		return mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+			return aws.ToString(input.Range) == byteRange
+		})
+	}
+
+	type expected struct {
+		err           error
+		body          string
+		encoding      string
+		contentLength int64
+		contentRange  string
+	}
+	tests := []struct {
+		name     string
+		request  document.RequestDownloadDocument
+		prepare  func()
+		expected expected
+	}{
+		{
+			name:    "Accepted",
+			request: document.RequestDownloadDocument{Key: "exports/report.csv", AcceptEncoding: "gzip, br"},
+			prepare: func() {
+				mockS3Client.On("GetObject", mock.Anything, withRange("")).Return(stored(), nil).Once()
+			},
+			expected: expected{body: string(compressed), encoding: "gzip", contentLength: int64(len(compressed))},
+		},
+		{
+			name:    "AcceptedRange",
+			request: document.RequestDownloadDocument{Key: "exports/report.csv", AcceptEncoding: "gzip", Range: "bytes=0-9"},
+			prepare: func() {
+				output := stored()
+				output.Body = io.NopCloser(bytes.NewReader(compressed[:10]))
+				output.ContentLength = aws.Int64(10)
+				output.ContentRange = aws.String(fmt.Sprintf("bytes 0-9/%d", len(compressed)))
+				mockS3Client.On("GetObject", mock.Anything, withRange("bytes=0-9")).Return(output, nil).Once()
+			},
+			expected: expected{body: string(compressed[:10]), encoding: "gzip", contentLength: 10, contentRange: fmt.Sprintf("bytes 0-9/%d", len(compressed))},
+		},
+		{
+			name:    "Decompressed",
+			request: document.RequestDownloadDocument{Key: "exports/report.csv"},
+			prepare: func() {
+				mockS3Client.On("GetObject", mock.Anything, withRange("")).Return(stored(), nil).Once()
+			},
+			expected: expected{body: testCsv, contentLength: 2900},
+		},
+		{
+			name:    "DecompressedRange",
+			request: document.RequestDownloadDocument{Key: "exports/report.csv", AcceptEncoding: "br", Range: "bytes=15-28"},
+			prepare: func() {
+				partial := stored()
+				partial.ContentRange = aws.String("bytes 15-28/100")
+				mockS3Client.On("GetObject", mock.Anything, withRange("bytes=15-28")).Return(partial, nil).Once()
+				mockS3Client.On("GetObject", mock.Anything, withRange("")).Return(stored(), nil).Once()
+			},
+			expected: expected{body: "1,example,100\n", contentLength: 14, contentRange: "bytes 15-28/2900"},
+		},
+		{
+			name:    "DecompressedRangeNotSatisfiable",
+			request: document.RequestDownloadDocument{Key: "exports/report.csv", Range: "bytes=5000-"},
+			prepare: func() {
+				mockS3Client.On("GetObject", mock.Anything, withRange("bytes=5000-")).Return(nil, &smithy.GenericAPIError{Code: "InvalidRange"}).Once()
+			},
+			expected: expected{err: document.ErrRangeNotSatisfiable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			response, err := usecase.DownloadFile(context.Background(), tt.request)
+			if tt.expected.err != nil {
+				require.ErrorIs(t, err, tt.expected.err)
+				return
+			}
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected.body, string(body))
+			require.Equal(t, tt.expected.encoding, aws.ToString(response.ContentEncoding))
+			require.Equal(t, tt.expected.contentLength, aws.ToInt64(response.ContentLength))
+			require.Equal(t, tt.expected.contentRange, aws.ToString(response.ContentRange))
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/constant"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// maxCopyObjectSize is the limit of CopyObject, bigger object is copied part by part
+	maxCopyObjectSize = 5 << 30
+
+	defaultCopyPartSize = 512 << 20
+	minCopyPartSize     = 5 << 20
+	maxCopyParts        = 10000
+)
+
+// allowedBucket resolve the bucket of request, BUCKET_NAME when empty. other buckets must be listed in ALLOWED_BUCKETS
+func allowedBucket(bucket string) (string, error) {
+	defaultBucket := os.Getenv("BUCKET_NAME")
+	if bucket == "" || bucket == defaultBucket {
+		return defaultBucket, nil
+	}
+	for _, allowed := range strings.Split(os.Getenv("ALLOWED_BUCKETS"), ",") {
+		if strings.TrimSpace(allowed) == bucket {
+			return bucket, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", document.ErrBucketNotAllowed, bucket)
+}
+
+// copyPartSize is COPY_PART_SIZE (bytes), raised when needed to stay under the S3 limit of parts
+func copyPartSize(size int64) int64 {
+	partSize, err := strconv.ParseInt(os.Getenv("COPY_PART_SIZE"), 10, 64)
+	if err != nil || partSize < minCopyPartSize {
+		partSize = defaultCopyPartSize
+	}
+	if minimum := (size + maxCopyParts - 1) / maxCopyParts; partSize < minimum {
+		partSize = minimum
+	}
+	return partSize
+}
+
+type copyObject struct {
+	sourceBucket, sourceKey, sourceVersionId string
+	bucket, key                              string
+	size                                     int64
+	replace                                  bool
+	metadata                                 map[string]string
+	contentType                              string
+	contentEncoding                          string
+	cacheControl                             string
+	acl                                      types.ObjectCannedACL
+}
+
+// CopyDocument copy the document server side, the content never go through the service
+func (u *usecase) CopyDocument(ctx context.Context, request document.RequestCopyDocument) (response document.ResponseUploadDocument, err error) {
+	response, _, err = u.copyDocument(ctx, request, false)
+	return
+}
+
+// MoveDocument copy the document then delete the source, the source is kept when the copy failed
+func (u *usecase) MoveDocument(ctx context.Context, request document.RequestCopyDocument) (response document.ResponseUploadDocument, err error) {
+	if request.SourceVersionId != "" {
+		return response, fmt.Errorf("%w: a version cannot be moved, copy it instead", document.ErrInvalidCopy)
+	}
+
+	response, sourceBucket, err := u.copyDocument(ctx, request, true)
+	if err != nil {
+		return
+	}
+	if sourceBucket == os.Getenv("BUCKET_NAME") {
+		// release blob reference and renditions of the source as a delete does
+		err = u.DeleteFile(ctx, request.SourceKey)
+	} else {
+		_, err = u.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(sourceBucket),
+			Key:    aws.String(request.SourceKey),
+		})
+		if err == nil {
+			u.invalidateCache(sourceBucket, request.SourceKey)
+			err = u.deleteRecord(ctx, sourceBucket, request.SourceKey)
+		}
+	}
+	if err != nil {
+		return document.ResponseUploadDocument{}, fmt.Errorf("failed to delete source: %w", err)
+	}
+	return response, nil
+}
+
+// copyDocument copy the document, keepId move the opaque id of the source to the copy
+func (u *usecase) copyDocument(ctx context.Context, request document.RequestCopyDocument, keepId bool) (response document.ResponseUploadDocument, sourceBucket string, err error) {
+
+	if sourceBucket, err = allowedBucket(request.SourceBucket); err != nil {
+		return
+	}
+	bucket, err := allowedBucket(request.DestinationBucket)
+	if err != nil {
+		return
+	}
+	if isInternalKey(request.SourceKey) || isInternalKey(request.DestinationKey) {
+		return response, sourceBucket, fmt.Errorf("%w: %s", document.ErrDocumentNotFound, request.SourceKey)
+	}
+	if sourceBucket == bucket && request.SourceKey == request.DestinationKey && request.SourceVersionId == "" && request.MetadataDirective != "replace" {
+		return response, sourceBucket, fmt.Errorf("%w: source and destination are the same", document.ErrInvalidCopy)
+	}
+
+	// replaced metadata are checked as the ones of an upload, a client cannot set the ones of the service
+	metadata, err := requestMetadata(nil, request.Metadata)
+	if err != nil {
+		return response, sourceBucket, err
+	}
+
+	headInput := &s3.HeadObjectInput{
+		Bucket: aws.String(sourceBucket),
+		Key:    aws.String(request.SourceKey),
+	}
+	if request.SourceVersionId != "" {
+		headInput.VersionId = aws.String(request.SourceVersionId)
+	}
+	head, err := u.s3Client.HeadObject(ctx, headInput)
+	if isNotFound(err) {
+		return response, sourceBucket, fmt.Errorf("%w: %s", document.ErrDocumentNotFound, request.SourceKey)
+	}
+	if err != nil {
+		return response, sourceBucket, fmt.Errorf("failed to check document: %w", err)
+	}
+
+	object := copyObject{
+		sourceBucket:    sourceBucket,
+		sourceKey:       request.SourceKey,
+		sourceVersionId: request.SourceVersionId,
+		bucket:          bucket,
+		size:            aws.ToInt64(head.ContentLength),
+		replace:         request.MetadataDirective == "replace",
+		metadata:        head.Metadata,
+		contentType:     aws.ToString(head.ContentType),
+		contentEncoding: aws.ToString(head.ContentEncoding),
+		cacheControl:    aws.ToString(head.CacheControl),
+	}
+	if object.replace {
+		// the visibility and the filename describe the document, they are kept with the metadata of the request
+		object.metadata = withMetadata(metadata, inheritedOf(head.Metadata))
+		if request.ContentType != "" {
+			object.contentType = request.ContentType
+		}
+	}
+	contentType := object.contentType
+
+	// pointer of deduplicated document is copied with its dedupe metadata when the blob is reachable,
+	// otherwise the blob itself is copied as a regular object
+	blobKey := pointerBlob(head.Metadata)
+	if blobKey != "" && bucket == sourceBucket {
+		object.metadata = withMetadata(object.metadata, map[string]string{
+			metaDedupeBlob:   blobKey,
+			metaDedupeSHA256: head.Metadata[metaDedupeSHA256],
+			metaDedupeSize:   head.Metadata[metaDedupeSize],
+		})
+		object.contentType = pointerContentType
+		object.replace = true
+		contentType = ""
+	} else if blobKey != "" {
+		blob, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(sourceBucket), Key: aws.String(blobKey)})
+		if err != nil {
+			return response, sourceBucket, fmt.Errorf("failed to check document: %w", err)
+		}
+		object.sourceKey, object.sourceVersionId, object.size = blobKey, "", aws.ToInt64(blob.ContentLength)
+		object.metadata = withoutDedupeMetadata(object.metadata)
+		object.contentType = aws.ToString(blob.ContentType)
+		object.contentEncoding = aws.ToString(blob.ContentEncoding)
+		object.replace = true
+		contentType = object.contentType
+	}
+
+	// the copy is a new document with its own opaque id, only the default bucket is served by id
+	documentId := newDocumentId()
+	if keepId && sourceBucket == bucket && head.Metadata[metaDocumentId] != "" {
+		documentId = head.Metadata[metaDocumentId]
+	}
+	if bucket != os.Getenv("BUCKET_NAME") {
+		documentId = ""
+	}
+	if object.metadata[metaDocumentId] != documentId {
+		object.metadata = withMetadata(object.metadata, map[string]string{metaDocumentId: documentId})
+		if documentId == "" {
+			delete(object.metadata, metaDocumentId)
+		}
+		object.replace = true
+	}
+
+	// ACL is not copied, a public document is made public again
+	object.acl = visibilityACL(object.metadata)
+
+	if err = checkPublicPrefix(request.DestinationKey, object.metadata[metaVisibility]); err != nil {
+		return response, sourceBucket, err
+	}
+
+	policy, err := overwritePolicy(request.Overwrite, request.DestinationKey)
+	if err != nil {
+		return response, sourceBucket, err
+	}
+	if object.key, err = u.copyDestination(ctx, bucket, request.DestinationKey, policy); err != nil {
+		return response, sourceBucket, err
+	}
+
+	// blob reference only exist in the default bucket
+	var previousBlob string
+	if bucket == os.Getenv("BUCKET_NAME") && policy == constant.OVERWRITE_ALLOW && (dedupeEnabled() || blobKey != "") {
+		previousBlob = u.previousBlob(ctx, bucket, object.key)
+	}
+
+	etag, versionId, err := u.copy(ctx, object)
+	if err != nil {
+		return response, sourceBucket, fmt.Errorf("failed to copy document: %w", err)
+	}
+	u.invalidateCache(bucket, object.key)
+
+	if blobKey != "" && bucket == sourceBucket {
+		_, err = u.s3Client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(refKey(blobKey, object.key)),
+			Body:   bytes.NewReader(nil),
+		})
+		if err != nil {
+			return response, sourceBucket, fmt.Errorf("failed to reference blob: %w", err)
+		}
+	}
+	if previousBlob != "" && previousBlob != blobKey {
+		if err = u.releaseBlob(ctx, bucket, previousBlob, object.key); err != nil {
+			return response, sourceBucket, err
+		}
+	}
+	if documentId != "" {
+		if err = u.putDocumentId(ctx, bucket, documentId, object.key); err != nil {
+			return response, sourceBucket, err
+		}
+	}
+
+	response = document.ResponseUploadDocument{
+		Id:          documentId,
+		Key:         object.key,
+		Bucket:      bucket,
+		ETag:        etag,
+		VersionId:   versionId,
+		Size:        object.size,
+		ContentType: contentType,
+		Visibility:  object.metadata[metaVisibility],
+	}
+	if dedupeSize, err := strconv.ParseInt(head.Metadata[metaDedupeSize], 10, 64); err == nil {
+		response.Size = dedupeSize
+	}
+	// only the default bucket is served by the download endpoint
+	if bucket == os.Getenv("BUCKET_NAME") {
+		response.DocumentUrl = documentUrl(object.key, documentId)
+		response.PublicUrl = publicUrl(object.key, response.Visibility)
+	}
+
+	if err = u.saveRecord(ctx, response, object.metadata); err != nil {
+		return document.ResponseUploadDocument{}, sourceBucket, err
+	}
+
+	return withoutKey(response), sourceBucket, nil
+}
+
+// copyDestination apply the overwrite policy to the destination key, CopyObject has no conditional write
+// so existence is checked before
+func (u *usecase) copyDestination(ctx context.Context, bucket, key, policy string) (string, error) {
+	switch policy {
+	case constant.OVERWRITE_REJECT:
+		exists, err := u.objectExists(ctx, bucket, key)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return "", document.ErrDocumentAlreadyExists
+		}
+	case constant.OVERWRITE_RENAME:
+		for attempt := 0; attempt <= maxRenameAttempt; attempt++ {
+			candidate := renameKey(key, attempt)
+			exists, err := u.objectExists(ctx, bucket, candidate)
+			if err != nil {
+				return "", err
+			}
+			if !exists {
+				return candidate, nil
+			}
+		}
+		return "", document.ErrDocumentAlreadyExists
+	}
+	return key, nil
+}
+
+// previousBlob is the blob referenced by the document about to be replaced
+func (u *usecase) previousBlob(ctx context.Context, bucket, key string) string {
+	head, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return ""
+	}
+	return pointerBlob(head.Metadata)
+}
+
+func withoutDedupeMetadata(metadata map[string]string) map[string]string {
+	cleaned := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		if key != metaDedupeBlob && key != metaDedupeSHA256 && key != metaDedupeSize {
+			cleaned[key] = value
+		}
+	}
+	return cleaned
+}
+
+// copy use CopyObject up to 5 GB and multipart UploadPartCopy beyond
+func (u *usecase) copy(ctx context.Context, object copyObject) (etag, versionId string, err error) {
+	source := copySource(object.sourceBucket, object.sourceKey, object.sourceVersionId)
+
+	if object.size <= maxCopyObjectSize {
+		input := &s3.CopyObjectInput{
+			Bucket:     aws.String(object.bucket),
+			Key:        aws.String(object.key),
+			CopySource: aws.String(source),
+			ACL:        object.acl,
+		}
+		if object.replace {
+			input.MetadataDirective = types.MetadataDirectiveReplace
+			input.Metadata = object.metadata
+			input.ContentType = aws.String(object.contentType)
+			// headers are replaced along the metadata, the stored ones are kept
+			if object.contentEncoding != "" {
+				input.ContentEncoding = aws.String(object.contentEncoding)
+			}
+			if object.cacheControl != "" {
+				input.CacheControl = aws.String(object.cacheControl)
+			}
+		}
+		output, err := u.s3Client.CopyObject(ctx, input)
+		if err != nil {
+			return "", "", err
+		}
+		if output.CopyObjectResult != nil {
+			etag = aws.ToString(output.CopyObjectResult.ETag)
+		}
+		return etag, aws.ToString(output.VersionId), nil
+	}
+
+	// multipart copy does not carry metadata, they are always set on the new upload
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(object.bucket),
+		Key:         aws.String(object.key),
+		Metadata:    object.metadata,
+		ContentType: aws.String(object.contentType),
+		ACL:         object.acl,
+	}
+	if object.contentEncoding != "" {
+		input.ContentEncoding = aws.String(object.contentEncoding)
+	}
+	if object.cacheControl != "" {
+		input.CacheControl = aws.String(object.cacheControl)
+	}
+	upload, err := u.s3Client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return "", "", err
+	}
+	abort := func() {
+		_, _ = u.s3Client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(object.bucket),
+			Key:      aws.String(object.key),
+			UploadId: upload.UploadId,
+		})
+	}
+
+	partSize := copyPartSize(object.size)
+	var parts []types.CompletedPart
+	for start, number := int64(0), int32(1); start < object.size; start, number = start+partSize, number+1 {
+		end := min(start+partSize, object.size) - 1
+		part, err := u.s3Client.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+			Bucket:          aws.String(object.bucket),
+			Key:             aws.String(object.key),
+			UploadId:        upload.UploadId,
+			PartNumber:      aws.Int32(number),
+			CopySource:      aws.String(source),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+		})
+		if err != nil {
+			abort()
+			return "", "", err
+		}
+		completed := types.CompletedPart{PartNumber: aws.Int32(number)}
+		if part.CopyPartResult != nil {
+			completed.ETag = part.CopyPartResult.ETag
+		}
+		parts = append(parts, completed)
+	}
+
+	output, err := u.s3Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(object.bucket),
+		Key:             aws.String(object.key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		abort()
+		return "", "", err
+	}
+	return aws.ToString(output.ETag), aws.ToString(output.VersionId), nil
+}
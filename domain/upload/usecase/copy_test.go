@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AllowedBucket(t *testing.T) {
+	os.Setenv("BUCKET_NAME", "test-bucket")
+	os.Setenv("ALLOWED_BUCKETS", "archive-bucket, other-bucket")
+	defer os.Unsetenv("ALLOWED_BUCKETS")
+
+	bucket, err := allowedBucket("")
+	require.NoError(t, err)
+	require.Equal(t, "test-bucket", bucket)
+
+	bucket, err = allowedBucket("other-bucket")
+	require.NoError(t, err)
+	require.Equal(t, "other-bucket", bucket)
+
+	_, err = allowedBucket("unknown-bucket")
+	require.ErrorIs(t, err, document.ErrBucketNotAllowed)
+}
+
+func Test_CopyPartSize(t *testing.T) {
+	require.Equal(t, int64(defaultCopyPartSize), copyPartSize(6<<30))
+	// 10000 parts of 512 MB is not enough for 6 TB
+	require.Equal(t, int64(6<<40+maxCopyParts-1)/maxCopyParts, copyPartSize(6<<40))
+}
+
+func Test_CopyDocument(t *testing.T) {
+	os.Setenv("ALLOWED_BUCKETS", "archive-bucket")
+	defer os.Unsetenv("ALLOWED_BUCKETS")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	matchCopy := func(bucket, key, source string) interface{} {
+		return mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+			return aws.ToString(input.Bucket) == bucket && aws.ToString(input.Key) == key && aws.ToString(input.CopySource) == source
+		})
+	}
+
+	type expected struct {
+		err      error
+		response document.ResponseUploadDocument
+	}
+	tests := []struct {
+		name     string
+		request  document.RequestCopyDocument
+		prepare  func()
+		expected expected
+	}{
+		{
+			name:     "BucketNotAllowed",
+			request:  document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf", DestinationBucket: "unknown"},
+			prepare:  func() {},
+			expected: expected{err: document.ErrBucketNotAllowed},
+		},
+		{
+			name:     "SameKey",
+			request:  document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "a/b.pdf"},
+			prepare:  func() {},
+			expected: expected{err: document.ErrInvalidCopy},
+		},
+		{
+			name: "ReservedMetadata",
+			request: document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf", MetadataDirective: "replace",
+				Metadata: map[string]string{metaDedupeBlob: "_ids/0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70"}},
+			prepare:  func() {},
+			expected: expected{err: document.ErrInvalidMetadata},
+		},
+		{
+			name: "ReservedVisibility",
+			request: document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf", MetadataDirective: "replace",
+				Metadata: map[string]string{"Visibility": visibilityPublic}},
+			prepare:  func() {},
+			expected: expected{err: document.ErrInvalidMetadata},
+		},
+		{
+			name:    "NotFound",
+			request: document.RequestCopyDocument{SourceKey: "a/missing.pdf", DestinationKey: "c/b.pdf"},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("a/missing.pdf")).Return(nil, &types.NotFound{}).Once()
+			},
+			expected: expected{err: document.ErrDocumentNotFound},
+		},
+		{
+			name:    "DestinationExists",
+			request: document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf", Overwrite: "reject"},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("a/b.pdf")).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(10)}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("c/b.pdf")).Return(&s3.HeadObjectOutput{}, nil).Once()
+			},
+			expected: expected{err: document.ErrDocumentAlreadyExists},
+		},
+		{
+			name:    "CopyAcrossBucket",
+			request: document.RequestCopyDocument{SourceKey: "a/b c.pdf", DestinationKey: "c/b.pdf", DestinationBucket: "archive-bucket"},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("a/b c.pdf")).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(10), ContentType: aws.String("application/pdf")}, nil).Once()
+				mockS3Client.On("CopyObject", mock.Anything, matchCopy("archive-bucket", "c/b.pdf", "test-bucket/a/b%20c.pdf")).Return(&s3.CopyObjectOutput{
+					CopyObjectResult: &types.CopyObjectResult{ETag: aws.String(`"etag"`)},
+				}, nil).Once()
+			},
+			expected: expected{response: document.ResponseUploadDocument{
+				Key: "c/b.pdf", Bucket: "archive-bucket", ETag: `"etag"`, Size: 10, ContentType: "application/pdf",
+			}},
+		},
+		{
+			name: "ReplaceMetadata",
+			request: document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf", MetadataDirective: "replace",
+				Metadata: map[string]string{"owner": "finance"}, ContentType: "application/x-pdf"},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("a/b.pdf")).Return(&s3.HeadObjectOutput{
+					ContentLength:   aws.Int64(10),
+					ContentType:     aws.String("application/pdf"),
+					ContentEncoding: aws.String("gzip"),
+					CacheControl:    aws.String("public, max-age=86400"),
+				}, nil).Once()
+				mockS3Client.On("CopyObject", mock.Anything, mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+					return input.MetadataDirective == types.MetadataDirectiveReplace && input.Metadata["owner"] == "finance" &&
+						aws.ToString(input.ContentType) == "application/x-pdf" && aws.ToString(input.ContentEncoding) == "gzip" &&
+						aws.ToString(input.CacheControl) == "public, max-age=86400"
+				})).Return(&s3.CopyObjectOutput{}, nil).Once()
+			},
+			expected: expected{response: document.ResponseUploadDocument{
+				DocumentUrl: "http://localhost:8080/api/v1/download/c/b.pdf", Key: "c/b.pdf", Bucket: "test-bucket", Size: 10, ContentType: "application/x-pdf",
+			}},
+		},
+		{
+			name: "ReplaceMetadataPrivate",
+			request: document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf", MetadataDirective: "replace",
+				Metadata: map[string]string{"owner": "finance"}},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("a/b.pdf")).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(10),
+					ContentType:   aws.String("application/pdf"),
+					Metadata:      map[string]string{metaVisibility: visibilityPrivate, "owner": "legal"},
+				}, nil).Once()
+				mockS3Client.On("CopyObject", mock.Anything, mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+					return input.MetadataDirective == types.MetadataDirectiveReplace && input.Metadata["owner"] == "finance" &&
+						input.Metadata[metaVisibility] == visibilityPrivate
+				})).Return(&s3.CopyObjectOutput{}, nil).Once()
+			},
+			expected: expected{response: document.ResponseUploadDocument{
+				DocumentUrl: "http://localhost:8080/api/v1/download/c/b.pdf", Key: "c/b.pdf", Bucket: "test-bucket", Size: 10, ContentType: "application/pdf",
+				Visibility: visibilityPrivate,
+			}},
+		},
+		{
+			name:    "Multipart",
+			request: document.RequestCopyDocument{SourceKey: "a/big.bin", DestinationKey: "c/big.bin"},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("a/big.bin")).Return(&s3.HeadObjectOutput{
+					ContentLength:   aws.Int64(6 << 30),
+					ContentType:     aws.String("application/octet-stream"),
+					ContentEncoding: aws.String("br"),
+					CacheControl:    aws.String("private, no-store"),
+				}, nil).Once()
+				mockS3Client.On("CreateMultipartUpload", mock.Anything, mock.MatchedBy(func(input *s3.CreateMultipartUploadInput) bool {
+					return aws.ToString(input.ContentEncoding) == "br" && aws.ToString(input.CacheControl) == "private, no-store"
+				})).Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+				mockS3Client.On("UploadPartCopy", mock.Anything, mock.MatchedBy(func(input *s3.UploadPartCopyInput) bool {
+					return aws.ToString(input.CopySourceRange) == "bytes=0-536870911" && aws.ToInt32(input.PartNumber) == 1
+				})).Return(&s3.UploadPartCopyOutput{CopyPartResult: &types.CopyPartResult{ETag: aws.String("1")}}, nil).Once()
+				mockS3Client.On("UploadPartCopy", mock.Anything, mock.MatchedBy(func(input *s3.UploadPartCopyInput) bool {
+					return aws.ToString(input.CopySourceRange) == "bytes=5905580032-6442450943" && aws.ToInt32(input.PartNumber) == 12
+				})).Return(&s3.UploadPartCopyOutput{CopyPartResult: &types.CopyPartResult{ETag: aws.String("12")}}, nil).Once()
+				mockS3Client.On("UploadPartCopy", mock.Anything, mock.Anything).Return(&s3.UploadPartCopyOutput{CopyPartResult: &types.CopyPartResult{ETag: aws.String("n")}}, nil).Times(10)
+				mockS3Client.On("CompleteMultipartUpload", mock.Anything, mock.MatchedBy(func(input *s3.CompleteMultipartUploadInput) bool {
+					return len(input.MultipartUpload.Parts) == 12
+				})).Return(&s3.CompleteMultipartUploadOutput{ETag: aws.String(`"etag-12"`)}, nil).Once()
+			},
+			expected: expected{response: document.ResponseUploadDocument{
+				DocumentUrl: "http://localhost:8080/api/v1/download/c/big.bin", Key: "c/big.bin", Bucket: "test-bucket", ETag: `"etag-12"`, Size: 6 << 30, ContentType: "application/octet-stream",
+			}},
+		},
+		{
+			name:    "MultipartAborted",
+			request: document.RequestCopyDocument{SourceKey: "a/big.bin", DestinationKey: "c/big.bin"},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("a/big.bin")).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(6 << 30)}, nil).Once()
+				mockS3Client.On("CreateMultipartUpload", mock.Anything, mock.Anything).Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil).Once()
+				mockS3Client.On("UploadPartCopy", mock.Anything, mock.Anything).Return(nil, errors.New("connection error")).Once()
+				mockS3Client.On("AbortMultipartUpload", mock.Anything, mock.MatchedBy(func(input *s3.AbortMultipartUploadInput) bool {
+					return aws.ToString(input.UploadId) == "upload"
+				})).Return(&s3.AbortMultipartUploadOutput{}, nil).Once()
+			},
+			expected: expected{err: errors.New("failed to copy document: connection error")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			response, err := usecase.CopyDocument(context.Background(), tt.request)
+			if tt.expected.err != nil {
+				if errors.Is(err, tt.expected.err) {
+					return
+				}
+				require.EqualError(t, err, tt.expected.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected.response, response)
+		})
+	}
+}
+
+func Test_CopyDocument_Deduplicated(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	pointer := map[string]string{metaDedupeBlob: testBlob, metaDedupeSHA256: "abc", metaDedupeSize: "42"}
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("a/b.pdf")).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(80), Metadata: pointer}, nil).Once()
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("c/b.pdf")).Return(nil, &types.NotFound{}).Once()
+	mockS3Client.On("CopyObject", mock.Anything, mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+		return input.MetadataDirective == types.MetadataDirectiveReplace && input.Metadata[metaDedupeBlob] == testBlob &&
+			aws.ToString(input.ContentType) == pointerContentType
+	})).Return(&s3.CopyObjectOutput{}, nil).Once()
+	mockS3Client.On("PutObject", mock.Anything, matchKey(refKey(testBlob, "c/b.pdf"))).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.CopyDocument(context.Background(), document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf"})
+
+	require.NoError(t, err)
+	require.Equal(t, int64(42), response.Size)
+}
+
+func Test_MoveDocument(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	_, err := usecase.MoveDocument(context.Background(), document.RequestCopyDocument{SourceKey: "a/b.pdf", SourceVersionId: "v1", DestinationKey: "c/b.pdf"})
+	require.ErrorIs(t, err, document.ErrInvalidCopy)
+
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("a/b.pdf")).Return(&s3.HeadObjectOutput{ContentLength: aws.Int64(10)}, nil).Twice()
+	mockS3Client.On("CopyObject", mock.Anything, mock.Anything).Return(&s3.CopyObjectOutput{}, nil).Once()
+	mockS3Client.On("DeleteObject", mock.Anything, matchKey("a/b.pdf")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+
+	response, err := usecase.MoveDocument(context.Background(), document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf"})
+
+	require.NoError(t, err)
+	require.Equal(t, "c/b.pdf", response.Key)
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Deduplicated storage layout :
+//   - blobs/<sha256>              the content, stored once
+//   - refs/<sha256>/<document>    empty marker for every document pointing to the blob
+//   - <document>                  small json manifest with metadata dedupe-blob
+//
+// the blob is removed when the last ref marker is deleted
+const (
+	blobPrefix = "blobs/"
+	refPrefix  = "refs/"
+
+	metaDedupeBlob   = "dedupe-blob"
+	metaDedupeSHA256 = "dedupe-sha256"
+	metaDedupeSize   = "dedupe-size"
+
+	pointerContentType = "application/vnd.document-pointer+json"
+)
+
+type pointerManifest struct {
+	Blob        string `json:"blob"`
+	SHA256      string `json:"sha256"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+}
+
+// errInvalidBlob is a dedupe-blob metadata that is not a blob key, it is never read nor deleted
+var errInvalidBlob = errors.New("invalid blob key")
+
+// isBlobKey tell whether key is blobs/ followed by the lowercase hex SHA-256 of the content
+func isBlobKey(key string) bool {
+	sum, ok := strings.CutPrefix(key, blobPrefix)
+	if !ok || len(sum) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range sum {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// pointerBlob is the blob the document point to, empty for a regular document. metadata can be written by
+// other paths than the dedupe, a value that is not a blob key must not make another object readable or deletable
+func pointerBlob(metadata map[string]string) string {
+	if blobKey := metadata[metaDedupeBlob]; isBlobKey(blobKey) {
+		return blobKey
+	}
+	return ""
+}
+
+func dedupeEnabled() bool {
+	return os.Getenv("DEDUPE_STORAGE") == "true"
+}
+
+// putDeduplicated store the blob when nobody did it before and write the pointer. checksum always contain SHA-256
+func (u *usecase) putDeduplicated(ctx context.Context, bucketName string, doc upload, checksum utils.Checksum, policy string) (output *s3.PutObjectOutput, key string, err error) {
+
+	sum := hex.EncodeToString(checksum.SHA256)
+	blobKey := blobPrefix + sum
+
+	exists, err := u.objectExists(ctx, bucketName, blobKey)
+	if err != nil {
+		return
+	}
+	if !exists {
+		blob := &s3.PutObjectInput{
+			Bucket:      aws.String(bucketName),
+			Key:         aws.String(blobKey),
+			Body:        doc.body,
+			ContentType: aws.String(doc.contentType),
+		}
+		// the blob is served in place of the pointer, it must be decodable by itself
+		if doc.contentEncoding != "" {
+			blob.ContentEncoding = aws.String(doc.contentEncoding)
+			blob.Metadata = map[string]string{metaUncompressedSize: doc.metadata[metaUncompressedSize]}
+		}
+		_, err = u.s3Client.PutObject(ctx, withChecksum(blob, checksum))
+		if err != nil {
+			return
+		}
+	}
+
+	// the previous document is replaced, remember its blob to release it later
+	var previousBlob string
+	if policy == constant.OVERWRITE_ALLOW {
+		head, headErr := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(doc.key),
+		})
+		if headErr != nil && !isNotFound(headErr) {
+			return nil, "", fmt.Errorf("failed to check document: %w", headErr)
+		}
+		if head != nil {
+			previousBlob = pointerBlob(head.Metadata)
+		}
+	}
+
+	manifest, _ := json.Marshal(pointerManifest{
+		Blob:        blobKey,
+		SHA256:      sum,
+		Size:        checksum.Size,
+		ContentType: doc.contentType,
+	})
+	output, key, err = u.putObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(doc.key),
+		Body:        bytes.NewReader(manifest),
+		ContentType: aws.String(pointerContentType),
+		Metadata: withMetadata(doc.metadata, map[string]string{
+			metaDedupeBlob:   blobKey,
+			metaDedupeSHA256: sum,
+			metaDedupeSize:   strconv.FormatInt(checksum.Size, 10),
+		}),
+	}, policy)
+	if err != nil {
+		if !exists {
+			// nobody else reference the blob we just created
+			_ = u.deleteUnreferencedBlob(ctx, bucketName, blobKey)
+		}
+		return
+	}
+
+	_, err = u.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(refKey(blobKey, key)),
+		Body:   bytes.NewReader(nil),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to reference blob: %w", err)
+	}
+
+	if previousBlob != "" && previousBlob != blobKey {
+		err = u.releaseBlob(ctx, bucketName, previousBlob, key)
+	}
+
+	return
+}
+
+// resolvePointer replace the pointer object with the blob it point to
+func (u *usecase) resolvePointer(ctx context.Context, pointer *s3.GetObjectOutput, byteRange *string) (*s3.GetObjectOutput, error) {
+	if pointer.Body != nil {
+		pointer.Body.Close()
+	}
+
+	blob, err := u.cachedGetObject(ctx, &s3.GetObjectInput{
+		Bucket:       aws.String(os.Getenv("BUCKET_NAME")),
+		Key:          aws.String(pointerBlob(pointer.Metadata)),
+		Range:        byteRange,
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return withInheritedMetadata(blob, pointer.Metadata), nil
+}
+
+// releaseBlob drop reference of document to the blob and delete the blob when no reference left
+func (u *usecase) releaseBlob(ctx context.Context, bucketName, blobKey, documentKey string) error {
+	if !isBlobKey(blobKey) {
+		return fmt.Errorf("failed to release blob: %w: %q", errInvalidBlob, blobKey)
+	}
+	_, err := u.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(refKey(blobKey, documentKey)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release blob: %w", err)
+	}
+
+	return u.deleteUnreferencedBlob(ctx, bucketName, blobKey)
+}
+
+func (u *usecase) deleteUnreferencedBlob(ctx context.Context, bucketName, blobKey string) error {
+	if !isBlobKey(blobKey) {
+		return fmt.Errorf("failed to delete blob: %w: %q", errInvalidBlob, blobKey)
+	}
+	refs, err := u.s3Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucketName),
+		Prefix:  aws.String(refKey(blobKey, "")),
+		MaxKeys: aws.Int32(1),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count blob reference: %w", err)
+	}
+	if aws.ToInt32(refs.KeyCount) > 0 {
+		return nil
+	}
+
+	_, err = u.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(blobKey),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	u.invalidateCache(bucketName, blobKey)
+	return nil
+}
+
+func refKey(blobKey, documentKey string) string {
+	return refPrefix + strings.TrimPrefix(blobKey, blobPrefix) + "/" + documentKey
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testBlob and oldBlob are keys of deduplicated content, dedupe-blob metadata is only followed to such key
+const (
+	testBlob = blobPrefix + "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+	oldBlob  = blobPrefix + "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"
+)
+
+func matchKey(key string) interface{} {
+	return mock.MatchedBy(func(input interface{}) bool {
+		switch in := input.(type) {
+		case *s3.PutObjectInput:
+			return aws.ToString(in.Key) == key
+		case *s3.HeadObjectInput:
+			return aws.ToString(in.Key) == key
+		case *s3.GetObjectInput:
+			return aws.ToString(in.Key) == key
+		case *s3.DeleteObjectInput:
+			return aws.ToString(in.Key) == key
+		case *s3.ListObjectsV2Input:
+			return aws.ToString(in.Prefix) == key
+		}
+		return false
+	})
+}
+
+func Test_UploadBase64_Deduplicated(t *testing.T) {
+	os.Setenv("DEDUPE_STORAGE", "true")
+	defer os.Unsetenv("DEDUPE_STORAGE")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	blobKey := "blobs/" + testContentSHA256
+
+	type expected struct {
+		err      error
+		response document.ResponseUploadDocument
+	}
+	tests := []struct {
+		name     string
+		prepare  func()
+		expected expected
+	}{
+		{
+			name: "NewBlob",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey(blobKey)).Return(nil, &types.NotFound{}).Once()
+				mockS3Client.On("PutObject", mock.Anything, matchKey(blobKey)).Return(&s3.PutObjectOutput{}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(nil, &types.NotFound{}).Once()
+				mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+					return aws.ToString(input.Key) == "data/example.txt" && input.Metadata[metaDedupeBlob] == blobKey
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+				mockS3Client.On("PutObject", mock.Anything, matchKey("refs/"+testContentSHA256+"/data/example.txt")).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadDocument{
+					DocumentUrl:    fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), "data/example.txt"),
+					Key:            "data/example.txt",
+					Bucket:         "test-bucket",
+					Size:           20,
+					ContentType:    "text/txt",
+					ChecksumSHA256: testContentSHA256Base64,
+				},
+			},
+		},
+		{
+			name: "ExistingBlob_ReplacePreviousDocument",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey(blobKey)).Return(&s3.HeadObjectOutput{}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.HeadObjectOutput{
+					Metadata: map[string]string{metaDedupeBlob: oldBlob},
+				}, nil).Once()
+				mockS3Client.On("PutObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.PutObjectOutput{}, nil).Once()
+				mockS3Client.On("PutObject", mock.Anything, matchKey("refs/"+testContentSHA256+"/data/example.txt")).Return(&s3.PutObjectOutput{}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey(refKey(oldBlob, "data/example.txt"))).Return(&s3.DeleteObjectOutput{}, nil).Once()
+				mockS3Client.On("ListObjectsV2", mock.Anything, matchKey(refKey(oldBlob, ""))).Return(&s3.ListObjectsV2Output{KeyCount: aws.Int32(0)}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey(oldBlob)).Return(&s3.DeleteObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadDocument{
+					DocumentUrl:    fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), "data/example.txt"),
+					Key:            "data/example.txt",
+					Bucket:         "test-bucket",
+					Size:           20,
+					ContentType:    "text/txt",
+					ChecksumSHA256: testContentSHA256Base64,
+				},
+			},
+		},
+		{
+			name: "PointerRejected_CleanupNewBlob",
+			prepare: func() {
+				os.Setenv("OVERWRITE_POLICY", "reject")
+				mockS3Client.On("HeadObject", mock.Anything, matchKey(blobKey)).Return(nil, &types.NotFound{}).Once()
+				mockS3Client.On("PutObject", mock.Anything, matchKey(blobKey)).Return(&s3.PutObjectOutput{}, nil).Once()
+				mockS3Client.On("PutObject", mock.Anything, matchKey("data/example.txt")).Return(nil, errors.New("put failed")).Once()
+				mockS3Client.On("ListObjectsV2", mock.Anything, matchKey("refs/"+testContentSHA256+"/")).Return(&s3.ListObjectsV2Output{KeyCount: aws.Int32(0)}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey(blobKey)).Return(&s3.DeleteObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				err: fmt.Errorf("failed to upload file: %w", errors.New("put failed")),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			defer os.Unsetenv("OVERWRITE_POLICY")
+
+			response, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+				DocumentKey:    "data",
+				DocumentName:   "example",
+				DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+			})
+
+			require.Equal(t, tt.expected.err, err)
+			require.Equal(t, tt.expected.response, response)
+		})
+	}
+}
+
+func Test_DownloadFile_Pointer(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	blob := &s3.GetObjectOutput{
+		Body:        io.NopCloser(bytes.NewReader([]byte("This is test content"))),
+		ContentType: aws.String("text/plain"),
+	}
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.GetObjectOutput{
+		Body:     io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		Metadata: map[string]string{metaDedupeBlob: testBlob},
+	}, nil).Once()
+	mockS3Client.On("GetObject", mock.Anything, matchKey(testBlob)).Return(blob, nil).Once()
+
+	response, err := usecase.DownloadFile(nil, document.RequestDownloadDocument{Key: "data/example.txt"})
+
+	require.NoError(t, err)
+	require.Equal(t, blob, response)
+}
+
+func Test_IsBlobKey(t *testing.T) {
+	require.True(t, isBlobKey(testBlob))
+	require.False(t, isBlobKey("blobs/abc"))
+	require.False(t, isBlobKey("_ids/2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"))
+	require.False(t, isBlobKey("blobs/2C26B46B68FFC68FF99B453C1D30413413422D706483BFA0F98A5E886266E7AE"))
+	require.False(t, isBlobKey("blobs/../data/private.pdf-2c26b46b68ffc68ff99b453c1d30413413422d706483"))
+
+	uc, _ := initUseCaseUnitTest(t)
+	require.ErrorIs(t, uc.(*usecase).releaseBlob(nil, "test-bucket", "data/other.txt", "data/example.txt"), errInvalidBlob)
+}
+
+func Test_DownloadFile_InvalidPointer(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	// the document is served as is, the key in its metadata is never read
+	stored := &s3.GetObjectOutput{
+		Body:     io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		Metadata: map[string]string{metaDedupeBlob: "_ids/0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70"},
+	}
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/example.txt")).Return(stored, nil).Once()
+
+	response, err := usecase.DownloadFile(nil, document.RequestDownloadDocument{Key: "data/example.txt"})
+
+	require.NoError(t, err)
+	require.Equal(t, stored.Metadata, response.Metadata)
+}
+
+func Test_DeleteFile(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	tests := []struct {
+		name     string
+		prepare  func()
+		expected error
+	}{
+		{
+			name: "DeleteFile_NotFound",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(nil, &types.NotFound{}).Once()
+			},
+			expected: document.ErrDocumentNotFound,
+		},
+		{
+			name: "DeleteFile_Success",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.HeadObjectOutput{}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+			},
+		},
+		{
+			name: "DeleteFile_Failure",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.HeadObjectOutput{}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey("data/example.txt")).Return(nil, errors.New("delete failed")).Once()
+			},
+			expected: fmt.Errorf("failed to delete file: %w", errors.New("delete failed")),
+		},
+		{
+			name: "DeleteFile_PointerBlobStillReferenced",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.HeadObjectOutput{
+					Metadata: map[string]string{metaDedupeBlob: testBlob},
+				}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey(refKey(testBlob, "data/example.txt"))).Return(&s3.DeleteObjectOutput{}, nil).Once()
+				mockS3Client.On("ListObjectsV2", mock.Anything, matchKey(refKey(testBlob, ""))).Return(&s3.ListObjectsV2Output{KeyCount: aws.Int32(1)}, nil).Once()
+			},
+		},
+		{
+			// metadata pointing outside blobs/ is not a pointer, only the document itself is deleted
+			name: "DeleteFile_InvalidBlob",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.HeadObjectOutput{
+					Metadata: map[string]string{metaDedupeBlob: "data/other.txt"},
+				}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+			},
+		},
+		{
+			name: "DeleteFile_PointerLastReference",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.HeadObjectOutput{
+					Metadata: map[string]string{metaDedupeBlob: testBlob},
+				}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey(refKey(testBlob, "data/example.txt"))).Return(&s3.DeleteObjectOutput{}, nil).Once()
+				mockS3Client.On("ListObjectsV2", mock.Anything, matchKey(refKey(testBlob, ""))).Return(&s3.ListObjectsV2Output{KeyCount: aws.Int32(0)}, nil).Once()
+				mockS3Client.On("DeleteObject", mock.Anything, matchKey(testBlob)).Return(&s3.DeleteObjectOutput{}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			err := usecase.DeleteFile(nil, "data/example.txt")
+
+			require.Equal(t, tt.expected, err)
+		})
+	}
+}
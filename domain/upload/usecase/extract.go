@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"archive/zip"
+	"aws-s3-bucket/models/document"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultExtractMaxFiles = 1000
+	defaultExtractMaxSize  = 1 << 30
+	defaultExtractMaxRatio = 100
+)
+
+type extractLimits struct {
+	maxFiles int
+	maxSize  uint64
+	maxRatio uint64
+}
+
+// archiveExtractLimits read ARCHIVE_EXTRACT_MAX_FILES, ARCHIVE_EXTRACT_MAX_SIZE (total uncompressed bytes)
+// and ARCHIVE_EXTRACT_MAX_RATIO (uncompressed / compressed of one entry)
+func archiveExtractLimits() extractLimits {
+	limits := extractLimits{maxFiles: defaultExtractMaxFiles, maxSize: defaultExtractMaxSize, maxRatio: defaultExtractMaxRatio}
+	if maxFiles, err := strconv.Atoi(os.Getenv("ARCHIVE_EXTRACT_MAX_FILES")); err == nil && maxFiles > 0 {
+		limits.maxFiles = maxFiles
+	}
+	if maxSize, err := strconv.ParseUint(os.Getenv("ARCHIVE_EXTRACT_MAX_SIZE"), 10, 64); err == nil && maxSize > 0 {
+		limits.maxSize = maxSize
+	}
+	if maxRatio, err := strconv.ParseUint(os.Getenv("ARCHIVE_EXTRACT_MAX_RATIO"), 10, 64); err == nil && maxRatio > 0 {
+		limits.maxRatio = maxRatio
+	}
+	return limits
+}
+
+// UploadArchive extract every entry of the zip to document_key/<entry path>, an entry that fail does not stop the others
+func (u *usecase) UploadArchive(ctx context.Context, request document.RequestUploadArchive, file *multipart.FileHeader) (response document.ResponseUploadArchive, err error) {
+
+	filed, err := file.Open()
+	if err != nil {
+		err = fmt.Errorf("failed to open file: %w", err)
+		return
+	}
+	defer filed.Close()
+
+	reader, err := zip.NewReader(filed, file.Size)
+	if err != nil {
+		return response, fmt.Errorf("%w: %s", document.ErrInvalidArchive, err.Error())
+	}
+
+	entries, err := archiveEntries(reader, archiveExtractLimits())
+	if err != nil {
+		return
+	}
+
+	response.Documents = make([]document.ResponseExtractedDocument, 0, len(entries))
+	for _, entry := range entries {
+		name, _ := entryName(entry.Name)
+		extracted := document.ResponseExtractedDocument{Entry: entry.Name}
+
+		uploaded, err := u.extractEntry(ctx, request, entry, name)
+		if err != nil {
+			extracted.Error = err.Error()
+		} else {
+			extracted.Key, extracted.DocumentUrl = uploaded.Key, uploaded.DocumentUrl
+		}
+		response.Documents = append(response.Documents, extracted)
+	}
+
+	return response, nil
+}
+
+// archiveEntries check the declared size of every entry before anything is extracted,
+// the actual size is enforced again while reading as header can lie
+func archiveEntries(reader *zip.Reader, limits extractLimits) (entries []*zip.File, err error) {
+	var total uint64
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") {
+			continue
+		}
+		if _, err = entryName(entry.Name); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+		if len(entries) > limits.maxFiles {
+			return nil, fmt.Errorf("%w: more than %d files", document.ErrArchiveTooLarge, limits.maxFiles)
+		}
+
+		total += entry.UncompressedSize64
+		if total > limits.maxSize {
+			return nil, fmt.Errorf("%w: more than %d bytes uncompressed", document.ErrArchiveTooLarge, limits.maxSize)
+		}
+		if entry.UncompressedSize64 > max(entry.CompressedSize64, 1)*limits.maxRatio {
+			return nil, fmt.Errorf("%w: %s compression ratio is over %d", document.ErrArchiveTooLarge, entry.Name, limits.maxRatio)
+		}
+	}
+	return entries, nil
+}
+
+// entryName clean the entry path and refuse the one escaping the document key (zip slip)
+func entryName(name string) (string, error) {
+	cleaned := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") || cleaned == "." {
+		return "", fmt.Errorf("%w: unsafe entry path %s", document.ErrInvalidArchive, name)
+	}
+	return cleaned, nil
+}
+
+// extractEntry copy the entry to a temporary file, so it is never fully in memory, and store it
+func (u *usecase) extractEntry(ctx context.Context, request document.RequestUploadArchive, entry *zip.File, name string) (response document.ResponseUploadDocument, err error) {
+	key := fmt.Sprintf("%s/%s", request.DocumentKey, name)
+	if isInternalKey(key) {
+		return response, fmt.Errorf("reserved path")
+	}
+
+	content, err := entry.Open()
+	if err != nil {
+		return response, fmt.Errorf("failed to open entry: %w", err)
+	}
+	defer content.Close()
+
+	temp, err := os.CreateTemp("", "archive-entry-*")
+	if err != nil {
+		return response, fmt.Errorf("failed to extract entry: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	defer temp.Close()
+
+	// declared size is already checked, more bytes than declared mean a forged header
+	n, err := io.Copy(temp, io.LimitReader(content, int64(entry.UncompressedSize64)+1))
+	if err != nil {
+		return response, fmt.Errorf("failed to extract entry: %w", err)
+	}
+	if uint64(n) > entry.UncompressedSize64 {
+		return response, fmt.Errorf("entry is bigger than declared")
+	}
+	if err = rewind(temp); err != nil {
+		return response, err
+	}
+
+	contentType, err := entryContentType(temp, name)
+	if err != nil {
+		return response, err
+	}
+
+	return u.store(ctx, upload{
+		key:         key,
+		body:        temp,
+		contentType: contentType,
+		overwrite:   request.Overwrite,
+	})
+}
+
+// entryContentType is the type guessed from extension, checked against the content and ARCHIVE_ALLOWED_CONTENT_TYPES
+// (ex: "application/pdf,image/*", empty allow everything)
+func entryContentType(body io.ReadSeeker, name string) (string, error) {
+	sniffed, err := detectContentType(body, "")
+	if err != nil {
+		return "", err
+	}
+	contentType := sniffed
+	if byExtension := mime.TypeByExtension(path.Ext(name)); byExtension != "" {
+		contentType = byExtension
+	}
+
+	// a binary content named as text or an image named as pdf is refused
+	if base := mediaType(contentType); mediaType(sniffed) != base && !compatibleContentType(base, mediaType(sniffed)) {
+		return "", fmt.Errorf("content does not match extension: %s is %s", path.Ext(name), mediaType(sniffed))
+	}
+
+	allowed := os.Getenv("ARCHIVE_ALLOWED_CONTENT_TYPES")
+	if allowed == "" {
+		return contentType, nil
+	}
+	for _, candidate := range strings.Split(allowed, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == mediaType(contentType) || (strings.HasSuffix(candidate, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(candidate, "*"))) {
+			return contentType, nil
+		}
+	}
+	return "", fmt.Errorf("content type %s is not allowed", mediaType(contentType))
+}
+
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
+
+// compatibleContentType tell whether sniffing result can be refined by extension, sniffing only know few types
+// and return generic one (octet-stream, text/plain, zip for office document) for everything else
+func compatibleContentType(declared, sniffed string) bool {
+	switch sniffed {
+	case "application/octet-stream":
+		return !isTextContentType(declared)
+	case "text/plain", "text/xml", "text/html":
+		return isTextContentType(declared)
+	case "application/zip":
+		return strings.HasPrefix(declared, "application/vnd.") || strings.HasSuffix(declared, "zip") || declared == "application/epub+zip"
+	}
+	return false
+}
+
+func isTextContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/") || strings.HasSuffix(contentType, "json") || strings.HasSuffix(contentType, "xml") ||
+		strings.HasSuffix(contentType, "csv") || contentType == "application/javascript" || contentType == "image/svg+xml"
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type zipEntry struct {
+	name    string
+	content string
+}
+
+func createZip(t *testing.T, entries ...zipEntry) []byte {
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	for _, entry := range entries {
+		w, err := writer.Create(entry.name)
+		require.NoError(t, err)
+		w.Write([]byte(entry.content))
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func createMultipartArchive(t *testing.T, content []byte) *multipart.FileHeader {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", `form-data; name="file"; filename="archive.zip"`)
+	h.Set("Content-Type", "application/zip")
+	part, err := writer.CreatePart(h)
+	require.NoError(t, err)
+	part.Write(content)
+	writer.Close()
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form.File["file"][0]
+}
+
+func Test_EntryName(t *testing.T) {
+	name, err := entryName("docs/./sub/../a.txt")
+	require.NoError(t, err)
+	require.Equal(t, "docs/a.txt", name)
+
+	name, err = entryName(`docs\b.txt`)
+	require.NoError(t, err)
+	require.Equal(t, "docs/b.txt", name)
+
+	for _, unsafe := range []string{"../evil.txt", "docs/../../evil.txt", "/etc/passwd", `..\evil.txt`} {
+		_, err = entryName(unsafe)
+		require.ErrorIs(t, err, document.ErrInvalidArchive, unsafe)
+	}
+}
+
+func Test_ArchiveEntries_Limits(t *testing.T) {
+	archive := createZip(t,
+		zipEntry{name: "a.txt", content: strings.Repeat("a", 1000)},
+		zipEntry{name: "dir/"},
+		zipEntry{name: "__MACOSX/._a.txt", content: "x"},
+		zipEntry{name: "b.txt", content: "b"},
+	)
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	entries, err := archiveEntries(reader, extractLimits{maxFiles: 2, maxSize: 2000, maxRatio: 100})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	_, err = archiveEntries(reader, extractLimits{maxFiles: 1, maxSize: 2000, maxRatio: 100})
+	require.ErrorIs(t, err, document.ErrArchiveTooLarge)
+
+	_, err = archiveEntries(reader, extractLimits{maxFiles: 2, maxSize: 500, maxRatio: 100})
+	require.ErrorIs(t, err, document.ErrArchiveTooLarge)
+
+	// 1000 same bytes deflate to few bytes
+	_, err = archiveEntries(reader, extractLimits{maxFiles: 2, maxSize: 2000, maxRatio: 10})
+	require.ErrorIs(t, err, document.ErrArchiveTooLarge)
+}
+
+func Test_EntryContentType(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+	contentType, err := entryContentType(strings.NewReader("hello"), "a.txt")
+	require.NoError(t, err)
+	require.Equal(t, "text/plain; charset=utf-8", contentType)
+
+	contentType, err = entryContentType(strings.NewReader(png), "a.png")
+	require.NoError(t, err)
+	require.Equal(t, "image/png", contentType)
+
+	_, err = entryContentType(strings.NewReader(png), "a.pdf")
+	require.Error(t, err)
+
+	os.Setenv("ARCHIVE_ALLOWED_CONTENT_TYPES", "image/*")
+	defer os.Unsetenv("ARCHIVE_ALLOWED_CONTENT_TYPES")
+
+	_, err = entryContentType(strings.NewReader(png), "a.png")
+	require.NoError(t, err)
+	_, err = entryContentType(strings.NewReader("hello"), "a.txt")
+	require.Error(t, err)
+}
+
+func Test_UploadArchive(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	type expected struct {
+		err      error
+		response document.ResponseUploadArchive
+	}
+	tests := []struct {
+		name     string
+		archive  []byte
+		prepare  func()
+		expected expected
+	}{
+		{
+			name:    "InvalidArchive",
+			archive: []byte("not a zip"),
+			prepare: func() {},
+			expected: expected{
+				err: document.ErrInvalidArchive,
+			},
+		},
+		{
+			name:    "ZipSlip",
+			archive: createZip(t, zipEntry{name: "../evil.txt", content: "evil"}),
+			prepare: func() {},
+			expected: expected{
+				err: document.ErrInvalidArchive,
+			},
+		},
+		{
+			name: "Success",
+			archive: createZip(t,
+				zipEntry{name: "readme.txt", content: "hello"},
+				zipEntry{name: "sub/data.json", content: `{"a":1}`},
+				zipEntry{name: "fake.pdf", content: "plain text"},
+			),
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, matchKey("folder/readme.txt")).Return(&s3.PutObjectOutput{}, nil).Once()
+				mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+					return *input.Key == "folder/sub/data.json" && *input.ContentType == "application/json"
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadArchive{Documents: []document.ResponseExtractedDocument{
+					{Entry: "readme.txt", Key: "folder/readme.txt", DocumentUrl: "http://localhost:8080/api/v1/download/folder/readme.txt"},
+					{Entry: "sub/data.json", Key: "folder/sub/data.json", DocumentUrl: "http://localhost:8080/api/v1/download/folder/sub/data.json"},
+					{Entry: "fake.pdf", Error: "content does not match extension: .pdf is text/plain"},
+				}},
+			},
+		},
+		{
+			name:    "EntryUploadFailed",
+			archive: createZip(t, zipEntry{name: "readme.txt", content: "hello"}),
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, matchKey("folder/readme.txt")).Return(nil, errors.New("connection error")).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadArchive{Documents: []document.ResponseExtractedDocument{
+					{Entry: "readme.txt", Error: "failed to upload file: connection error"},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			response, err := usecase.UploadArchive(context.Background(), document.RequestUploadArchive{DocumentKey: "folder"}, createMultipartArchive(t, tt.archive))
+			if tt.expected.err != nil {
+				require.ErrorIs(t, err, tt.expected.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected.response, response)
+		})
+	}
+}
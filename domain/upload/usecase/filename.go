@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+	"mime"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// metaOriginalFilename is the filename the client uploaded the document with, the download name by default
+const metaOriginalFilename = "original-filename"
+
+// originalFilenameMetadata add the sanitized client filename to the metadata of the upload
+func originalFilenameMetadata(metadata map[string]string, filename string) map[string]string {
+	filename = utils.SanitizeFilename(filename)
+	if filename == "" {
+		return metadata
+	}
+	return withMetadata(metadata, map[string]string{metaOriginalFilename: mime.QEncoding.Encode("utf-8", filename)})
+}
+
+// withFilename expose the original filename of the document as result metadata, the handler name the download with it
+func withFilename(response *s3.GetObjectOutput) *s3.GetObjectOutput {
+	if response.Metadata[metaOriginalFilename] == "" {
+		return response
+	}
+	filename := decodeMetadata(map[string]string{metaOriginalFilename: response.Metadata[metaOriginalFilename]})[metaOriginalFilename]
+	response.ResultMetadata.Set(document.ResultFilename{}, filename)
+	return response
+}
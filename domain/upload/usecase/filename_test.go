@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UploadFile_OriginalFilename(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	_, fileHeader, err := createMultipartFile("This is test content", `C:\Users\me\résumé.txt`)
+	require.NoError(t, err)
+
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.ToString(input.Key) == "data/example.txt" && input.Metadata[metaOriginalFilename] == "=?utf-8?q?r=C3=A9sum=C3=A9.txt?="
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	_, err = usecase.UploadFile(context.Background(), document.RequestUploadDocumentFile{
+		DocumentKey:  "data",
+		DocumentName: "example",
+	}, fileHeader)
+	require.NoError(t, err)
+}
+
+func Test_DownloadFile_OriginalFilename(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.GetObjectOutput{
+		Body:     io.NopCloser(strings.NewReader("content")),
+		Metadata: map[string]string{metaOriginalFilename: "=?utf-8?q?r=C3=A9sum=C3=A9.txt?="},
+	}, nil).Once()
+	response, err := usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.txt"})
+	require.NoError(t, err)
+	require.Equal(t, "résumé.txt", response.ResultMetadata.Get(document.ResultFilename{}))
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/other.txt")).Return(&s3.GetObjectOutput{
+		Body: io.NopCloser(strings.NewReader("content")),
+	}, nil).Once()
+	response, err = usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/other.txt"})
+	require.NoError(t, err)
+	require.Nil(t, response.ResultMetadata.Get(document.ResultFilename{}))
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
+)
+
+const (
+	// idPrefix hold one object per opaque id, its content is the key of the document
+	idPrefix = "_ids/"
+	// metaDocumentId is the opaque id on the document, to release it on delete
+	metaDocumentId = "document-id"
+)
+
+// opaqueIdsEnabled is configured by OPAQUE_IDS=true, every upload then get an opaque id
+func opaqueIdsEnabled() bool {
+	return os.Getenv("OPAQUE_IDS") == "true"
+}
+
+// downloadByKeyEnabled is configured by DOWNLOAD_BY_KEY, false serve documents only by their opaque id
+// so the folder structure cannot be enumerated
+func downloadByKeyEnabled() bool {
+	return os.Getenv("DOWNLOAD_BY_KEY") != "false"
+}
+
+// documentUrl is the download url of the document, by its opaque id when it has one
+func documentUrl(key, id string) string {
+	if id != "" {
+		return fmt.Sprintf("%s/api/v1/files/%s", os.Getenv("BASE_URL"), id)
+	}
+	return fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), escapeKey(key))
+}
+
+// escapeKey escape every segment of the key for an url path, the slashes are kept
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+// derivedUrl is the download url of a rendition or preview of the document, the derived object is internal
+// so it is always served through the url of its document
+func derivedUrl(key, id, name string) string {
+	return documentUrl(key, id) + "?rendition=" + url.QueryEscape(name)
+}
+
+// withoutKey remove what reveal the key of a document served only by its opaque id, the S3 signed url
+// include the key too
+func withoutKey(response document.ResponseUploadDocument) document.ResponseUploadDocument {
+	if response.Id == "" || downloadByKeyEnabled() {
+		return response
+	}
+	response.Key = ""
+	response.SignedUrl, response.SignedUrlExpiresAt = "", nil
+	return response
+}
+
+// uploadedKey is the key of the uploaded document, resolved by its opaque id when the response leave it out
+func (u *usecase) uploadedKey(ctx context.Context, uploaded document.ResponseUploadDocument) (string, error) {
+	if uploaded.Key != "" {
+		return uploaded.Key, nil
+	}
+	return u.ResolveDocumentId(ctx, uploaded.Id)
+}
+
+// newDocumentId return a new opaque id, empty when opaque ids are disabled
+func newDocumentId() string {
+	if !opaqueIdsEnabled() {
+		return ""
+	}
+	return uuid.New().String()
+}
+
+// putDocumentId map the opaque id to the key, a moved document keep its id this way
+func (u *usecase) putDocumentId(ctx context.Context, bucketName, id, key string) error {
+	_, err := u.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(idPrefix + id),
+		Body:        bytes.NewReader([]byte(key)),
+		ContentType: aws.String("text/plain"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save document id: %w", err)
+	}
+	return nil
+}
+
+// ResolveDocumentId return the key of the document of the opaque id
+func (u *usecase) ResolveDocumentId(ctx context.Context, id string) (key string, err error) {
+	// anything else than an uuid is not an id, it must not reach another path of the bucket
+	if _, err = uuid.Parse(id); err != nil {
+		return "", document.ErrDocumentNotFound
+	}
+
+	output, err := u.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(os.Getenv("BUCKET_NAME")),
+		Key:    aws.String(idPrefix + id),
+	})
+	if isNotFound(err) {
+		return "", document.ErrDocumentNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve document id: %w", err)
+	}
+	defer output.Body.Close()
+
+	content, err := io.ReadAll(output.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve document id: %w", err)
+	}
+	return string(content), nil
+}
+
+// releaseDocumentId delete the opaque id of the deleted document, unless the id was moved to another key
+func (u *usecase) releaseDocumentId(ctx context.Context, bucketName, id, key string) error {
+	current, err := u.ResolveDocumentId(ctx, id)
+	if errors.Is(err, document.ErrDocumentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current != key {
+		return nil
+	}
+
+	_, err = u.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(idPrefix + id),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete document id: %w", err)
+	}
+	return nil
+}
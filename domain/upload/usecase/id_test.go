@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testDocumentId = "0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70"
+
+func mappingOutput(key string) *s3.GetObjectOutput {
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(key))}
+}
+
+func Test_UploadBase64_OpaqueId(t *testing.T) {
+	os.Setenv("OPAQUE_IDS", "true")
+	defer os.Unsetenv("OPAQUE_IDS")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	var documentId string
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		if aws.ToString(input.Key) != "data/example.txt" {
+			return false
+		}
+		documentId = input.Metadata[metaDocumentId]
+		return documentId != ""
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		content, _ := io.ReadAll(input.Body)
+		return aws.ToString(input.Key) == idPrefix+documentId && string(content) == "data/example.txt"
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, documentId, response.Id)
+	require.Equal(t, "http://localhost:8080/api/v1/files/"+documentId, response.DocumentUrl)
+}
+
+func Test_ResolveDocumentId(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	_, err := usecase.ResolveDocumentId(context.Background(), "../data/example.txt")
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey(idPrefix+testDocumentId)).Return(nil, &types.NoSuchKey{}).Once()
+	_, err = usecase.ResolveDocumentId(context.Background(), testDocumentId)
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey(idPrefix+testDocumentId)).Return(mappingOutput("data/example.txt"), nil).Once()
+	key, err := usecase.ResolveDocumentId(context.Background(), testDocumentId)
+	require.NoError(t, err)
+	require.Equal(t, "data/example.txt", key)
+}
+
+func Test_DownloadFile_ByKeyDisabled(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	// internal layout is never served by key
+	_, err := usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: idPrefix + testDocumentId})
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+
+	os.Setenv("DOWNLOAD_BY_KEY", "false")
+	defer os.Unsetenv("DOWNLOAD_BY_KEY")
+
+	_, err = usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.txt"})
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("content"))}, nil).Once()
+	_, err = usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.txt", Id: testDocumentId})
+	require.NoError(t, err)
+}
+
+func Test_DeleteFile_OpaqueId(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	head := &s3.HeadObjectOutput{Metadata: map[string]string{metaDocumentId: testDocumentId}}
+
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(head, nil).Once()
+	mockS3Client.On("DeleteObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+	mockS3Client.On("GetObject", mock.Anything, matchKey(idPrefix+testDocumentId)).Return(mappingOutput("data/example.txt"), nil).Once()
+	mockS3Client.On("DeleteObject", mock.Anything, matchKey(idPrefix+testDocumentId)).Return(&s3.DeleteObjectOutput{}, nil).Once()
+
+	require.NoError(t, usecase.DeleteFile(context.Background(), "data/example.txt"))
+
+	// the id was moved to another document, it is kept
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(head, nil).Once()
+	mockS3Client.On("DeleteObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+	mockS3Client.On("GetObject", mock.Anything, matchKey(idPrefix+testDocumentId)).Return(mappingOutput("archive/example.txt"), nil).Once()
+
+	require.NoError(t, usecase.DeleteFile(context.Background(), "data/example.txt"))
+}
+
+func Test_MoveDocument_KeepOpaqueId(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	head := &s3.HeadObjectOutput{ContentLength: aws.Int64(10), Metadata: map[string]string{metaDocumentId: testDocumentId}}
+
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("a/b.pdf")).Return(head, nil).Twice()
+	// metadata of the source, with its id, is copied as is
+	mockS3Client.On("CopyObject", mock.Anything, mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+		return input.MetadataDirective != types.MetadataDirectiveReplace
+	})).Return(&s3.CopyObjectOutput{}, nil).Once()
+	mockS3Client.On("PutObject", mock.Anything, matchKey(idPrefix+testDocumentId)).Return(&s3.PutObjectOutput{}, nil).Once()
+	mockS3Client.On("DeleteObject", mock.Anything, matchKey("a/b.pdf")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+	mockS3Client.On("GetObject", mock.Anything, matchKey(idPrefix+testDocumentId)).Return(mappingOutput("c/b.pdf"), nil).Once()
+
+	response, err := usecase.MoveDocument(context.Background(), document.RequestCopyDocument{SourceKey: "a/b.pdf", DestinationKey: "c/b.pdf"})
+
+	require.NoError(t, err)
+	require.Equal(t, testDocumentId, response.Id)
+	require.Equal(t, "http://localhost:8080/api/v1/files/"+testDocumentId, response.DocumentUrl)
+}
+
+func Test_UploadBase64_ByKeyDisabled(t *testing.T) {
+	os.Setenv("OPAQUE_IDS", "true")
+	os.Setenv("DOWNLOAD_BY_KEY", "false")
+	os.Setenv("RENDITION_PROFILES", "thumb:4x4:cover")
+	defer os.Unsetenv("OPAQUE_IDS")
+	defer os.Unsetenv("DOWNLOAD_BY_KEY")
+	defer os.Unsetenv("RENDITION_PROFILES")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	var source bytes.Buffer
+	require.NoError(t, png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 16, 8))))
+
+	mockS3Client.On("PutObject", mock.Anything, mock.Anything).Return(&s3.PutObjectOutput{}, nil).Times(3)
+
+	response, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:image/png;base64," + base64.StdEncoding.EncodeToString(source.Bytes()),
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, response.Id)
+	require.Empty(t, response.Key)
+	require.Empty(t, response.SignedUrl)
+	require.Equal(t, map[string]string{
+		"thumb": "http://localhost:8080/api/v1/files/" + response.Id + "?rendition=thumb",
+	}, response.Renditions)
+}
+
+func Test_DownloadFile_RenditionById(t *testing.T) {
+	os.Setenv("DOWNLOAD_BY_KEY", "false")
+	os.Setenv("RENDITION_PROFILES", "thumb:4x4:cover")
+	defer os.Unsetenv("DOWNLOAD_BY_KEY")
+	defer os.Unsetenv("RENDITION_PROFILES")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("_renditions/data/example.png/@thumb.png")).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("thumb"))}, nil).Once()
+	_, err := usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.png", Id: testDocumentId, Rendition: "thumb"})
+	require.NoError(t, err)
+
+	_, err = usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.png", Id: testDocumentId, Rendition: "unknown"})
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+
+	// the original of a transformation missing from the cache is fetched by id as well
+	var source bytes.Buffer
+	require.NoError(t, png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.png")).Return(&s3.HeadObjectOutput{ETag: aws.String(`"abc"`)}, nil).Once()
+	mockS3Client.On("GetObject", mock.Anything, matchKey("_renditions/data/example.png/abc/20x0_contain_q80.orig")).Return(nil, &types.NoSuchKey{}).Once()
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/example.png")).Return(&s3.GetObjectOutput{
+		Body:        io.NopCloser(bytes.NewReader(source.Bytes())),
+		ContentType: aws.String("image/png"),
+	}, nil).Once()
+	mockS3Client.On("PutObject", mock.Anything, matchKey("_renditions/data/example.png/abc/20x0_contain_q80.orig")).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	_, err = usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.png", Id: testDocumentId, Width: 20})
+	require.NoError(t, err)
+}
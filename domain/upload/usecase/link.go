@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLinkExpires = 15 * time.Minute
+
+	// bindIp and bindDisposition are the values a signed link can be bound to
+	bindIp          = "ip"
+	bindDisposition = "type"
+)
+
+type signingKey struct {
+	id     string
+	secret []byte
+}
+
+// signingKeys is configured by DOWNLOAD_SIGNING_KEYS as comma separated kid:secret. the first one sign the
+// new links and every one verify, a secret is rotated by adding the new one first and removing the old one
+// once its links are expired
+func signingKeys() []signingKey {
+	var keys []signingKey
+	for _, entry := range strings.Split(os.Getenv("DOWNLOAD_SIGNING_KEYS"), ",") {
+		id, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" || secret == "" {
+			continue
+		}
+		keys = append(keys, signingKey{id: id, secret: []byte(secret)})
+	}
+	return keys
+}
+
+// signedLinksRequired is configured by DOWNLOAD_SIGNED_ONLY=true, a download without signed link is refused
+func signedLinksRequired() bool {
+	return os.Getenv("DOWNLOAD_SIGNED_ONLY") == "true"
+}
+
+// linkExpires is the default lifetime of signed link, configured by DOWNLOAD_LINK_EXPIRES (ex: 1h)
+func linkExpires() time.Duration {
+	expires, err := time.ParseDuration(os.Getenv("DOWNLOAD_LINK_EXPIRES"))
+	if err != nil || expires <= 0 {
+		return defaultLinkExpires
+	}
+	return expires
+}
+
+// linkPayload is the signed content of a link, a value not bound is left empty. the key is last
+// as it is the only value that can hold a new line
+func linkPayload(key string, expires int64, bind []string, ip, disposition string) string {
+	var boundIp, boundDisposition string
+	for _, value := range bind {
+		switch value {
+		case bindIp:
+			boundIp = ip
+		case bindDisposition:
+			boundDisposition = disposition
+		}
+	}
+	return strings.Join([]string{strconv.FormatInt(expires, 10), strings.Join(bind, ","), boundIp, boundDisposition, key}, "\n")
+}
+
+// linkDisposition is the disposition of the type of download, anything else than download and base64 is inline
+func linkDisposition(typeResponse string) string {
+	if typeResponse == "download" || typeResponse == "base64" {
+		return typeResponse
+	}
+	return "inline"
+}
+
+// normalizeIp write the address the same way for the signer and the verifier
+func normalizeIp(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	return ip
+}
+
+// SignDownloadLink return a link of the document served by this service, valid until it expires
+func (u *usecase) SignDownloadLink(ctx context.Context, request document.RequestSignDownload) (response document.ResponseSignDownload, err error) {
+	keys := signingKeys()
+	if len(keys) == 0 {
+		return response, document.ErrSigningNotConfigured
+	}
+	if isInternalKey(request.Key) {
+		return response, document.ErrDocumentNotFound
+	}
+
+	expires := linkExpires()
+	if request.ExpiresIn > 0 {
+		expires = time.Duration(request.ExpiresIn) * time.Second
+	}
+	expiresAt := time.Now().Add(expires).Truncate(time.Second).UTC()
+
+	var bind []string
+	if request.ClientIp != "" {
+		bind = append(bind, bindIp)
+	}
+	if request.Disposition != "" {
+		bind = append(bind, bindDisposition)
+	}
+	disposition := linkDisposition(request.Disposition)
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("kid", keys[0].id)
+	if len(bind) > 0 {
+		query.Set("bind", strings.Join(bind, ","))
+	}
+	if disposition != "inline" {
+		query.Set("type", disposition)
+	}
+	payload := linkPayload(request.Key, expiresAt.Unix(), bind, normalizeIp(request.ClientIp), disposition)
+	query.Set("sig", utils.SignHMAC(keys[0].secret, payload))
+
+	return document.ResponseSignDownload{
+		Url:       fmt.Sprintf("%s?%s", documentUrl(request.Key, ""), query.Encode()),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// verifyLink check the signed link of the download before anything is fetched, signed is false
+// when the download has no signed link
+func verifyLink(request document.RequestDownloadDocument, now time.Time) (signed bool, err error) {
+	link := request.Link
+	if link == nil {
+		return false, nil
+	}
+
+	expires, err := strconv.ParseInt(link.Expires, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid expires", document.ErrInvalidSignature)
+	}
+	if now.Unix() > expires {
+		return false, fmt.Errorf("%w: link is expired", document.ErrInvalidSignature)
+	}
+
+	var bind []string
+	if link.Bind != "" {
+		bind = strings.Split(link.Bind, ",")
+	}
+	for _, value := range bind {
+		if value != bindIp && value != bindDisposition {
+			return false, fmt.Errorf("%w: unknown bind %q", document.ErrInvalidSignature, value)
+		}
+	}
+
+	payload := linkPayload(request.Key, expires, bind, normalizeIp(link.ClientIp), linkDisposition(link.Disposition))
+	for _, key := range signingKeys() {
+		if key.id == link.KeyId && utils.VerifyHMAC(key.secret, payload, link.Signature) {
+			return true, nil
+		}
+	}
+	return false, document.ErrInvalidSignature
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// linkRequest is the download of the signed link, as the handler read it
+func linkRequest(t *testing.T, signedUrl, clientIp string) document.RequestDownloadDocument {
+	parsed, err := url.Parse(signedUrl)
+	require.NoError(t, err)
+	query := parsed.Query()
+	return document.RequestDownloadDocument{
+		Key: strings.TrimPrefix(parsed.Path, "/api/v1/download/"),
+		Link: &document.RequestDownloadLink{
+			Expires:     query.Get("expires"),
+			KeyId:       query.Get("kid"),
+			Bind:        query.Get("bind"),
+			Signature:   query.Get("sig"),
+			ClientIp:    clientIp,
+			Disposition: query.Get("type"),
+		},
+	}
+}
+
+func Test_SignDownloadLink(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	_, err := usecase.SignDownloadLink(context.Background(), document.RequestSignDownload{Key: "data/example.txt"})
+	require.ErrorIs(t, err, document.ErrSigningNotConfigured)
+
+	os.Setenv("DOWNLOAD_SIGNING_KEYS", "k1:old-secret")
+	defer os.Unsetenv("DOWNLOAD_SIGNING_KEYS")
+
+	_, err = usecase.SignDownloadLink(context.Background(), document.RequestSignDownload{Key: idPrefix + testDocumentId})
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+
+	response, err := usecase.SignDownloadLink(context.Background(), document.RequestSignDownload{Key: "data/example.txt", ExpiresIn: 60})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(response.Url, "http://localhost:8080/api/v1/download/data/example.txt?"))
+	require.WithinDuration(t, time.Now().Add(time.Minute), response.ExpiresAt, 2*time.Second)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("content"))}, nil)
+	request := linkRequest(t, response.Url, "203.0.113.7")
+
+	// served while download by key is disabled
+	os.Setenv("DOWNLOAD_BY_KEY", "false")
+	defer os.Unsetenv("DOWNLOAD_BY_KEY")
+	_, err = usecase.DownloadFile(context.Background(), request)
+	require.NoError(t, err)
+
+	// another key with the same signature
+	other := request
+	other.Key = "data/other.txt"
+	_, err = usecase.DownloadFile(context.Background(), other)
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+
+	// new secret sign, the old one still verify until it is removed
+	os.Setenv("DOWNLOAD_SIGNING_KEYS", "k2:new-secret,k1:old-secret")
+	_, err = usecase.DownloadFile(context.Background(), request)
+	require.NoError(t, err)
+
+	rotated, err := usecase.SignDownloadLink(context.Background(), document.RequestSignDownload{Key: "data/example.txt"})
+	require.NoError(t, err)
+	require.Contains(t, rotated.Url, "kid=k2")
+
+	os.Setenv("DOWNLOAD_SIGNING_KEYS", "k2:new-secret")
+	_, err = usecase.DownloadFile(context.Background(), request)
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+}
+
+func Test_DownloadFile_SignedLinkBound(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	os.Setenv("DOWNLOAD_SIGNING_KEYS", "k1:secret")
+	defer os.Unsetenv("DOWNLOAD_SIGNING_KEYS")
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("content"))}, nil)
+
+	response, err := usecase.SignDownloadLink(context.Background(), document.RequestSignDownload{
+		Key:         "data/example.txt",
+		ClientIp:    "203.0.113.7",
+		Disposition: "download",
+	})
+	require.NoError(t, err)
+	require.Contains(t, response.Url, "bind=ip%2Ctype")
+	require.Contains(t, response.Url, "type=download")
+
+	_, err = usecase.DownloadFile(context.Background(), linkRequest(t, response.Url, "203.0.113.7"))
+	require.NoError(t, err)
+
+	_, err = usecase.DownloadFile(context.Background(), linkRequest(t, response.Url, "198.51.100.1"))
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+
+	inline := linkRequest(t, response.Url, "203.0.113.7")
+	inline.Link.Disposition = ""
+	_, err = usecase.DownloadFile(context.Background(), inline)
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+
+	unbound := linkRequest(t, response.Url, "203.0.113.7")
+	unbound.Link.Bind = "type"
+	_, err = usecase.DownloadFile(context.Background(), unbound)
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+}
+
+func Test_DownloadFile_SignedLinkRequired(t *testing.T) {
+	usecase, _ := initUseCaseUnitTest(t)
+	os.Setenv("DOWNLOAD_SIGNING_KEYS", "k1:secret")
+	defer os.Unsetenv("DOWNLOAD_SIGNING_KEYS")
+
+	expired := time.Now().Add(-time.Minute).Unix()
+	link := &document.RequestDownloadLink{
+		Expires:   strconv.FormatInt(expired, 10),
+		KeyId:     "k1",
+		Signature: utils.SignHMAC([]byte("secret"), linkPayload("data/example.txt", expired, nil, "", "inline")),
+	}
+	_, err := usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.txt", Link: link})
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+
+	os.Setenv("DOWNLOAD_SIGNED_ONLY", "true")
+	defer os.Unsetenv("DOWNLOAD_SIGNED_ONLY")
+
+	_, err = usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.txt"})
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+	_, err = usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.txt", Id: testDocumentId})
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+}
+
+func Test_SignDownloadLink_EscapedKey(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	os.Setenv("DOWNLOAD_SIGNING_KEYS", "k1:secret")
+	defer os.Unsetenv("DOWNLOAD_SIGNING_KEYS")
+
+	link, err := usecase.SignDownloadLink(context.Background(), document.RequestSignDownload{Key: "data/my report?#1.txt"})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(link.Url, "http://localhost:8080/api/v1/download/data/my%20report%3F%231.txt?"))
+
+	// the link cannot be altered by the key, it is verified for the very same key
+	request := linkRequest(t, link.Url, "")
+	require.Equal(t, "data/my report?#1.txt", request.Key)
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/my report?#1.txt")).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("content"))}, nil).Once()
+	_, err = usecase.DownloadFile(context.Background(), request)
+	require.NoError(t, err)
+}
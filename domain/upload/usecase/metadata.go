@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func (u *usecase) GetMetadata(ctx context.Context, fileIdentifier string) (response document.ResponseDocumentMetadata, err error) {
+
+	if isInternalKey(fileIdentifier) {
+		return response, document.ErrDocumentNotFound
+	}
+	bucketName := os.Getenv("BUCKET_NAME")
+	head, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(fileIdentifier),
+	})
+	if isNotFound(err) {
+		err = document.ErrDocumentNotFound
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to get metadata: %w", err)
+		return
+	}
+	// metadata is read without signed link, the one of a private document is refused as its download
+	if err = checkVisibility(head.Metadata, false); err != nil {
+		return
+	}
+
+	response = document.ResponseDocumentMetadata{
+		Key:             fileIdentifier,
+		Size:            aws.ToInt64(head.ContentLength),
+		ContentType:     aws.ToString(head.ContentType),
+		ContentEncoding: aws.ToString(head.ContentEncoding),
+		ETag:            aws.ToString(head.ETag),
+		VersionId:       aws.ToString(head.VersionId),
+		LastModified:    head.LastModified,
+		Metadata:        decodeMetadata(head.Metadata),
+	}
+
+	// pointer of deduplicated document only hold the manifest, describe the content instead
+	if blobKey := pointerBlob(head.Metadata); blobKey != "" {
+		blob, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(blobKey),
+		})
+		if err != nil {
+			return document.ResponseDocumentMetadata{}, fmt.Errorf("failed to get metadata: %w", err)
+		}
+		response.Size = aws.ToInt64(blob.ContentLength)
+		response.ContentType = aws.ToString(blob.ContentType)
+		response.ContentEncoding = aws.ToString(blob.ContentEncoding)
+	}
+
+	if encrypted, ok := response.Metadata[metaPdfEncrypted]; ok {
+		response.Pdf = &document.ResponsePdfInfo{
+			Title:  response.Metadata[metaPdfTitle],
+			Author: response.Metadata[metaPdfAuthor],
+		}
+		response.Pdf.Encrypted, _ = strconv.ParseBool(encrypted)
+		response.Pdf.PageCount, _ = strconv.Atoi(response.Metadata[metaPdfPages])
+	}
+
+	return response, nil
+}
+
+// decodeMetadata decode RFC 2047 encoded value written for non ASCII text
+func decodeMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	decoder := new(mime.WordDecoder)
+	decoded := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		if text, err := decoder.DecodeHeader(value); err == nil {
+			value = text
+		}
+		decoded[key] = value
+	}
+	return decoded
+}
+
+// inheritedMetadata describe the document rather than its content, a shared blob or a rendition has none of its own
+var inheritedMetadata = []string{metaVisibility, metaOriginalFilename}
+
+// withInheritedMetadata give the response the inherited metadata of the document it is derived from
+func withInheritedMetadata(response *s3.GetObjectOutput, metadata map[string]string) *s3.GetObjectOutput {
+	if inherited := inheritedOf(metadata); len(inherited) > 0 {
+		response.Metadata = withMetadata(response.Metadata, inherited)
+	}
+	return response
+}
+
+// inheritedOf is the inherited metadata set in metadata
+func inheritedOf(metadata map[string]string) map[string]string {
+	inherited := map[string]string{}
+	for _, name := range inheritedMetadata {
+		if metadata[name] != "" {
+			inherited[name] = metadata[name]
+		}
+	}
+	return inherited
+}
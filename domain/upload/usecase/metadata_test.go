@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetMetadata(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	type expected struct {
+		err      error
+		response document.ResponseDocumentMetadata
+	}
+	tests := []struct {
+		name     string
+		prepare  func()
+		expected expected
+	}{
+		{
+			name: "NotFound",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/invoice.pdf")).Return(nil, &types.NotFound{}).Once()
+			},
+			expected: expected{err: document.ErrDocumentNotFound},
+		},
+		{
+			name: "Failure",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/invoice.pdf")).Return(nil, errors.New("connection error")).Once()
+			},
+			expected: expected{err: fmt.Errorf("failed to get metadata: %w", errors.New("connection error"))},
+		},
+		{
+			name: "Private",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/invoice.pdf")).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(1024),
+					Metadata:      map[string]string{metaVisibility: visibilityPrivate},
+				}, nil).Once()
+			},
+			expected: expected{err: fmt.Errorf("%w: private document requires a signed link", document.ErrInvalidSignature)},
+		},
+		{
+			name: "Pdf",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/invoice.pdf")).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(1024),
+					ContentType:   aws.String("application/pdf"),
+					ETag:          aws.String(`"abc"`),
+					LastModified:  &lastModified,
+					Metadata: map[string]string{
+						metaPdfPages:     "3",
+						metaPdfTitle:     "=?utf-8?q?Facture_=C3=A9t=C3=A9?=",
+						metaPdfAuthor:    "Jane",
+						metaPdfEncrypted: "false",
+					},
+				}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseDocumentMetadata{
+					Key:          "data/invoice.pdf",
+					Size:         1024,
+					ContentType:  "application/pdf",
+					ETag:         `"abc"`,
+					LastModified: &lastModified,
+					Metadata: map[string]string{
+						metaPdfPages:     "3",
+						metaPdfTitle:     "Facture été",
+						metaPdfAuthor:    "Jane",
+						metaPdfEncrypted: "false",
+					},
+					Pdf: &document.ResponsePdfInfo{PageCount: 3, Title: "Facture été", Author: "Jane"},
+				},
+			},
+		},
+		{
+			name: "DeduplicatedPointer",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, matchKey("data/invoice.pdf")).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(100),
+					ContentType:   aws.String(pointerContentType),
+					Metadata:      map[string]string{metaDedupeBlob: testBlob},
+				}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, matchKey(testBlob)).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(2048),
+					ContentType:   aws.String("application/pdf"),
+				}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseDocumentMetadata{
+					Key:         "data/invoice.pdf",
+					Size:        2048,
+					ContentType: "application/pdf",
+					Metadata:    map[string]string{metaDedupeBlob: testBlob},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			response, err := usecase.GetMetadata(nil, "data/invoice.pdf")
+
+			require.Equal(t, tt.expected.err, err)
+			require.Equal(t, tt.expected.response, response)
+		})
+	}
+}
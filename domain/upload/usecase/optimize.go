@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	originalPrefix = "_originals/"
+
+	metaOriginalSize = "original-size"
+	metaStoredSize   = "stored-size"
+)
+
+// optimizeFormats is image extension that can be optimized, gif is left alone to keep its animation
+var optimizeFormats = map[string]string{
+	".jpg":  constant.IMAGE_FORMAT_JPEG,
+	".jpeg": constant.IMAGE_FORMAT_JPEG,
+	".png":  constant.IMAGE_FORMAT_PNG,
+	".webp": constant.IMAGE_FORMAT_WEBP,
+}
+
+var formatExtensions = map[string]string{
+	constant.IMAGE_FORMAT_JPEG: ".jpg",
+	constant.IMAGE_FORMAT_PNG:  ".png",
+	constant.IMAGE_FORMAT_WEBP: ".webp",
+}
+
+type optimizeConfig struct {
+	threshold    int64
+	maxDimension int
+	format       string
+	quality      int
+	keepOriginal bool
+}
+
+// imageOptimizeConfig read IMAGE_OPTIMIZE_* env, optimization is disabled when IMAGE_OPTIMIZE_THRESHOLD is not set
+func imageOptimizeConfig() (config optimizeConfig, enabled bool) {
+	threshold, err := strconv.ParseInt(os.Getenv("IMAGE_OPTIMIZE_THRESHOLD"), 10, 64)
+	if err != nil || threshold <= 0 {
+		return config, false
+	}
+
+	config = optimizeConfig{
+		threshold:    threshold,
+		format:       strings.ToLower(os.Getenv("IMAGE_OPTIMIZE_FORMAT")),
+		quality:      defaultImageQuality,
+		keepOriginal: os.Getenv("IMAGE_OPTIMIZE_KEEP_ORIGINAL") == "true",
+	}
+	if maxDimension, err := strconv.Atoi(os.Getenv("IMAGE_OPTIMIZE_MAX_DIMENSION")); err == nil && maxDimension > 0 {
+		config.maxDimension = maxDimension
+	}
+	if quality, err := strconv.Atoi(os.Getenv("IMAGE_OPTIMIZE_QUALITY")); err == nil && quality >= 1 && quality <= 100 {
+		config.quality = quality
+	}
+	if config.format == "jpg" {
+		config.format = constant.IMAGE_FORMAT_JPEG
+	}
+	return config, true
+}
+
+// optimizeUpload re-encode image bigger than the threshold, cap its dimension and convert opaque png to
+// IMAGE_OPTIMIZE_FORMAT. the key extension follow the new format, original is returned to be kept when configured
+func optimizeUpload(doc upload) (optimized upload, original []byte, err error) {
+	config, enabled := imageOptimizeConfig()
+	sourceFormat, ok := optimizeFormats[strings.ToLower(path.Ext(doc.key))]
+	if !enabled || !ok || !strings.HasPrefix(doc.contentType, "image/") {
+		return doc, nil, nil
+	}
+
+	size, err := contentLength(doc.body)
+	if err != nil || size <= config.threshold {
+		return doc, nil, err
+	}
+
+	original, err = io.ReadAll(doc.body)
+	if err != nil {
+		return doc, nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if err = rewind(doc.body); err != nil {
+		return doc, nil, err
+	}
+
+	img, _, err := utils.DecodeImage(bytes.NewReader(original))
+	if err != nil {
+		// not decodable, store it as is
+		return doc, nil, nil
+	}
+
+	resized := false
+	if bounds := img.Bounds(); config.maxDimension > 0 && (bounds.Dx() > config.maxDimension || bounds.Dy() > config.maxDimension) {
+		img, resized = utils.ResizeImage(img, config.maxDimension, config.maxDimension, constant.IMAGE_FIT_CONTAIN), true
+	}
+
+	format := sourceFormat
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && format == constant.IMAGE_FORMAT_PNG && config.format != "" && opaque.Opaque() {
+		format = config.format
+	}
+	if _, ok := formatExtensions[format]; !ok {
+		format = sourceFormat
+	}
+
+	data, contentType, err := utils.EncodeImage(img, format, config.quality)
+	if err != nil {
+		return doc, nil, fmt.Errorf("failed to optimize image: %w", err)
+	}
+	if !resized && int64(len(data)) >= size {
+		// re-encoding does not help, keep the original
+		return doc, nil, nil
+	}
+
+	// the checksum sent by client is about the uploaded image, S3 get the one of the optimized bytes
+	if doc, err = verifyClientChecksum(doc, original); err != nil {
+		return doc, nil, err
+	}
+
+	optimized = doc
+	optimized.body = bytes.NewReader(data)
+	optimized.contentType = contentType
+	if format != sourceFormat {
+		optimized.key = strings.TrimSuffix(doc.key, path.Ext(doc.key)) + formatExtensions[format]
+	}
+	optimized.metadata = withMetadata(doc.metadata, map[string]string{
+		metaOriginalSize: strconv.FormatInt(size, 10),
+		metaStoredSize:   strconv.Itoa(len(data)),
+	})
+
+	if !config.keepOriginal {
+		original = nil
+	}
+	return optimized, original, nil
+}
+
+// storeOriginal keep the bytes uploaded before optimization under _originals/, with their own extension
+func (u *usecase) storeOriginal(ctx context.Context, bucketName, key, originalKey string, original []byte, contentType string) error {
+	_, err := u.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(originalPrefix + strings.TrimSuffix(key, path.Ext(key)) + path.Ext(originalKey)),
+		Body:        bytes.NewReader(original),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload original: %w", err)
+	}
+	return nil
+}
+
+// withMetadata return a copy of metadata with the extra entries
+func withMetadata(metadata, extra map[string]string) map[string]string {
+	merged := make(map[string]string, len(metadata)+len(extra))
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
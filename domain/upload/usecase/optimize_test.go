@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testPng(t *testing.T, width, height int, alpha uint8) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * y), G: uint8(x ^ y), B: uint8(x + y), A: alpha})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func Test_UploadBase64_Optimize(t *testing.T) {
+	os.Setenv("IMAGE_OPTIMIZE_THRESHOLD", "1024")
+	os.Setenv("IMAGE_OPTIMIZE_MAX_DIMENSION", "32")
+	os.Setenv("IMAGE_OPTIMIZE_FORMAT", "jpeg")
+	defer os.Unsetenv("IMAGE_OPTIMIZE_THRESHOLD")
+	defer os.Unsetenv("IMAGE_OPTIMIZE_MAX_DIMENSION")
+	defer os.Unsetenv("IMAGE_OPTIMIZE_FORMAT")
+	defer os.Unsetenv("IMAGE_OPTIMIZE_KEEP_ORIGINAL")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	opaque := testPng(t, 64, 64, 255)
+	transparent := testPng(t, 64, 64, 128)
+	small := testPng(t, 4, 4, 255)
+
+	putKey := func(key string, match func(input *s3.PutObjectInput) bool) interface{} {
+		return mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+			return aws.ToString(input.Key) == key && match(input)
+		})
+	}
+	optimized := func(input *s3.PutObjectInput) bool {
+		return input.Metadata[metaOriginalSize] != "" && input.Metadata[metaStoredSize] != ""
+	}
+
+	tests := []struct {
+		name                string
+		content             []byte
+		keepOriginal        string
+		prepare             func()
+		expectedKey         string
+		expectedContentType string
+	}{
+		{
+			name:    "ConvertOpaquePng",
+			content: opaque,
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, putKey("data/example.jpg", func(input *s3.PutObjectInput) bool {
+					return optimized(input) && input.Metadata[metaOriginalSize] == strconv.Itoa(len(opaque))
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expectedKey:         "data/example.jpg",
+			expectedContentType: "image/jpeg",
+		},
+		{
+			name:    "KeepPngWithAlpha",
+			content: transparent,
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, putKey("data/example.png", optimized)).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expectedKey:         "data/example.png",
+			expectedContentType: "image/png",
+		},
+		{
+			name:         "KeepOriginal",
+			content:      opaque,
+			keepOriginal: "true",
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, putKey("data/example.jpg", optimized)).Return(&s3.PutObjectOutput{}, nil).Once()
+				mockS3Client.On("PutObject", mock.Anything, putKey("_originals/data/example.png", func(input *s3.PutObjectInput) bool {
+					return aws.ToString(input.ContentType) == "image/png"
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expectedKey:         "data/example.jpg",
+			expectedContentType: "image/jpeg",
+		},
+		{
+			name:    "BelowThreshold",
+			content: small,
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, putKey("data/example.png", func(input *s3.PutObjectInput) bool {
+					return input.Metadata == nil
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expectedKey:         "data/example.png",
+			expectedContentType: "image/png",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("IMAGE_OPTIMIZE_KEEP_ORIGINAL", tt.keepOriginal)
+			tt.prepare()
+
+			response, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+				DocumentKey:    "data",
+				DocumentName:   "example",
+				DocumentBase64: "data:image/png;base64," + base64.StdEncoding.EncodeToString(tt.content),
+			})
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedKey, response.Key)
+			require.Equal(t, tt.expectedContentType, response.ContentType)
+		})
+	}
+}
+
+func Test_OptimizeUpload_Checksum(t *testing.T) {
+	os.Setenv("IMAGE_OPTIMIZE_THRESHOLD", "1024")
+	os.Setenv("IMAGE_OPTIMIZE_MAX_DIMENSION", "32")
+	os.Setenv("IMAGE_OPTIMIZE_FORMAT", "jpeg")
+	defer os.Unsetenv("IMAGE_OPTIMIZE_THRESHOLD")
+	defer os.Unsetenv("IMAGE_OPTIMIZE_MAX_DIMENSION")
+	defer os.Unsetenv("IMAGE_OPTIMIZE_FORMAT")
+
+	content := testPng(t, 64, 64, 255)
+	sum := sha256.Sum256(content)
+
+	// the checksum of the uploaded image is verified before it is replaced by the optimized one
+	doc, _, err := optimizeUpload(upload{key: "data/example.png", body: bytes.NewReader(content), contentType: "image/png", expectedSHA256: base64.StdEncoding.EncodeToString(sum[:])})
+	require.NoError(t, err)
+	require.Equal(t, "data/example.jpg", doc.key)
+	require.Empty(t, doc.expectedSHA256)
+
+	_, _, err = optimizeUpload(upload{key: "data/example.png", body: bytes.NewReader(content), contentType: "image/png", expectedSHA256: base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))})
+	require.ErrorIs(t, err, document.ErrChecksumMismatch)
+}
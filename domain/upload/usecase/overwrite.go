@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/constant"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// maxRenameAttempt is how many suffixed keys are tried before giving up on rename policy
+const maxRenameAttempt = 100
+
+// overwritePolicy resolve policy from request, then OVERWRITE_POLICY_PREFIXES
+// (ex: "invoices/=reject,avatars/=allow", longest prefix win), then OVERWRITE_POLICY.
+// a misspelled policy is an error rather than the overwrite the upload would otherwise do
+func overwritePolicy(requested, key string) (string, error) {
+	policy := requested
+	if policy == "" {
+		matched := -1
+		for _, rule := range strings.Split(os.Getenv("OVERWRITE_POLICY_PREFIXES"), ",") {
+			prefix, value, ok := strings.Cut(strings.TrimSpace(rule), "=")
+			if !ok || !strings.HasPrefix(key, prefix) || len(prefix) <= matched {
+				continue
+			}
+			policy, matched = value, len(prefix)
+		}
+	}
+	if policy == "" {
+		policy = os.Getenv("OVERWRITE_POLICY")
+	}
+
+	switch policy {
+	case "":
+		return constant.OVERWRITE_ALLOW, nil
+	case constant.OVERWRITE_ALLOW, constant.OVERWRITE_REJECT, constant.OVERWRITE_RENAME:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown overwrite policy %q, expected allow, reject or rename", policy)
+}
+
+// putObject store the object according the overwrite policy and return the key finally used
+func (u *usecase) putObject(ctx context.Context, input *s3.PutObjectInput, policy string) (output *s3.PutObjectOutput, key string, err error) {
+	key = aws.ToString(input.Key)
+
+	switch policy {
+	case constant.OVERWRITE_REJECT:
+		output, err = u.putIfAbsent(ctx, input)
+		if errors.Is(err, errObjectExists) {
+			err = document.ErrDocumentAlreadyExists
+		}
+		return
+	case constant.OVERWRITE_RENAME:
+		for attempt := 0; attempt <= maxRenameAttempt; attempt++ {
+			candidate := renameKey(key, attempt)
+
+			var exists bool
+			exists, err = u.objectExists(ctx, aws.ToString(input.Bucket), candidate)
+			if err != nil {
+				return
+			}
+			if exists {
+				continue
+			}
+
+			input.Key = aws.String(candidate)
+			output, err = u.putIfAbsent(ctx, input)
+			if errors.Is(err, errObjectExists) {
+				// someone else take the key in between, rewind and try the next one
+				if err = rewind(input.Body); err != nil {
+					return
+				}
+				continue
+			}
+			return output, candidate, err
+		}
+		err = document.ErrDocumentAlreadyExists
+		return
+	default:
+		output, err = u.s3Client.PutObject(ctx, input)
+		return
+	}
+}
+
+var errObjectExists = errors.New("object exists")
+
+// putIfAbsent use conditional write (If-None-Match: *) and fallback to HeadObject
+// when the backend does not support it or S3_CONDITIONAL_WRITE=false
+func (u *usecase) putIfAbsent(ctx context.Context, input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	if os.Getenv("S3_CONDITIONAL_WRITE") != "false" {
+		input.IfNoneMatch = aws.String("*")
+		output, err := u.s3Client.PutObject(ctx, input)
+		input.IfNoneMatch = nil
+
+		switch apiErrorCode(err) {
+		case "":
+			return output, err
+		case "PreconditionFailed", "ConditionalRequestConflict":
+			return nil, errObjectExists
+		case "NotImplemented":
+			if err = rewind(input.Body); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, err
+		}
+	}
+
+	exists, err := u.objectExists(ctx, aws.ToString(input.Bucket), aws.ToString(input.Key))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errObjectExists
+	}
+
+	return u.s3Client.PutObject(ctx, input)
+}
+
+func (u *usecase) objectExists(ctx context.Context, bucket, key string) (bool, error) {
+	_, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		return true, nil
+	}
+	if isNotFound(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check document: %w", err)
+}
+
+// renameKey add suffix before the extension, ex: folder/example.png -> folder/example-1.png
+func renameKey(key string, attempt int) string {
+	if attempt == 0 {
+		return key
+	}
+	ext := path.Ext(key)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(key, ext), attempt, ext)
+}
+
+func rewind(body io.Reader) error {
+	seeker, ok := body.(io.Seeker)
+	if !ok {
+		return fmt.Errorf("failed to retry upload: body is not seekable")
+	}
+	_, err := seeker.Seek(0, io.SeekStart)
+	return err
+}
+
+func apiErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return "Unknown"
+}
+
+func isNotFound(err error) bool {
+	var notFound *types.NotFound
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &notFound) || errors.As(err, &noSuchKey) {
+		return true
+	}
+	code := apiErrorCode(err)
+	return code == "NotFound" || code == "NoSuchKey" || code == "NoSuchVersion"
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OverwritePolicy(t *testing.T) {
+	os.Setenv("OVERWRITE_POLICY_PREFIXES", "invoices/=reject, invoices/draft/=rename, avatars/=replace")
+	os.Setenv("OVERWRITE_POLICY", "")
+	defer os.Unsetenv("OVERWRITE_POLICY_PREFIXES")
+
+	tests := []struct {
+		name      string
+		requested string
+		key       string
+		expected  string
+		err       string
+	}{
+		{name: "requested", requested: "allow", key: "invoices/a.pdf", expected: "allow"},
+		{name: "prefix", key: "invoices/a.pdf", expected: "reject"},
+		{name: "longest prefix", key: "invoices/draft/a.pdf", expected: "rename"},
+		{name: "default", key: "other/a.pdf", expected: "allow"},
+		{name: "unknown requested", requested: "overwrite", key: "other/a.pdf", err: `unknown overwrite policy "overwrite", expected allow, reject or rename`},
+		{name: "unknown prefix", key: "avatars/a.png", err: `unknown overwrite policy "replace", expected allow, reject or rename`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := overwritePolicy(tt.requested, tt.key)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, policy)
+		})
+	}
+}
+
+func Test_UploadBase64_UnknownOverwritePolicy(t *testing.T) {
+	usecase, _ := initUseCaseUnitTest(t)
+	os.Setenv("OVERWRITE_POLICY", "Reject")
+	defer os.Unsetenv("OVERWRITE_POLICY")
+
+	// nothing is stored, a misspelled reject must not overwrite
+	_, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+	})
+	require.EqualError(t, err, `unknown overwrite policy "Reject", expected allow, reject or rename`)
+}
+
+func Test_RenameKey(t *testing.T) {
+	require.Equal(t, "data/example.txt", renameKey("data/example.txt", 0))
+	require.Equal(t, "data/example-2.txt", renameKey("data/example.txt", 2))
+	require.Equal(t, "data/example-1", renameKey("data/example", 1))
+}
+
+func Test_UploadBase64_Overwrite(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	conditional := mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.ToString(input.IfNoneMatch) == "*"
+	})
+	headKey := func(key string) interface{} {
+		return mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+			return aws.ToString(input.Key) == key
+		})
+	}
+
+	type expected struct {
+		err      error
+		response document.ResponseUploadDocument
+	}
+	tests := []struct {
+		name      string
+		overwrite string
+		prepare   func()
+		expected  expected
+	}{
+		{
+			name:      "Reject_ConditionalWriteFailed",
+			overwrite: "reject",
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, conditional).Return(nil, &smithy.GenericAPIError{Code: "PreconditionFailed"}).Once()
+			},
+			expected: expected{
+				err: document.ErrDocumentAlreadyExists,
+			},
+		},
+		{
+			name:      "Reject_FallbackHeadObjectExists",
+			overwrite: "reject",
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, conditional).Return(nil, &smithy.GenericAPIError{Code: "NotImplemented"}).Once()
+				mockS3Client.On("HeadObject", mock.Anything, headKey("data/example.txt")).Return(&s3.HeadObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				err: document.ErrDocumentAlreadyExists,
+			},
+		},
+		{
+			name:      "Reject_FallbackHeadObjectNotFound",
+			overwrite: "reject",
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, conditional).Return(nil, &smithy.GenericAPIError{Code: "NotImplemented"}).Once()
+				mockS3Client.On("HeadObject", mock.Anything, headKey("data/example.txt")).Return(nil, &types.NotFound{}).Once()
+				mockS3Client.On("PutObject", mock.Anything, mock.Anything).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadDocument{
+					DocumentUrl: fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), "data/example.txt"),
+					Key:         "data/example.txt",
+					Bucket:      "test-bucket",
+					Size:        20,
+					ContentType: "text/txt",
+				},
+			},
+		},
+		{
+			name:      "Reject_HeadObjectError",
+			overwrite: "reject",
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, conditional).Return(nil, &smithy.GenericAPIError{Code: "NotImplemented"}).Once()
+				mockS3Client.On("HeadObject", mock.Anything, headKey("data/example.txt")).Return(nil, &smithy.GenericAPIError{Code: "AccessDenied"}).Once()
+			},
+			expected: expected{
+				err: fmt.Errorf("failed to upload file: %w", fmt.Errorf("failed to check document: %w", &smithy.GenericAPIError{Code: "AccessDenied"})),
+			},
+		},
+		{
+			name:      "Rename_NextFreeKey",
+			overwrite: "rename",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, headKey("data/example.txt")).Return(&s3.HeadObjectOutput{}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, headKey("data/example-1.txt")).Return(nil, &types.NotFound{}).Once()
+				mockS3Client.On("PutObject", mock.Anything, conditional).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadDocument{
+					DocumentUrl: fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), "data/example-1.txt"),
+					Key:         "data/example-1.txt",
+					Bucket:      "test-bucket",
+					Size:        20,
+					ContentType: "text/txt",
+				},
+			},
+		},
+		{
+			name:      "Rename_RaceOnConditionalWrite",
+			overwrite: "rename",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, headKey("data/example.txt")).Return(nil, &types.NotFound{}).Once()
+				mockS3Client.On("PutObject", mock.Anything, conditional).Return(nil, &smithy.GenericAPIError{Code: "PreconditionFailed"}).Once()
+				mockS3Client.On("HeadObject", mock.Anything, headKey("data/example-1.txt")).Return(nil, &types.NotFound{}).Once()
+				mockS3Client.On("PutObject", mock.Anything, conditional).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadDocument{
+					DocumentUrl: fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), "data/example-1.txt"),
+					Key:         "data/example-1.txt",
+					Bucket:      "test-bucket",
+					Size:        20,
+					ContentType: "text/txt",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			response, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+				DocumentKey:    "data",
+				DocumentName:   "example",
+				DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+				Overwrite:      tt.overwrite,
+			})
+
+			require.Equal(t, tt.expected.err, err)
+			require.Equal(t, tt.expected.response, response)
+		})
+	}
+}
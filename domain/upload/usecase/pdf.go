@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	metaPdfPages     = "pdf-pages"
+	metaPdfTitle     = "pdf-title"
+	metaPdfAuthor    = "pdf-author"
+	metaPdfEncrypted = "pdf-encrypted"
+
+	pdfContentType   = "application/pdf"
+	pdfPreviewName   = "preview"
+	pdfPreviewMaxDim = 800
+
+	// pdfTextMaxLength is the budget of the encoded title and author, S3 refuse more than 2 KB of user metadata
+	pdfTextMaxLength = 256
+)
+
+func isPdf(doc upload) bool {
+	return doc.contentType == pdfContentType || strings.EqualFold(path.Ext(doc.key), ".pdf")
+}
+
+// inspectPdf read the PDF info and apply PDF_ENCRYPTED_POLICY (allow, reject or reject-password),
+// nil info when the document is not a PDF or is not readable
+func inspectPdf(doc upload) (info *utils.PDFInfo, err error) {
+	if !isPdf(doc) {
+		return nil, nil
+	}
+
+	pdfInfo, err := utils.ReadPDFInfo(doc.body)
+	if rewindErr := rewind(doc.body); rewindErr != nil {
+		return nil, rewindErr
+	}
+	if err != nil {
+		// broken pdf is stored as is, without pdf metadata
+		return nil, nil
+	}
+
+	switch os.Getenv("PDF_ENCRYPTED_POLICY") {
+	case constant.PDF_ENCRYPTED_REJECT:
+		if pdfInfo.Encrypted {
+			return nil, document.ErrEncryptedDocument
+		}
+	case constant.PDF_ENCRYPTED_REJECT_PASSWORD:
+		if pdfInfo.PasswordProtected {
+			return nil, document.ErrEncryptedDocument
+		}
+	}
+
+	return &pdfInfo, nil
+}
+
+// pdfMetadata is object metadata of the PDF, non ASCII text is RFC 2047 encoded as S3 metadata are http headers
+func pdfMetadata(info utils.PDFInfo) map[string]string {
+	metadata := map[string]string{
+		metaPdfEncrypted: strconv.FormatBool(info.Encrypted),
+	}
+	if info.PasswordProtected {
+		return metadata
+	}
+
+	metadata[metaPdfPages] = strconv.Itoa(info.PageCount)
+	if info.Title != "" {
+		metadata[metaPdfTitle] = pdfText(info.Title)
+	}
+	if info.Author != "" {
+		metadata[metaPdfAuthor] = pdfText(info.Author)
+	}
+	return metadata
+}
+
+// pdfText is the encoded text truncated to pdfTextMaxLength, a long title must not fail the upload
+func pdfText(text string) string {
+	// every rune take at least one encoded byte, longer text never fit
+	if runes := []rune(text); len(runes) > pdfTextMaxLength {
+		text = string(runes[:pdfTextMaxLength])
+	}
+	for {
+		encoded := mime.QEncoding.Encode("utf-8", text)
+		if len(encoded) <= pdfTextMaxLength {
+			return encoded
+		}
+		_, size := utf8.DecodeLastRuneInString(text)
+		text = text[:len(text)-size]
+	}
+}
+
+// pdfPreviewEnabled is configured by PDF_PREVIEW=true
+func pdfPreviewEnabled() bool {
+	return os.Getenv("PDF_PREVIEW") == "true"
+}
+
+// storePdfPreview store the first page image as a rendition of the PDF (see pdfPreviewKey) and return its url,
+// empty when the first page has no image
+func (u *usecase) storePdfPreview(ctx context.Context, bucketName, key, documentId string, doc upload) (url string, err error) {
+	if err = rewind(doc.body); err != nil {
+		return
+	}
+	preview, err := utils.PDFPreview(doc.body)
+	if err != nil || preview == nil {
+		return "", nil
+	}
+
+	if bounds := preview.Bounds(); bounds.Dx() > pdfPreviewMaxDim || bounds.Dy() > pdfPreviewMaxDim {
+		preview = utils.ResizeImage(preview, pdfPreviewMaxDim, pdfPreviewMaxDim, constant.IMAGE_FIT_CONTAIN)
+	}
+	data, contentType, err := utils.EncodeImage(preview, constant.IMAGE_FORMAT_PNG, defaultImageQuality)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate pdf preview: %w", err)
+	}
+
+	previewKey := pdfPreviewKey(key)
+	_, err = u.s3Client.PutObject(ctx, withDerivedVisibility(&s3.PutObjectInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(previewKey),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	}, doc.metadata))
+	if err != nil {
+		return "", fmt.Errorf("failed to upload pdf preview: %w", err)
+	}
+
+	return derivedUrl(key, documentId, pdfPreviewName), nil
+}
+
+// pdfPreviewKey is the key of the preview of the PDF, stored as the renditions,
+// ex: folder/invoice.pdf -> _renditions/folder/invoice.pdf/@preview.png
+func pdfPreviewKey(key string) string {
+	return fmt.Sprintf("%s%s/@%s.png", renditionPrefix, key, pdfPreviewName)
+}
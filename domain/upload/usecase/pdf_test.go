@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testPDF build one page PDF with the given title
+func testPDF(title string) []byte {
+	objects := []string{
+		"<</Type/Catalog/Pages 2 0 R>>",
+		"<</Type/Pages/Kids[3 0 R]/Count 1>>",
+		"<</Type/Page/Parent 2 0 R/MediaBox[0 0 612 792]>>",
+		fmt.Sprintf("<</Title(%s)/Author(Jane)>>", title),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<</Root 1 0 R/Info 4 0 R/Size %d>>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func Test_UploadBase64_Pdf(t *testing.T) {
+	defer os.Unsetenv("PDF_ENCRYPTED_POLICY")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	pdf := testPDF("Invoice")
+	var protected bytes.Buffer
+	require.NoError(t, api.Encrypt(bytes.NewReader(pdf), &protected, model.NewAESConfiguration("secret", "owner", 256)))
+
+	tests := []struct {
+		name     string
+		policy   string
+		content  []byte
+		prepare  func()
+		expected error
+	}{
+		{
+			name:    "Metadata",
+			content: pdf,
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+					return aws.ToString(input.ContentType) == "application/pdf" && input.Metadata[metaPdfPages] == "1" &&
+						input.Metadata[metaPdfTitle] == "Invoice" && input.Metadata[metaPdfAuthor] == "Jane" && input.Metadata[metaPdfEncrypted] == "false"
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+		},
+		{
+			name:    "PasswordProtectedAllowed",
+			policy:  "allow",
+			content: protected.Bytes(),
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+					_, hasPages := input.Metadata[metaPdfPages]
+					return input.Metadata[metaPdfEncrypted] == "true" && !hasPages
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+		},
+		{
+			name:     "PasswordProtectedRejected",
+			policy:   "reject-password",
+			content:  protected.Bytes(),
+			prepare:  func() {},
+			expected: document.ErrEncryptedDocument,
+		},
+		{
+			name:     "EncryptedRejected",
+			policy:   "reject",
+			content:  protected.Bytes(),
+			prepare:  func() {},
+			expected: document.ErrEncryptedDocument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("PDF_ENCRYPTED_POLICY", tt.policy)
+			tt.prepare()
+
+			_, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+				DocumentKey:    "data",
+				DocumentName:   "invoice",
+				DocumentBase64: "data:application/pdf;base64," + base64.StdEncoding.EncodeToString(tt.content),
+			})
+
+			require.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func Test_PdfMetadata_NonASCII(t *testing.T) {
+	metadata := pdfMetadata(utils.PDFInfo{PageCount: 1, Title: "Facture été"})
+
+	require.Equal(t, "=?utf-8?q?Facture_=C3=A9t=C3=A9?=", metadata[metaPdfTitle])
+	require.Equal(t, "Facture été", decodeMetadata(metadata)[metaPdfTitle])
+}
+
+func Test_PdfMetadata_LongText(t *testing.T) {
+	title := strings.Repeat("été ", 1000)
+	metadata := pdfMetadata(utils.PDFInfo{PageCount: 1, Title: title, Author: strings.Repeat("a", 5000)})
+
+	require.LessOrEqual(t, len(metadata[metaPdfTitle]), pdfTextMaxLength)
+	require.LessOrEqual(t, len(metadata[metaPdfAuthor]), pdfTextMaxLength)
+
+	decoded := decodeMetadata(metadata)
+	require.NotEmpty(t, decoded[metaPdfTitle])
+	require.True(t, strings.HasPrefix(title, decoded[metaPdfTitle]))
+	require.True(t, utf8.ValidString(decoded[metaPdfTitle]))
+	require.Equal(t, strings.Repeat("a", len(decoded[metaPdfAuthor])), decoded[metaPdfAuthor])
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const defaultSignedUrlExpires = 15 * time.Minute
+
+// signedUrlExpires is lifetime of signed url, configured by SIGNED_URL_EXPIRES (ex: 10m)
+func signedUrlExpires() time.Duration {
+	expires, err := time.ParseDuration(os.Getenv("SIGNED_URL_EXPIRES"))
+	if err != nil || expires <= 0 {
+		return defaultSignedUrlExpires
+	}
+	return expires
+}
+
+// signUrl return short-lived url to get the object directly from S3, empty when no presigner configured
+func (u *usecase) signUrl(ctx context.Context, bucketName, key string) (signedUrl string, expiresAt *time.Time, err error) {
+	if u.presigner == nil {
+		return
+	}
+
+	expires := signedUrlExpires()
+	request, err := u.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		err = fmt.Errorf("failed to sign url: %w", err)
+		return
+	}
+
+	return request.URL, aws.Time(time.Now().Add(expires).UTC()), nil
+}
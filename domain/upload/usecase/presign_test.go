@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"aws-s3-bucket/domain/upload/interfaces/mocks"
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UploadBase64_SignedUrl(t *testing.T) {
+	mockS3Client := mocks.NewS3Interface(t)
+	mockPresigner := mocks.NewPresignInterface(t)
+	os.Setenv("BUCKET_NAME", "test-bucket")
+	os.Setenv("SIGNED_URL_EXPIRES", "5m")
+	defer os.Unsetenv("SIGNED_URL_EXPIRES")
+
+	usecase := NewUsecase(mockS3Client, WithPresigner(mockPresigner))
+
+	tests := []struct {
+		name       string
+		visibility string
+		prepare    func()
+		signedUrl  string
+		err        error
+	}{
+		{
+			name: "SignedUrl_Success",
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, mock.Anything).Return(&s3.PutObjectOutput{ETag: aws.String(`"etag"`)}, nil).Once()
+				mockPresigner.On("PresignGetObject", mock.Anything, mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+					return aws.ToString(input.Key) == "data/example.txt"
+				}), mock.Anything).Return(&v4.PresignedHTTPRequest{URL: "https://s3.example.com/data/example.txt?X-Amz-Signature=abc"}, nil).Once()
+			},
+			signedUrl: "https://s3.example.com/data/example.txt?X-Amz-Signature=abc",
+		},
+		{
+			name: "SignedUrl_Failure",
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, mock.Anything).Return(&s3.PutObjectOutput{}, nil).Once()
+				mockPresigner.On("PresignGetObject", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("no credentials")).Once()
+			},
+			err: fmt.Errorf("failed to sign url: %w", errors.New("no credentials")),
+		},
+		{
+			name:       "SignedUrl_Public",
+			visibility: visibilityPublic,
+			prepare: func() {
+				// public document need no signed url, the presigner is not called
+				mockS3Client.On("PutObject", mock.Anything, mock.Anything).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			response, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+				DocumentKey:    "data",
+				DocumentName:   "example",
+				DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+				Visibility:     tt.visibility,
+			})
+
+			require.Equal(t, tt.err, err)
+			require.Equal(t, tt.signedUrl, response.SignedUrl)
+			if tt.visibility == visibilityPublic {
+				require.Empty(t, response.SignedUrl)
+				require.Nil(t, response.SignedUrlExpiresAt)
+			}
+			if tt.signedUrl != "" {
+				require.Equal(t, `"etag"`, response.ETag)
+				require.WithinDuration(t, time.Now().Add(5*time.Minute), *response.SignedUrlExpiresAt, time.Minute)
+			}
+		})
+	}
+}
+
+func Test_DetectContentType(t *testing.T) {
+	file, fileHeader, err := createMultipartFile("%PDF-1.4 test", "test.pdf")
+	require.NoError(t, err)
+	defer file.Close()
+	fileHeader.Header.Set("Content-Type", "application/octet-stream")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.ToString(input.ContentType) == "application/pdf"
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadFile(nil, document.RequestUploadDocumentFile{DocumentKey: "data", DocumentName: "example"}, fileHeader)
+
+	require.NoError(t, err)
+	require.Equal(t, "application/pdf", response.ContentType)
+	require.Equal(t, int64(13), response.Size)
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// profileFormats is image extension that get eager renditions and the format they are encoded with,
+// gif is not encodable in pure go so its renditions are png
+var profileFormats = map[string]string{
+	".jpg":  constant.IMAGE_FORMAT_JPEG,
+	".jpeg": constant.IMAGE_FORMAT_JPEG,
+	".png":  constant.IMAGE_FORMAT_PNG,
+	".webp": constant.IMAGE_FORMAT_WEBP,
+	".gif":  constant.IMAGE_FORMAT_PNG,
+}
+
+type renditionProfile struct {
+	name   string
+	width  int
+	height int
+	fit    string
+}
+
+// renditionProfiles parse RENDITION_PROFILES, ex: "thumb:200x200:cover,medium:800w",
+// size is WxH, Nw or Nh and fit is optional (default contain), invalid profile is ignored
+func renditionProfiles() (profiles []renditionProfile) {
+	for _, rule := range strings.Split(os.Getenv("RENDITION_PROFILES"), ",") {
+		parts := strings.Split(strings.TrimSpace(rule), ":")
+		if len(parts) < 2 || parts[0] == "" {
+			continue
+		}
+
+		profile := renditionProfile{name: parts[0], fit: constant.IMAGE_FIT_CONTAIN}
+		if len(parts) > 2 {
+			profile.fit = parts[2]
+		}
+
+		var err error
+		size := strings.ToLower(parts[1])
+		switch {
+		case strings.HasSuffix(size, "w"):
+			profile.width, err = strconv.Atoi(strings.TrimSuffix(size, "w"))
+		case strings.HasSuffix(size, "h"):
+			profile.height, err = strconv.Atoi(strings.TrimSuffix(size, "h"))
+		default:
+			width, height, _ := strings.Cut(size, "x")
+			if profile.width, err = strconv.Atoi(width); err == nil {
+				profile.height, err = strconv.Atoi(height)
+			}
+		}
+		if err != nil || profile.width < 0 || profile.height < 0 || profile.width+profile.height == 0 {
+			continue
+		}
+
+		profiles = append(profiles, profile)
+	}
+	return
+}
+
+// profileKey is key of the rendition under the internal prefix, an upload can neither replace it nor be deleted
+// with the original, ex: folder/example.png -> _renditions/folder/example.png/@thumb.png
+func profileKey(key, name string) string {
+	ext := path.Ext(key)
+	format := profileFormats[strings.ToLower(ext)]
+	if format == constant.IMAGE_FORMAT_PNG {
+		ext = ".png"
+	}
+	return fmt.Sprintf("%s%s/@%s%s", renditionPrefix, key, name, ext)
+}
+
+// derivedKey is the key of the rendition profile or the pdf preview named name of the document
+func derivedKey(key, name string) (string, error) {
+	if name == pdfPreviewName {
+		return pdfPreviewKey(key), nil
+	}
+	for _, profile := range renditionProfiles() {
+		if profile.name == name {
+			return profileKey(key, name), nil
+		}
+	}
+	return "", fmt.Errorf("%w: no rendition %s", document.ErrDocumentNotFound, name)
+}
+
+// storeRenditions generate every profile of the uploaded image and return their url by profile name,
+// nothing is generated when no profile is configured or the document is not an image we can decode
+func (u *usecase) storeRenditions(ctx context.Context, bucketName, key, documentId string, doc upload) (renditions map[string]string, err error) {
+	format, ok := profileFormats[strings.ToLower(path.Ext(key))]
+	profiles := renditionProfiles()
+	if !ok || len(profiles) == 0 || !strings.HasPrefix(doc.contentType, "image/") {
+		return nil, nil
+	}
+
+	if err = rewind(doc.body); err != nil {
+		return
+	}
+	src, _, err := utils.DecodeImage(doc.body)
+	if err != nil {
+		// content type say image but it is not decodable (ex: svg), keep only the original
+		return nil, nil
+	}
+
+	renditions = make(map[string]string, len(profiles))
+	for _, profile := range profiles {
+		data, contentType, err := utils.EncodeImage(utils.ResizeImage(src, profile.width, profile.height, profile.fit), format, defaultImageQuality)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate rendition %s: %w", profile.name, err)
+		}
+
+		renditionKey := profileKey(key, profile.name)
+		_, err = u.s3Client.PutObject(ctx, withDerivedVisibility(&s3.PutObjectInput{
+			Bucket:      aws.String(bucketName),
+			Key:         aws.String(renditionKey),
+			Body:        bytes.NewReader(data),
+			ContentType: aws.String(contentType),
+		}, doc.metadata))
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload rendition %s: %w", profile.name, err)
+		}
+
+		renditions[profile.name] = derivedUrl(key, documentId, profile.name)
+	}
+
+	return renditions, nil
+}
+
+// deleteRenditions remove the renditions of the original, deleting missing key is not an error in S3
+func (u *usecase) deleteRenditions(ctx context.Context, bucketName, key string) error {
+	if _, ok := profileFormats[strings.ToLower(path.Ext(key))]; !ok {
+		return nil
+	}
+
+	for _, profile := range renditionProfiles() {
+		_, err := u.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(profileKey(key, profile.name)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete rendition %s: %w", profile.name, err)
+		}
+	}
+	return nil
+}
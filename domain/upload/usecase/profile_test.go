@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RenditionProfiles(t *testing.T) {
+	os.Setenv("RENDITION_PROFILES", "thumb:200x200:cover, medium:800w,tall:300h,broken:abc,:10w,empty:0x0")
+	defer os.Unsetenv("RENDITION_PROFILES")
+
+	require.Equal(t, []renditionProfile{
+		{name: "thumb", width: 200, height: 200, fit: "cover"},
+		{name: "medium", width: 800, fit: "contain"},
+		{name: "tall", height: 300, fit: "contain"},
+	}, renditionProfiles())
+}
+
+func Test_ProfileKey(t *testing.T) {
+	require.Equal(t, "_renditions/data/example.jpg/@thumb.jpg", profileKey("data/example.jpg", "thumb"))
+	require.Equal(t, "_renditions/data/example.gif/@thumb.png", profileKey("data/example.gif", "thumb"))
+	require.True(t, isInternalKey(profileKey("data/example.png", "thumb")))
+}
+
+func Test_UploadBase64_Renditions(t *testing.T) {
+	os.Setenv("RENDITION_PROFILES", "thumb:4x4:cover,medium:8w")
+	defer os.Unsetenv("RENDITION_PROFILES")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	var source bytes.Buffer
+	require.NoError(t, png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 16, 8))))
+
+	putKey := func(key string) interface{} {
+		return mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+			return aws.ToString(input.Key) == key
+		})
+	}
+	mockS3Client.On("PutObject", mock.Anything, putKey("data/example.png")).Return(&s3.PutObjectOutput{}, nil).Once()
+	mockS3Client.On("PutObject", mock.Anything, putKey("_renditions/data/example.png/@thumb.png")).Return(&s3.PutObjectOutput{}, nil).Once()
+	mockS3Client.On("PutObject", mock.Anything, putKey("_renditions/data/example.png/@medium.png")).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:image/png;base64," + base64.StdEncoding.EncodeToString(source.Bytes()),
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"thumb":  "http://localhost:8080/api/v1/download/data/example.png?rendition=thumb",
+		"medium": "http://localhost:8080/api/v1/download/data/example.png?rendition=medium",
+	}, response.Renditions)
+
+	// not an image, no rendition
+	mockS3Client.On("PutObject", mock.Anything, putKey("data/example.txt")).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err = usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+	})
+
+	require.NoError(t, err)
+	require.Nil(t, response.Renditions)
+}
+
+func Test_UploadBase64_PrivateRenditions(t *testing.T) {
+	os.Setenv("RENDITION_PROFILES", "thumb:4x4:cover")
+	defer os.Unsetenv("RENDITION_PROFILES")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	var source bytes.Buffer
+	require.NoError(t, png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 16, 8))))
+
+	// the rendition is as private as its document
+	for _, key := range []string{"data/example.png", "_renditions/data/example.png/@thumb.png"} {
+		mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+			return aws.ToString(input.Key) == key && input.Metadata[metaVisibility] == visibilityPrivate &&
+				aws.ToString(input.CacheControl) == "private, no-store"
+		})).Return(&s3.PutObjectOutput{}, nil).Once()
+	}
+
+	_, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:image/png;base64," + base64.StdEncoding.EncodeToString(source.Bytes()),
+		Visibility:     visibilityPrivate,
+	})
+	require.NoError(t, err)
+}
+
+func Test_DeleteFile_Renditions(t *testing.T) {
+	os.Setenv("RENDITION_PROFILES", "thumb:200x200")
+	defer os.Unsetenv("RENDITION_PROFILES")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.png")).Return(&s3.HeadObjectOutput{}, nil).Once()
+	mockS3Client.On("DeleteObject", mock.Anything, matchKey("data/example.png")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+	mockS3Client.On("DeleteObject", mock.Anything, matchKey("_renditions/data/example.png/@thumb.png")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+
+	require.NoError(t, usecase.DeleteFile(nil, "data/example.png"))
+}
+
+func Test_DownloadFile_RenditionByKey(t *testing.T) {
+	os.Setenv("RENDITION_PROFILES", "thumb:4x4:cover")
+	defer os.Unsetenv("RENDITION_PROFILES")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	// the rendition is served through its document, its internal key cannot be downloaded directly
+	mockS3Client.On("GetObject", mock.Anything, matchKey("_renditions/data/example.png/@thumb.png")).Return(&s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("thumb"))}, nil).Once()
+	_, err := usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.png", Rendition: "thumb"})
+	require.NoError(t, err)
+
+	_, err = usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: profileKey("data/example.png", "thumb")})
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"aws-s3-bucket/domain/upload/interfaces"
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/constant"
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+const defaultListLimit = 100
+
+// WithRepository record every upload and delete in the metadata database, documents are listed from it
+func WithRepository(repository interfaces.RepositoryInterface) Option {
+	return func(u *usecase) {
+		u.repository = repository
+	}
+}
+
+// uploader is the caller identity stored by the middleware from the X-Uploader-Id header
+func uploader(ctx context.Context) string {
+	uploader, _ := ctx.Value(constant.HEADER_UPLOADER).(string)
+	return uploader
+}
+
+// saveRecord write the stored document in the metadata database with the tags and custom metadata
+// of its object metadata, nothing to do when there is no database
+func (u *usecase) saveRecord(ctx context.Context, response document.ResponseUploadDocument, metadata map[string]string) error {
+	if u.repository == nil {
+		return nil
+	}
+
+	tags, custom := userMetadata(metadata)
+	err := u.repository.SaveDocument(ctx, document.Record{
+		DocumentId:     response.Id,
+		Key:            response.Key,
+		Bucket:         response.Bucket,
+		Size:           response.Size,
+		ContentType:    response.ContentType,
+		ChecksumSHA256: response.ChecksumSHA256,
+		Uploader:       uploader(ctx),
+		Visibility:     metadata[metaVisibility],
+		Status:         document.RecordStatusActive,
+		Tags:           tags,
+		Metadata:       custom,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record document: %w", err)
+	}
+	return nil
+}
+
+// deleteRecord soft delete the document in the metadata database, nothing to do when there is no database
+func (u *usecase) deleteRecord(ctx context.Context, bucket, key string) error {
+	if u.repository == nil {
+		return nil
+	}
+
+	if err := u.repository.DeleteDocument(ctx, bucket, key, time.Now()); err != nil {
+		return fmt.Errorf("failed to record document deletion: %w", err)
+	}
+	return nil
+}
+
+// ListDocuments list the documents from the metadata database, the bucket is never scanned
+func (u *usecase) ListDocuments(ctx context.Context, request document.RequestListDocument) (response document.ResponseListDocument, err error) {
+	if u.repository == nil {
+		return response, document.ErrDatabaseNotConfigured
+	}
+
+	bucket, err := allowedBucket(request.Bucket)
+	if err != nil {
+		return
+	}
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	records, err := u.repository.ListDocuments(ctx, document.RecordFilter{
+		Bucket:         bucket,
+		Prefix:         request.Prefix,
+		IncludeDeleted: request.IncludeDeleted,
+		// listing is not authenticated, private documents are left out
+		ExcludeVisibility: visibilityPrivate,
+		Limit:             limit,
+		Offset:            request.Offset,
+	})
+	if err != nil {
+		return response, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	response = document.ResponseListDocument{Documents: make([]document.ResponseDocumentRecord, 0, len(records)), Limit: limit, Offset: request.Offset}
+	for _, record := range records {
+		item := document.ResponseDocumentRecord{Record: record}
+		// only the default bucket is served by the download endpoint
+		if record.Bucket == os.Getenv("BUCKET_NAME") && record.DeletedAt == nil {
+			item.DocumentUrl = documentUrl(record.Key, record.DocumentId)
+		}
+		response.Documents = append(response.Documents, item)
+	}
+
+	return response, nil
+}
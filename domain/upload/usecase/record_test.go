@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"aws-s3-bucket/domain/upload/interfaces/mocks"
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/constant"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func initRecordUnitTest(t *testing.T) (*usecase, *mocks.S3Interface, *mocks.RepositoryInterface) {
+	os.Setenv("BUCKET_NAME", "test-bucket")
+	os.Setenv("BASE_URL", "http://localhost:8080")
+
+	mockS3Client := mocks.NewS3Interface(t)
+	mockRepository := mocks.NewRepositoryInterface(t)
+
+	return NewUsecase(mockS3Client, WithRepository(mockRepository)).(*usecase), mockS3Client, mockRepository
+}
+
+func Test_UploadBase64_Record(t *testing.T) {
+	usecase, mockS3Client, mockRepository := initRecordUnitTest(t)
+	request := document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+	}
+	ctx := context.WithValue(context.Background(), constant.HEADER_UPLOADER, "alice")
+
+	mockS3Client.On("PutObject", mock.Anything, mock.Anything).Return(&s3.PutObjectOutput{}, nil).Once()
+	mockRepository.On("SaveDocument", mock.Anything, document.Record{
+		Key:         "data/example.txt",
+		Bucket:      "test-bucket",
+		Size:        20,
+		ContentType: "text/txt",
+		Uploader:    "alice",
+		Status:      document.RecordStatusActive,
+	}).Return(nil).Once()
+
+	response, err := usecase.UploadBase64(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, "data/example.txt", response.Key)
+
+	mockS3Client.On("PutObject", mock.Anything, mock.Anything).Return(&s3.PutObjectOutput{}, nil).Once()
+	mockRepository.On("SaveDocument", mock.Anything, mock.Anything).Return(errors.New("connection refused")).Once()
+
+	_, err = usecase.UploadBase64(ctx, request)
+	require.ErrorContains(t, err, "failed to record document")
+}
+
+func Test_DeleteFile_Record(t *testing.T) {
+	usecase, mockS3Client, mockRepository := initRecordUnitTest(t)
+
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.HeadObjectOutput{}, nil).Once()
+	mockS3Client.On("DeleteObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.DeleteObjectOutput{}, nil).Once()
+	mockRepository.On("DeleteDocument", mock.Anything, "test-bucket", "data/example.txt", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	require.NoError(t, usecase.DeleteFile(context.Background(), "data/example.txt"))
+}
+
+func Test_ListDocuments(t *testing.T) {
+	usecase, _, mockRepository := initRecordUnitTest(t)
+	now := time.Now()
+
+	_, err := NewUsecase(mocks.NewS3Interface(t)).ListDocuments(context.Background(), document.RequestListDocument{})
+	require.ErrorIs(t, err, document.ErrDatabaseNotConfigured)
+
+	_, err = usecase.ListDocuments(context.Background(), document.RequestListDocument{Bucket: "unknown-bucket"})
+	require.ErrorIs(t, err, document.ErrBucketNotAllowed)
+
+	mockRepository.On("ListDocuments", mock.Anything, document.RecordFilter{Bucket: "test-bucket", Prefix: "data/", IncludeDeleted: true, ExcludeVisibility: visibilityPrivate, Limit: defaultListLimit}).Return([]document.Record{
+		{Key: "data/a.txt", Bucket: "test-bucket", Status: document.RecordStatusActive},
+		{Key: "data/b.txt", Bucket: "test-bucket", Status: document.RecordStatusDeleted, DeletedAt: &now},
+	}, nil).Once()
+
+	response, err := usecase.ListDocuments(context.Background(), document.RequestListDocument{Prefix: "data/", IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Equal(t, defaultListLimit, response.Limit)
+	require.Len(t, response.Documents, 2)
+	require.Equal(t, "http://localhost:8080/api/v1/download/data/a.txt", response.Documents[0].DocumentUrl)
+	require.Empty(t, response.Documents[1].DocumentUrl)
+}
@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/utils"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRemoteTimeout      = 30 * time.Second
+	defaultRemoteMaxSize      = 100 << 20
+	defaultRemoteMaxRedirects = 3
+)
+
+// WithHTTPClient replace the client fetching remote document, the default one refuse non public address.
+// timeout, size and redirect limits are applied on top of any client
+func WithHTTPClient(client *http.Client) Option {
+	return func(u *usecase) {
+		u.httpClient = client
+	}
+}
+
+func newRemoteClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: utils.RejectPrivateAddress,
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			// a proxy would be dialed instead of the remote host and bypass the address check
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 15 * time.Second,
+		},
+	}
+}
+
+type remoteLimits struct {
+	timeout      time.Duration
+	maxSize      int64
+	maxRedirects int
+}
+
+// uploadUrlLimits read UPLOAD_URL_TIMEOUT (ex: 30s), UPLOAD_URL_MAX_SIZE (bytes) and UPLOAD_URL_MAX_REDIRECTS
+func uploadUrlLimits() remoteLimits {
+	limits := remoteLimits{timeout: defaultRemoteTimeout, maxSize: defaultRemoteMaxSize, maxRedirects: defaultRemoteMaxRedirects}
+	if timeout, err := time.ParseDuration(os.Getenv("UPLOAD_URL_TIMEOUT")); err == nil && timeout > 0 {
+		limits.timeout = timeout
+	}
+	if maxSize, err := strconv.ParseInt(os.Getenv("UPLOAD_URL_MAX_SIZE"), 10, 64); err == nil && maxSize > 0 {
+		limits.maxSize = maxSize
+	}
+	if maxRedirects, err := strconv.Atoi(os.Getenv("UPLOAD_URL_MAX_REDIRECTS")); err == nil && maxRedirects >= 0 {
+		limits.maxRedirects = maxRedirects
+	}
+	return limits
+}
+
+// checkRemoteUrl accept http and https url whose host is in UPLOAD_URL_ALLOWED_HOSTS
+// (ex: "cdn.example.com,.example.org", leading dot match subdomains), every host when empty
+func checkRemoteUrl(remote *url.URL) error {
+	if remote.Scheme != "http" && remote.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %s is not allowed", document.ErrInvalidUrl, remote.Scheme)
+	}
+	host := strings.ToLower(remote.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: host is missing", document.ErrInvalidUrl)
+	}
+
+	allowed := os.Getenv("UPLOAD_URL_ALLOWED_HOSTS")
+	if allowed == "" {
+		return nil
+	}
+	for _, candidate := range strings.Split(allowed, ",") {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if candidate == host || (strings.HasPrefix(candidate, ".") && strings.HasSuffix(host, candidate)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: host %s is not allowed", document.ErrInvalidUrl, host)
+}
+
+// UploadUrl fetch the remote document into a temporary file and store it as UploadFile does
+func (u *usecase) UploadUrl(ctx context.Context, request document.RequestUploadDocumentUrl) (response document.ResponseUploadDocument, err error) {
+
+	remote, err := url.Parse(request.Url)
+	if err != nil {
+		return response, fmt.Errorf("%w: %s", document.ErrInvalidUrl, err.Error())
+	}
+	if err = checkRemoteUrl(remote); err != nil {
+		return
+	}
+	metadata, err := requestMetadata(request.Tags, request.Metadata)
+	if err != nil {
+		return
+	}
+
+	limits := uploadUrlLimits()
+	fetchCtx, cancel := context.WithTimeout(ctx, limits.timeout)
+	defer cancel()
+
+	body, contentType, err := u.fetchRemote(fetchCtx, remote, limits)
+	if err != nil {
+		return
+	}
+	defer os.Remove(body.Name())
+	defer body.Close()
+
+	return u.store(ctx, upload{
+		key:         fmt.Sprintf("%s/%s%s", request.DocumentKey, request.DocumentName, remoteExtension(remote, contentType)),
+		body:        body,
+		contentType: contentType,
+		overwrite:   request.Overwrite,
+		metadata:    metadata,
+		visibility:  request.Visibility,
+
+		expectedSHA256: request.ChecksumSHA256,
+		expectedCRC32C: request.ChecksumCRC32C,
+	})
+}
+
+// fetchRemote stream the remote document to a temporary file, every redirect is checked as the first url
+func (u *usecase) fetchRemote(ctx context.Context, remote *url.URL, limits remoteLimits) (body *os.File, contentType string, err error) {
+	client := *u.httpClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > limits.maxRedirects {
+			return fmt.Errorf("%w: more than %d redirects", document.ErrInvalidUrl, limits.maxRedirects)
+		}
+		return checkRemoteUrl(req.URL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, remote.String(), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %s", document.ErrInvalidUrl, err.Error())
+	}
+	resp, err := client.Do(req)
+	if errors.Is(err, document.ErrInvalidUrl) {
+		return nil, "", err
+	}
+	if errors.Is(err, utils.ErrBlockedAddress) {
+		return nil, "", fmt.Errorf("%w: %s", document.ErrInvalidUrl, err.Error())
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %s", document.ErrRemoteFetch, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", fmt.Errorf("%w: remote respond %s", document.ErrRemoteFetch, resp.Status)
+	}
+	if resp.ContentLength > limits.maxSize {
+		return nil, "", fmt.Errorf("%w: more than %d bytes", document.ErrDocumentTooLarge, limits.maxSize)
+	}
+
+	body, err = os.CreateTemp("", "remote-*")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch remote document: %w", err)
+	}
+	cleanup := func() {
+		body.Close()
+		os.Remove(body.Name())
+	}
+
+	// Content-Length can be absent or wrong, the limit is enforced while reading
+	n, err := io.Copy(body, io.LimitReader(resp.Body, limits.maxSize+1))
+	if err != nil {
+		cleanup()
+		return nil, "", fmt.Errorf("%w: %s", document.ErrRemoteFetch, err.Error())
+	}
+	if n > limits.maxSize {
+		cleanup()
+		return nil, "", fmt.Errorf("%w: more than %d bytes", document.ErrDocumentTooLarge, limits.maxSize)
+	}
+	if err = rewind(body); err != nil {
+		cleanup()
+		return nil, "", err
+	}
+
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+// preferredExtensions is the usual extension of content type having many, mime return them sorted
+var preferredExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"text/plain":      ".txt",
+	"text/html":       ".html",
+	"application/xml": ".xml",
+}
+
+// remoteExtension is the extension of the url path, or the one of content type when the path has none
+func remoteExtension(remote *url.URL, contentType string) string {
+	if ext := path.Ext(remote.Path); ext != "" && len(ext) <= 6 {
+		return strings.ToLower(ext)
+	}
+	return typeExtension(contentType)
+}
+
+// typeExtension is the usual extension of the content type, empty when it has none
+func typeExtension(contentType string) string {
+	if ext, ok := preferredExtensions[mediaType(contentType)]; ok {
+		return ext
+	}
+	if extensions, err := mime.ExtensionsByType(mediaType(contentType)); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"aws-s3-bucket/domain/upload/interfaces/mocks"
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckRemoteUrl(t *testing.T) {
+	parse := func(raw string) *url.URL {
+		parsed, err := url.Parse(raw)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	require.NoError(t, checkRemoteUrl(parse("https://example.com/a.pdf")))
+	require.ErrorIs(t, checkRemoteUrl(parse("file:///etc/passwd")), document.ErrInvalidUrl)
+	require.ErrorIs(t, checkRemoteUrl(parse("gopher://example.com")), document.ErrInvalidUrl)
+	require.ErrorIs(t, checkRemoteUrl(parse("http:///a.pdf")), document.ErrInvalidUrl)
+
+	os.Setenv("UPLOAD_URL_ALLOWED_HOSTS", "cdn.example.com, .example.org")
+	defer os.Unsetenv("UPLOAD_URL_ALLOWED_HOSTS")
+
+	require.NoError(t, checkRemoteUrl(parse("https://CDN.example.com/a.pdf")))
+	require.NoError(t, checkRemoteUrl(parse("https://files.example.org/a.pdf")))
+	require.ErrorIs(t, checkRemoteUrl(parse("https://example.com/a.pdf")), document.ErrInvalidUrl)
+	require.ErrorIs(t, checkRemoteUrl(parse("https://evilexample.org/a.pdf")), document.ErrInvalidUrl)
+}
+
+func Test_RemoteExtension(t *testing.T) {
+	require.Equal(t, ".pdf", remoteExtension(&url.URL{Path: "/files/Invoice.PDF"}, "application/octet-stream"))
+	require.Equal(t, ".jpg", remoteExtension(&url.URL{Path: "/image"}, "image/jpeg"))
+	require.Equal(t, ".png", remoteExtension(&url.URL{Path: "/image"}, "image/png"))
+	require.Equal(t, "", remoteExtension(&url.URL{Path: "/image"}, ""))
+}
+
+func Test_UploadUrl(t *testing.T) {
+	os.Setenv("BUCKET_NAME", "test-bucket")
+	os.Setenv("BASE_URL", "http://localhost:8080")
+	os.Setenv("UPLOAD_URL_MAX_SIZE", "64")
+	os.Setenv("UPLOAD_URL_MAX_REDIRECTS", "1")
+	defer os.Unsetenv("UPLOAD_URL_MAX_SIZE")
+	defer os.Unsetenv("UPLOAD_URL_MAX_REDIRECTS")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/invoice":
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, "invoice content")
+		case "/large":
+			io.WriteString(w, strings.Repeat("a", 100))
+		case "/redirect":
+			http.Redirect(w, r, "/invoice", http.StatusFound)
+		case "/redirect-twice":
+			http.Redirect(w, r, "/redirect", http.StatusFound)
+		case "/redirect-file":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	mockS3Client := mocks.NewS3Interface(t)
+	usecase := NewUsecase(mockS3Client, WithHTTPClient(server.Client()))
+
+	type expected struct {
+		err error
+		key string
+	}
+	tests := []struct {
+		name     string
+		path     string
+		prepare  func()
+		expected expected
+	}{
+		{
+			name: "Success",
+			path: "/invoice",
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+					content, _ := io.ReadAll(input.Body)
+					return aws.ToString(input.Key) == "folder/invoice.txt" && string(content) == "invoice content"
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{key: "folder/invoice.txt"},
+		},
+		{
+			name: "Redirect",
+			path: "/redirect",
+			prepare: func() {
+				mockS3Client.On("PutObject", mock.Anything, matchKey("folder/invoice.txt")).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{key: "folder/invoice.txt"},
+		},
+		{
+			name:     "TooManyRedirects",
+			path:     "/redirect-twice",
+			prepare:  func() {},
+			expected: expected{err: document.ErrInvalidUrl},
+		},
+		{
+			name:     "RedirectToFile",
+			path:     "/redirect-file",
+			prepare:  func() {},
+			expected: expected{err: document.ErrInvalidUrl},
+		},
+		{
+			name:     "TooLarge",
+			path:     "/large",
+			prepare:  func() {},
+			expected: expected{err: document.ErrDocumentTooLarge},
+		},
+		{
+			name:     "NotFound",
+			path:     "/missing",
+			prepare:  func() {},
+			expected: expected{err: document.ErrRemoteFetch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			response, err := usecase.UploadUrl(context.Background(), document.RequestUploadDocumentUrl{
+				DocumentKey:  "folder",
+				DocumentName: "invoice",
+				Url:          server.URL + tt.path,
+			})
+			if tt.expected.err != nil {
+				require.ErrorIs(t, err, tt.expected.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected.key, response.Key)
+		})
+	}
+}
+
+func Test_UploadUrl_PrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "internal")
+	}))
+	defer server.Close()
+
+	// default client refuse loopback address of the test server
+	usecase, _ := initUseCaseUnitTest(t)
+	_, err := usecase.UploadUrl(context.Background(), document.RequestUploadDocumentUrl{
+		DocumentKey:  "folder",
+		DocumentName: "internal",
+		Url:          server.URL,
+	})
+
+	require.ErrorIs(t, err, document.ErrInvalidUrl)
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"aws-s3-bucket/shared/constant"
+	"aws-s3-bucket/shared/utils"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	renditionPrefix = "_renditions/"
+
+	defaultImageQuality      = 80
+	defaultImageMaxDimension = 2048
+)
+
+// wantRendition tell whether the download ask for a transformed image instead of the original
+func wantRendition(request document.RequestDownloadDocument) bool {
+	return request.Width != 0 || request.Height != 0 || request.Format != "" || request.Fit != "" || request.Quality != 0
+}
+
+// imageTransform validate the requested transformation and fill the defaults
+func imageTransform(request document.RequestDownloadDocument) (transform utils.ImageTransform, err error) {
+	transform = utils.ImageTransform{
+		Width:   request.Width,
+		Height:  request.Height,
+		Fit:     request.Fit,
+		Format:  request.Format,
+		Quality: request.Quality,
+	}
+
+	if transform.Width < 0 || transform.Height < 0 {
+		return transform, fmt.Errorf("%w: width and height must be positive", document.ErrInvalidTransformation)
+	}
+	if !imageSizeAllowed(transform.Width, transform.Height) {
+		return transform, fmt.Errorf("%w: size %dx%d is not allowed", document.ErrInvalidTransformation, transform.Width, transform.Height)
+	}
+
+	switch transform.Fit {
+	case "":
+		transform.Fit = constant.IMAGE_FIT_CONTAIN
+	case constant.IMAGE_FIT_CONTAIN, constant.IMAGE_FIT_COVER, constant.IMAGE_FIT_FILL:
+	default:
+		return transform, fmt.Errorf("%w: fit must be contain, cover or fill", document.ErrInvalidTransformation)
+	}
+
+	switch transform.Format {
+	case "jpg":
+		transform.Format = constant.IMAGE_FORMAT_JPEG
+	case "", constant.IMAGE_FORMAT_JPEG, constant.IMAGE_FORMAT_PNG, constant.IMAGE_FORMAT_WEBP:
+	default:
+		return transform, fmt.Errorf("%w: format must be jpeg, png or webp", document.ErrInvalidTransformation)
+	}
+
+	switch {
+	case transform.Quality == 0:
+		transform.Quality = defaultImageQuality
+	case transform.Quality < 1 || transform.Quality > 100:
+		return transform, fmt.Errorf("%w: quality must be between 1 and 100", document.ErrInvalidTransformation)
+	}
+
+	return transform, nil
+}
+
+// imageSizeAllowed check the size against IMAGE_ALLOWED_SIZES (ex: "200x200,800x0", 0 mean auto),
+// when it is empty any size up to IMAGE_MAX_DIMENSION is accepted
+func imageSizeAllowed(width, height int) bool {
+	if allowed := os.Getenv("IMAGE_ALLOWED_SIZES"); allowed != "" {
+		size := fmt.Sprintf("%dx%d", width, height)
+		for _, candidate := range strings.Split(allowed, ",") {
+			if strings.TrimSpace(candidate) == size {
+				return true
+			}
+		}
+		// format conversion only keep the original size
+		return width == 0 && height == 0
+	}
+
+	maxDimension, err := strconv.Atoi(os.Getenv("IMAGE_MAX_DIMENSION"))
+	if err != nil || maxDimension <= 0 {
+		maxDimension = defaultImageMaxDimension
+	}
+	return width <= maxDimension && height <= maxDimension
+}
+
+// renditionKey is deterministic key of cached rendition, the source etag is part of it
+// so overwriting the original never serve a stale rendition
+func renditionKey(key, etag string, transform utils.ImageTransform) string {
+	format := transform.Format
+	if format == "" {
+		format = "orig"
+	}
+	return fmt.Sprintf("%s%s/%s/%dx%d_%s_q%d.%s", renditionPrefix, key, strings.Trim(etag, `"`),
+		transform.Width, transform.Height, transform.Fit, transform.Quality, format)
+}
+
+// downloadRendition serve the cached rendition or build it from the original and cache it back to S3
+func (u *usecase) downloadRendition(ctx context.Context, request document.RequestDownloadDocument, signed bool) (response *s3.GetObjectOutput, err error) {
+
+	transform, err := imageTransform(request)
+	if err != nil {
+		return
+	}
+
+	bucketName := os.Getenv("BUCKET_NAME")
+	headInput := &s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(request.Key),
+	}
+	if request.VersionId != "" {
+		headInput.VersionId = aws.String(request.VersionId)
+	}
+	head, err := u.s3Client.HeadObject(ctx, headInput)
+	if isNotFound(err) {
+		err = document.ErrDocumentNotFound
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to download file: %w", err)
+		return
+	}
+	// the original decide, the cached rendition may predate its visibility
+	if err = checkVisibility(head.Metadata, signed); err != nil {
+		return
+	}
+
+	cacheKey := renditionKey(request.Key, aws.ToString(head.ETag), transform)
+	response, err = u.cachedGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(cacheKey),
+	})
+	if err == nil {
+		return withInheritedMetadata(response, head.Metadata), nil
+	}
+	if !isNotFound(err) {
+		err = fmt.Errorf("failed to download rendition: %w", err)
+		return
+	}
+
+	// the signed link of the rendition is valid for its original as well
+	original, err := u.DownloadFile(ctx, document.RequestDownloadDocument{Key: request.Key, Id: request.Id, VersionId: request.VersionId, Link: request.Link})
+	if err != nil {
+		return
+	}
+	defer original.Body.Close()
+
+	if !strings.HasPrefix(aws.ToString(original.ContentType), "image/") {
+		err = fmt.Errorf("%w: document is not an image", document.ErrInvalidTransformation)
+		return
+	}
+
+	data, contentType, err := utils.TransformImage(original.Body, transform)
+	if err != nil {
+		err = fmt.Errorf("failed to transform image: %w", err)
+		return
+	}
+
+	_, err = u.s3Client.PutObject(ctx, withDerivedVisibility(&s3.PutObjectInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(cacheKey),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	}, head.Metadata))
+	if err != nil {
+		err = fmt.Errorf("failed to cache rendition: %w", err)
+		return
+	}
+
+	return withInheritedMetadata(&s3.GetObjectOutput{
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(int64(len(data))),
+	}, head.Metadata), nil
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ImageSizeAllowed(t *testing.T) {
+	os.Setenv("IMAGE_ALLOWED_SIZES", "200x200, 800x0")
+	defer os.Unsetenv("IMAGE_ALLOWED_SIZES")
+
+	require.True(t, imageSizeAllowed(200, 200))
+	require.True(t, imageSizeAllowed(800, 0))
+	require.True(t, imageSizeAllowed(0, 0))
+	require.False(t, imageSizeAllowed(201, 200))
+
+	os.Setenv("IMAGE_ALLOWED_SIZES", "")
+	require.True(t, imageSizeAllowed(2048, 10))
+	require.False(t, imageSizeAllowed(2049, 10))
+}
+
+func Test_RenditionKey(t *testing.T) {
+	transform, err := imageTransform(document.RequestDownloadDocument{Width: 200, Format: "jpg"})
+
+	require.NoError(t, err)
+	require.Equal(t, "_renditions/data/example.png/abc/200x0_contain_q80.jpeg", renditionKey("data/example.png", `"abc"`, transform))
+}
+
+func Test_DownloadFile_Rendition(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	var source bytes.Buffer
+	require.NoError(t, png.Encode(&source, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+
+	cacheKey := "_renditions/data/example.png/abc/20x0_contain_q80.orig"
+	getKey := func(key string) interface{} {
+		return mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+			return aws.ToString(input.Key) == key
+		})
+	}
+
+	type expected struct {
+		err         error
+		contentType string
+		body        bool
+	}
+	tests := []struct {
+		name     string
+		request  document.RequestDownloadDocument
+		prepare  func()
+		expected expected
+	}{
+		{
+			name:    "InvalidFit",
+			request: document.RequestDownloadDocument{Key: "data/example.png", Width: 20, Fit: "stretch"},
+			prepare: func() {},
+			expected: expected{
+				err: fmt.Errorf("%w: fit must be contain, cover or fill", document.ErrInvalidTransformation),
+			},
+		},
+		{
+			name:    "NotFound",
+			request: document.RequestDownloadDocument{Key: "data/example.png", Width: 20},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, mock.Anything).Return(nil, &types.NotFound{}).Once()
+			},
+			expected: expected{
+				err: document.ErrDocumentNotFound,
+			},
+		},
+		{
+			name:    "Cached",
+			request: document.RequestDownloadDocument{Key: "data/example.png", Width: 20},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, mock.Anything).Return(&s3.HeadObjectOutput{ETag: aws.String(`"abc"`)}, nil).Once()
+				mockS3Client.On("GetObject", mock.Anything, getKey(cacheKey)).Return(&s3.GetObjectOutput{
+					Body:        io.NopCloser(bytes.NewReader([]byte("cached"))),
+					ContentType: aws.String("image/png"),
+				}, nil).Once()
+			},
+			expected: expected{
+				contentType: "image/png",
+				body:        true,
+			},
+		},
+		{
+			name:    "TransformAndCache",
+			request: document.RequestDownloadDocument{Key: "data/example.png", Width: 20},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, mock.Anything).Return(&s3.HeadObjectOutput{ETag: aws.String(`"abc"`)}, nil).Once()
+				mockS3Client.On("GetObject", mock.Anything, getKey(cacheKey)).Return(nil, &types.NoSuchKey{}).Once()
+				mockS3Client.On("GetObject", mock.Anything, getKey("data/example.png")).Return(&s3.GetObjectOutput{
+					Body:        io.NopCloser(bytes.NewReader(source.Bytes())),
+					ContentType: aws.String("image/png"),
+				}, nil).Once()
+				mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+					return aws.ToString(input.Key) == cacheKey && aws.ToString(input.ContentType) == "image/png"
+				})).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				contentType: "image/png",
+				body:        true,
+			},
+		},
+		{
+			name:    "NotAnImage",
+			request: document.RequestDownloadDocument{Key: "data/example.png", Width: 20},
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, mock.Anything).Return(&s3.HeadObjectOutput{ETag: aws.String(`"abc"`)}, nil).Once()
+				mockS3Client.On("GetObject", mock.Anything, getKey(cacheKey)).Return(nil, &types.NoSuchKey{}).Once()
+				mockS3Client.On("GetObject", mock.Anything, getKey("data/example.png")).Return(&s3.GetObjectOutput{
+					Body:        io.NopCloser(bytes.NewReader([]byte("text"))),
+					ContentType: aws.String("text/plain"),
+				}, nil).Once()
+			},
+			expected: expected{
+				err: fmt.Errorf("%w: document is not an image", document.ErrInvalidTransformation),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			response, err := usecase.DownloadFile(nil, tt.request)
+
+			require.Equal(t, tt.expected.err, err)
+			if tt.expected.body {
+				require.Equal(t, tt.expected.contentType, aws.ToString(response.ContentType))
+				require.NotNil(t, response.Body)
+			}
+		})
+	}
+}
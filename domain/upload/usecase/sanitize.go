@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"aws-s3-bucket/shared/utils"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// sanitizeEnabled tell whether metadata of image stored under key must be stripped,
+// configured by IMAGE_SANITIZE_PREFIXES (ex: "avatars/,photos/" or "*" for every key)
+func sanitizeEnabled(key string) bool {
+	for _, prefix := range strings.Split(os.Getenv("IMAGE_SANITIZE_PREFIXES"), ",") {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "*" || (prefix != "" && strings.HasPrefix(key, prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeUpload replace the body with the image without EXIF, XMP and IPTC, so the original bytes never reach S3.
+// the checksum sent by client is about the original bytes, it is verified here and not anymore after
+func sanitizeUpload(doc upload) (upload, error) {
+	if !sanitizeEnabled(doc.key) {
+		return doc, nil
+	}
+
+	original, err := io.ReadAll(doc.body)
+	if err != nil {
+		return doc, fmt.Errorf("failed to read document: %w", err)
+	}
+
+	if doc, err = verifyClientChecksum(doc, original); err != nil {
+		return doc, err
+	}
+
+	sanitized, err := utils.StripImageMetadata(original)
+	if err != nil {
+		return doc, fmt.Errorf("failed to sanitize image: %w", err)
+	}
+
+	doc.body = bytes.NewReader(sanitized)
+	return doc, nil
+}
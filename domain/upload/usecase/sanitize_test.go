@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"image"
+	"image/jpeg"
+	"io"
+	"os"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SanitizeEnabled(t *testing.T) {
+	os.Setenv("IMAGE_SANITIZE_PREFIXES", "avatars/, photos/")
+	defer os.Unsetenv("IMAGE_SANITIZE_PREFIXES")
+
+	require.True(t, sanitizeEnabled("avatars/me.jpg"))
+	require.True(t, sanitizeEnabled("photos/a.png"))
+	require.False(t, sanitizeEnabled("invoices/a.pdf"))
+
+	os.Setenv("IMAGE_SANITIZE_PREFIXES", "*")
+	require.True(t, sanitizeEnabled("invoices/a.pdf"))
+}
+
+func Test_UploadBase64_Sanitize(t *testing.T) {
+	os.Setenv("IMAGE_SANITIZE_PREFIXES", "avatars/")
+	defer os.Unsetenv("IMAGE_SANITIZE_PREFIXES")
+
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil))
+	exif := []byte("\xFF\xE1\x00\x15Exif\x00\x00II*\x00\x08\x00\x00\x00\x00\x00GPS")
+	original := append(append([]byte{0xFF, 0xD8}, exif...), encoded.Bytes()[2:]...)
+	originalSHA256 := sha256.Sum256(original)
+
+	var stored []byte
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		stored, _ = io.ReadAll(input.Body)
+		return true
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+		DocumentKey:    "avatars",
+		DocumentName:   "me",
+		DocumentBase64: "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(original),
+		ChecksumSHA256: base64.StdEncoding.EncodeToString(originalSHA256[:]),
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, encoded.Bytes(), stored)
+	require.Equal(t, int64(encoded.Len()), response.Size)
+
+	// checksum is verified against the bytes sent by client
+	_, err = usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+		DocumentKey:    "avatars",
+		DocumentName:   "me",
+		DocumentBase64: "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(original),
+		ChecksumSHA256: base64.StdEncoding.EncodeToString(make([]byte, sha256.Size)),
+	})
+
+	require.Equal(t, document.ErrChecksumMismatch, err)
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// metaTags hold the comma separated tags of the document
+	metaTags = "tags"
+
+	defaultSearchLimit = 50
+)
+
+// reservedMetadata is written by the service itself, a client cannot set them nor search them
+var reservedMetadata = map[string]bool{
+	metaTags:             true,
+	metaDedupeBlob:       true,
+	metaDedupeSHA256:     true,
+	metaDedupeSize:       true,
+	metaUncompressedSize: true,
+	metaOriginalSize:     true,
+	metaStoredSize:       true,
+	metaPdfPages:         true,
+	metaPdfTitle:         true,
+	metaPdfAuthor:        true,
+	metaPdfEncrypted:     true,
+	metaDocumentId:       true,
+	metaVisibility:       true,
+	metaOriginalFilename: true,
+}
+
+// metadataName is a valid header name once prefixed by x-amz-meta-, S3 store it lowercase
+var metadataName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// requestMetadata build the object metadata of the tags and custom metadata of an upload,
+// non ASCII value is RFC 2047 encoded as the header only allow ASCII
+func requestMetadata(tags []string, custom map[string]string) (map[string]string, error) {
+	if len(tags) == 0 && len(custom) == 0 {
+		return nil, nil
+	}
+
+	metadata := make(map[string]string, len(custom)+1)
+	for name, value := range custom {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !metadataName.MatchString(name) {
+			return nil, fmt.Errorf("%w: %q is not a valid name", document.ErrInvalidMetadata, name)
+		}
+		if reservedMetadata[name] {
+			return nil, fmt.Errorf("%w: %q is reserved", document.ErrInvalidMetadata, name)
+		}
+		metadata[name] = mime.QEncoding.Encode("utf-8", value)
+	}
+
+	if tags = normalizeTags(tags); len(tags) > 0 {
+		metadata[metaTags] = mime.QEncoding.Encode("utf-8", strings.Join(tags, ","))
+	}
+	return metadata, nil
+}
+
+// normalizeTags trim and deduplicate the tags, in a stable order
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// userMetadata split the object metadata in tags and custom metadata, the ones of the service are left out
+func userMetadata(metadata map[string]string) (tags []string, custom map[string]string) {
+	for name, value := range decodeMetadata(metadata) {
+		name = strings.ToLower(name)
+		if name == metaTags {
+			tags = normalizeTags(strings.Split(value, ","))
+			continue
+		}
+		if reservedMetadata[name] {
+			continue
+		}
+		if custom == nil {
+			custom = map[string]string{}
+		}
+		custom[name] = value
+	}
+	return
+}
+
+// SearchDocuments search the documents in the metadata database, the bucket is never scanned
+func (u *usecase) SearchDocuments(ctx context.Context, request document.RequestSearchDocument) (response document.ResponseSearchDocument, err error) {
+	if u.repository == nil {
+		return response, document.ErrDatabaseNotConfigured
+	}
+
+	bucket, err := allowedBucket(request.Bucket)
+	if err != nil {
+		return
+	}
+	filter := document.SearchFilter{
+		Bucket:      bucket,
+		Name:        request.Name,
+		ContentType: request.ContentType,
+		MinSize:     request.MinSize,
+		MaxSize:     request.MaxSize,
+		Tags:        normalizeTags(request.Tags),
+		// search is not authenticated, private documents are left out
+		ExcludeVisibility: visibilityPrivate,
+		Sort:              request.Sort,
+		Limit:             request.Limit,
+		Cursor:            request.Cursor,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSearchLimit
+	}
+	if filter.MaxSize > 0 && filter.MinSize > filter.MaxSize {
+		return response, fmt.Errorf("%w: min_size is over max_size", document.ErrInvalidSearch)
+	}
+	if filter.UploadedAfter, err = searchDate(request.UploadedAfter); err != nil {
+		return
+	}
+	if filter.UploadedBefore, err = searchDate(request.UploadedBefore); err != nil {
+		return
+	}
+	for _, pair := range request.Metadata {
+		name, value, ok := strings.Cut(pair, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || !metadataName.MatchString(name) {
+			return response, fmt.Errorf("%w: metadata %q is not name:value", document.ErrInvalidSearch, pair)
+		}
+		if filter.Metadata == nil {
+			filter.Metadata = map[string]string{}
+		}
+		filter.Metadata[name] = value
+	}
+
+	records, next, err := u.repository.SearchDocuments(ctx, filter)
+	if err != nil {
+		return response, fmt.Errorf("failed to search documents: %w", err)
+	}
+
+	response = document.ResponseSearchDocument{Documents: make([]document.ResponseDocumentRecord, 0, len(records)), NextCursor: next}
+	for _, record := range records {
+		item := document.ResponseDocumentRecord{Record: record}
+		if record.Bucket == os.Getenv("BUCKET_NAME") {
+			item.DocumentUrl = documentUrl(record.Key, record.DocumentId)
+		}
+		response.Documents = append(response.Documents, item)
+	}
+	return response, nil
+}
+
+func searchDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is not a RFC 3339 date time", document.ErrInvalidSearch, value)
+	}
+	return &date, nil
+}
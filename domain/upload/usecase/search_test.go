@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aws-s3-bucket/domain/upload/interfaces/mocks"
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RequestMetadata(t *testing.T) {
+	metadata, err := requestMetadata(nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, metadata)
+
+	metadata, err = requestMetadata([]string{"invoice", " finance ", "invoice", ""}, map[string]string{"Owner": "Zoë", "team": "sales"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"owner": "=?utf-8?q?Zo=C3=AB?=", "team": "sales", metaTags: "finance,invoice"}, metadata)
+
+	tags, custom := userMetadata(withMetadata(metadata, map[string]string{metaDedupeBlob: "blobs/sum"}))
+	require.Equal(t, []string{"finance", "invoice"}, tags)
+	require.Equal(t, map[string]string{"owner": "Zoë", "team": "sales"}, custom)
+
+	_, err = requestMetadata(nil, map[string]string{metaDedupeBlob: "blobs/other"})
+	require.ErrorIs(t, err, document.ErrInvalidMetadata)
+	_, err = requestMetadata(nil, map[string]string{"not valid": "x"})
+	require.ErrorIs(t, err, document.ErrInvalidMetadata)
+}
+
+func Test_UploadBase64_Tags(t *testing.T) {
+	usecase, mockS3Client, mockRepository := initRecordUnitTest(t)
+
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return input.Metadata[metaTags] == "invoice" && input.Metadata["owner"] == "finance"
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+	mockRepository.On("SaveDocument", mock.Anything, mock.MatchedBy(func(record document.Record) bool {
+		return len(record.Tags) == 1 && record.Tags[0] == "invoice" && record.Metadata["owner"] == "finance"
+	})).Return(nil).Once()
+
+	_, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+		Tags:           []string{"invoice"},
+		Metadata:       map[string]string{"owner": "finance"},
+	})
+	require.NoError(t, err)
+
+	_, err = usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+		Metadata:       map[string]string{metaTags: "forged"},
+	})
+	require.ErrorIs(t, err, document.ErrInvalidMetadata)
+}
+
+func Test_SearchDocuments(t *testing.T) {
+	usecase, _, mockRepository := initRecordUnitTest(t)
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := NewUsecase(mocks.NewS3Interface(t)).SearchDocuments(context.Background(), document.RequestSearchDocument{})
+	require.ErrorIs(t, err, document.ErrDatabaseNotConfigured)
+
+	tests := []struct {
+		name    string
+		request document.RequestSearchDocument
+		err     error
+	}{
+		{name: "BucketNotAllowed", request: document.RequestSearchDocument{Bucket: "unknown-bucket"}, err: document.ErrBucketNotAllowed},
+		{name: "SizeRange", request: document.RequestSearchDocument{MinSize: 10, MaxSize: 5}, err: document.ErrInvalidSearch},
+		{name: "Date", request: document.RequestSearchDocument{UploadedAfter: "yesterday"}, err: document.ErrInvalidSearch},
+		{name: "Metadata", request: document.RequestSearchDocument{Metadata: []string{"owner"}}, err: document.ErrInvalidSearch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := usecase.SearchDocuments(context.Background(), tt.request)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	mockRepository.On("SearchDocuments", mock.Anything, document.SearchFilter{
+		Bucket:        "test-bucket",
+		Name:          "invoice",
+		UploadedAfter: &after,
+		Tags:          []string{"finance", "invoice"},
+		Metadata:      map[string]string{"owner": "bob:smith"},
+		// private documents are never searched
+		ExcludeVisibility: visibilityPrivate,
+		Limit:             defaultSearchLimit,
+	}).Return([]document.Record{{Key: "data/invoice.pdf", Bucket: "test-bucket"}}, "next", nil).Once()
+
+	response, err := usecase.SearchDocuments(context.Background(), document.RequestSearchDocument{
+		Name:          "invoice",
+		UploadedAfter: "2024-01-01T00:00:00Z",
+		Tags:          []string{"invoice", "finance"},
+		Metadata:      []string{"Owner:bob:smith"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "next", response.NextCursor)
+	require.Equal(t, "http://localhost:8080/api/v1/download/data/invoice.pdf", response.Documents[0].DocumentUrl)
+}
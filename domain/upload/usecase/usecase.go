@@ -5,80 +5,449 @@ import (
 	"aws-s3-bucket/models/document"
 	"aws-s3-bucket/shared/utils"
 	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 type usecase struct {
-	s3Client interfaces.S3Interface
+	s3Client   interfaces.S3Interface
+	presigner  interfaces.PresignInterface
+	httpClient *http.Client
+	repository interfaces.RepositoryInterface
+	cache      interfaces.CacheInterface
 }
 
-func NewUsecase(s3Client interfaces.S3Interface) interfaces.UsecaseInterface {
-	return &usecase{s3Client: s3Client}
+type Option func(*usecase)
+
+// WithPresigner enable short-lived signed url in upload response
+func WithPresigner(presigner interfaces.PresignInterface) Option {
+	return func(u *usecase) {
+		u.presigner = presigner
+	}
+}
+
+func NewUsecase(s3Client interfaces.S3Interface, opts ...Option) interfaces.UsecaseInterface {
+	u := &usecase{s3Client: s3Client, httpClient: newRemoteClient()}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// upload is document that ready to be stored, shared by every upload way
+type upload struct {
+	key         string
+	body        io.ReadSeeker
+	contentType string
+	overwrite   string
+	metadata    map[string]string
+	visibility  string
+	// contentEncoding is set when body is compressed
+	contentEncoding string
+
+	expectedSHA256 string
+	expectedCRC32C string
 }
 
 func (u *usecase) UploadBase64(ctx context.Context, request document.RequestUploadDocumentBase64) (response document.ResponseUploadDocument, err error) {
 
-	_, contentType, formatType, decodedBytes, err := utils.ExtractBase64(request.DocumentBase64)
+	dataUri, payload, err := utils.ParseBase64(request.DocumentBase64)
+	if err != nil {
+		return response, base64Error(err)
+	}
+	contentType, err := base64ContentType(request.ContentType, dataUri)
+	if err != nil {
+		return
+	}
+	metadata, err := requestMetadata(request.Tags, request.Metadata)
 	if err != nil {
 		return
 	}
 
-	bucketName := os.Getenv("BUCKET_NAME")
-	key := fmt.Sprintf("%s/%s.%s", request.DocumentKey, request.DocumentName, formatType)
-
-	_, err = u.s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(bucketName),
-		Key:         aws.String(key),
-		Body:        strings.NewReader(string(decodedBytes)),
-		ContentType: aws.String(contentType),
-		// ACL:         "public-read", //if wanna public use public read
-	})
+	body, cleanup, err := decodeBase64(payload)
 	if err != nil {
-		err = fmt.Errorf("failed to upload file: %w", err)
 		return
 	}
+	defer cleanup()
+	// the extension is part of the key, the content type cannot wait for store to sniff it
+	if contentType == "" {
+		if contentType, err = detectContentType(body, ""); err != nil {
+			return
+		}
+	}
+
+	return u.store(ctx, upload{
+		key:         fmt.Sprintf("%s/%s%s", request.DocumentKey, request.DocumentName, base64Extension(contentType, dataUri)),
+		body:        body,
+		contentType: contentType,
+		overwrite:   request.Overwrite,
+		metadata:    metadata,
+		visibility:  request.Visibility,
 
-	return document.ResponseUploadDocument{DocumentUrl: fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), key)}, nil
+		expectedSHA256: request.ChecksumSHA256,
+		expectedCRC32C: request.ChecksumCRC32C,
+	})
 }
 
 func (u *usecase) UploadFile(ctx context.Context, request document.RequestUploadDocumentFile, files *multipart.FileHeader) (response document.ResponseUploadDocument, err error) {
 
+	metadata, err := requestMetadata(request.Tags, request.Metadata)
+	if err != nil {
+		return
+	}
+
 	filed, err := files.Open()
+	if err != nil {
+		err = fmt.Errorf("failed to open file: %w", err)
+		return
+	}
+	defer filed.Close()
 
-	bucketName := os.Getenv("BUCKET_NAME")
+	return u.store(ctx, upload{
+		key:         fmt.Sprintf("%s/%s%s", request.DocumentKey, request.DocumentName, filepath.Ext(files.Filename)),
+		body:        filed,
+		contentType: files.Header.Get("Content-Type"),
+		overwrite:   request.Overwrite,
+		metadata:    originalFilenameMetadata(metadata, files.Filename),
+		visibility:  request.Visibility,
 
-	key := fmt.Sprintf("%s/%s%s", request.DocumentKey, request.DocumentName, filepath.Ext(files.Filename))
-	_, err = u.s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(bucketName),
-		Key:         aws.String(key),
-		Body:        filed,
-		ContentType: aws.String(files.Header.Get("Content-Type")),
-		// ACL:         "public-read", //if wanna public use public read
+		expectedSHA256: request.ChecksumSHA256,
+		expectedCRC32C: request.ChecksumCRC32C,
 	})
+}
+
+func (u *usecase) store(ctx context.Context, doc upload) (response document.ResponseUploadDocument, err error) {
+
+	// blobs, refs, ids and renditions are shared by other documents, a client must not replace them
+	if isInternalKey(doc.key) {
+		return response, fmt.Errorf("%w: %s", document.ErrReservedKey, doc.key)
+	}
+	if doc, err = sanitizeUpload(doc); err != nil {
+		return
+	}
+	if doc, err = applyVisibility(doc); err != nil {
+		return
+	}
+	originalKey, originalContentType := doc.key, doc.contentType
+	doc, original, err := optimizeUpload(doc)
+	if err != nil {
+		return
+	}
+
+	size, err := contentLength(doc.body)
+	if err != nil {
+		return
+	}
+	if doc.contentType, err = detectContentType(doc.body, doc.contentType); err != nil {
+		return
+	}
+
+	pdfInfo, err := inspectPdf(doc)
+	if err != nil {
+		return
+	}
+	if pdfInfo != nil {
+		doc.metadata = withMetadata(doc.metadata, pdfMetadata(*pdfInfo))
+	}
+
+	if doc, err = compressUpload(doc); err != nil {
+		return
+	}
+
+	checksum, err := computeChecksum(doc)
+	if err != nil {
+		return
+	}
+
+	bucketName := os.Getenv("BUCKET_NAME")
+	policy, err := overwritePolicy(doc.overwrite, doc.key)
+	if err != nil {
+		return
+	}
+	documentId := newDocumentId()
+	if documentId != "" {
+		doc.metadata = withMetadata(doc.metadata, map[string]string{metaDocumentId: documentId})
+	}
+
+	var key string
+	var output *s3.PutObjectOutput
+	if dedupeEnabled() {
+		output, key, err = u.putDeduplicated(ctx, bucketName, doc, checksum, policy)
+	} else {
+		input := &s3.PutObjectInput{
+			Bucket:      aws.String(bucketName),
+			Key:         aws.String(doc.key),
+			Body:        doc.body,
+			ContentType: aws.String(doc.contentType),
+			Metadata:    doc.metadata,
+			ACL:         visibilityACL(doc.metadata),
+		}
+		if cacheControl := visibilityCacheControl(doc.metadata); cacheControl != "" {
+			input.CacheControl = aws.String(cacheControl)
+		}
+		if doc.contentEncoding != "" {
+			input.ContentEncoding = aws.String(doc.contentEncoding)
+		}
+		output, key, err = u.putObject(ctx, withChecksum(input, checksum), policy)
+	}
+	if errors.Is(err, document.ErrDocumentAlreadyExists) {
+		return
+	}
 	if err != nil {
 		err = fmt.Errorf("failed to upload file: %w", err)
 		return
 	}
+	u.invalidateCache(bucketName, key)
+
+	if documentId != "" {
+		if err = u.putDocumentId(ctx, bucketName, documentId, key); err != nil {
+			return
+		}
+	}
 
-	return document.ResponseUploadDocument{DocumentUrl: fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), key)}, nil
+	response = document.ResponseUploadDocument{
+		DocumentUrl:     documentUrl(key, documentId),
+		Id:              documentId,
+		Key:             key,
+		Bucket:          bucketName,
+		Size:            size,
+		ContentType:     doc.contentType,
+		ContentEncoding: doc.contentEncoding,
+		Visibility:      doc.visibility,
+		PublicUrl:       publicUrl(key, doc.visibility),
+	}
+	if output != nil {
+		response.ETag = aws.ToString(output.ETag)
+		response.VersionId = aws.ToString(output.VersionId)
+	}
+	if checksum.SHA256 != nil {
+		response.ChecksumSHA256 = base64.StdEncoding.EncodeToString(checksum.SHA256)
+	}
+	if checksum.CRC32C != nil {
+		response.ChecksumCRC32C = base64.StdEncoding.EncodeToString(checksum.CRC32C)
+	}
+
+	if original != nil {
+		if err = u.storeOriginal(ctx, bucketName, key, originalKey, original, originalContentType); err != nil {
+			return document.ResponseUploadDocument{}, err
+		}
+	}
+
+	if response.Renditions, err = u.storeRenditions(ctx, bucketName, key, documentId, doc); err != nil {
+		return document.ResponseUploadDocument{}, err
+	}
+	if pdfInfo != nil && !pdfInfo.PasswordProtected && pdfPreviewEnabled() {
+		preview, err := u.storePdfPreview(ctx, bucketName, key, documentId, doc)
+		if err != nil {
+			return document.ResponseUploadDocument{}, err
+		}
+		if preview != "" && response.Renditions == nil {
+			response.Renditions = map[string]string{}
+		}
+		if preview != "" {
+			response.Renditions[pdfPreviewName] = preview
+		}
+	}
+
+	// public document is downloaded without signature, pointer of deduplicated document is not the content,
+	// sign the blob instead
+	if doc.visibility != visibilityPublic {
+		objectKey := key
+		if dedupeEnabled() {
+			objectKey = blobPrefix + hex.EncodeToString(checksum.SHA256)
+		}
+		response.SignedUrl, response.SignedUrlExpiresAt, err = u.signUrl(ctx, bucketName, objectKey)
+		if err != nil {
+			return document.ResponseUploadDocument{}, err
+		}
+	}
+
+	if err = u.saveRecord(ctx, response, doc.metadata); err != nil {
+		return document.ResponseUploadDocument{}, err
+	}
+
+	return withoutKey(response), nil
+}
+
+func contentLength(body io.Seeker) (size int64, err error) {
+	if size, err = body.Seek(0, io.SeekEnd); err != nil {
+		return 0, fmt.Errorf("failed to read document size: %w", err)
+	}
+	if _, err = body.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to read document size: %w", err)
+	}
+	return
+}
+
+// detectContentType sniff the content when client does not send a meaningful content type
+func detectContentType(body io.ReadSeeker, declared string) (string, error) {
+	if declared != "" && declared != "application/octet-stream" {
+		return declared, nil
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("failed to detect content type: %w", err)
+	}
+	if err = rewind(body); err != nil {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
 }
 
-func (u *usecase) DownloadFile(ctx context.Context, fileIdentifier string) (response *s3.GetObjectOutput, err error) {
+func (u *usecase) DownloadFile(ctx context.Context, request document.RequestDownloadDocument) (response *s3.GetObjectOutput, err error) {
+
+	signed, err := verifyLink(request, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !signed && signedLinksRequired() {
+		return nil, fmt.Errorf("%w: signed link is required", document.ErrInvalidSignature)
+	}
+	// a signed link was issued for this key, it is served even when download by key is disabled
+	if request.Id == "" && (isInternalKey(request.Key) || !signed && !downloadByKeyEnabled()) {
+		return nil, document.ErrDocumentNotFound
+	}
+	if request.Rendition != "" {
+		if request.Key, err = derivedKey(request.Key, request.Rendition); err != nil {
+			return nil, err
+		}
+	}
+
+	if wantRendition(request) {
+		if response, err = u.downloadRendition(ctx, request, signed); err != nil {
+			return nil, err
+		}
+		return withFilename(withCacheControl(response, request.Key)), nil
+	}
 
-	response, err = u.s3Client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(os.Getenv("BUCKET_NAME")),
+	input := &s3.GetObjectInput{
+		Bucket:       aws.String(os.Getenv("BUCKET_NAME")),
+		Key:          aws.String(request.Key),
+		ChecksumMode: types.ChecksumModeEnabled,
+	}
+	if request.VersionId != "" {
+		input.VersionId = aws.String(request.VersionId)
+	}
+	if request.Range != "" {
+		input.Range = aws.String(request.Range)
+	}
+
+	response, err = u.getObject(ctx, input)
+	if err != nil || response == nil {
+		return
+	}
+	if err = checkVisibility(response.Metadata, signed); err != nil {
+		response.Body.Close()
+		return nil, err
+	}
+	response = withFilename(withCacheControl(response, request.Key))
+
+	if response, err = u.decodeResponse(ctx, input, request, response); err != nil {
+		return nil, err
+	}
+
+	// checksum is about the whole object, a range cannot be verified
+	if response.Body != nil && response.ContentRange == nil {
+		response.Body = utils.NewVerifyingReader(response.Body, aws.ToString(response.ChecksumSHA256), aws.ToString(response.ChecksumCRC32C))
+	}
+
+	return
+}
+
+// getObject download the object, or the blob when it is a pointer of deduplicated document
+func (u *usecase) getObject(ctx context.Context, input *s3.GetObjectInput) (response *s3.GetObjectOutput, err error) {
+	response, err = u.cachedGetObject(ctx, input)
+	if apiErrorCode(err) == "InvalidRange" && dedupeEnabled() {
+		// the range can be beyond the pointer manifest but not beyond the blob, check it without range
+		whole := *input
+		whole.Range = nil
+		response, err = u.s3Client.GetObject(ctx, &whole)
+		if err == nil && pointerBlob(response.Metadata) == "" {
+			response.Body.Close()
+			return nil, fmt.Errorf("%w: %s", document.ErrRangeNotSatisfiable, aws.ToString(input.Range))
+		}
+	}
+	if isNotFound(err) {
+		return nil, document.ErrDocumentNotFound
+	}
+	if apiErrorCode(err) == "InvalidRange" {
+		return nil, fmt.Errorf("%w: %s", document.ErrRangeNotSatisfiable, aws.ToString(input.Range))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+
+	if response != nil && pointerBlob(response.Metadata) != "" {
+		response, err = u.resolvePointer(ctx, response, input.Range)
+		if apiErrorCode(err) == "InvalidRange" {
+			return nil, fmt.Errorf("%w: %s", document.ErrRangeNotSatisfiable, aws.ToString(input.Range))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to download file: %w", err)
+		}
+	}
+	return response, nil
+}
+
+func (u *usecase) DeleteFile(ctx context.Context, fileIdentifier string) (err error) {
+
+	if isInternalKey(fileIdentifier) {
+		return document.ErrDocumentNotFound
+	}
+	bucketName := os.Getenv("BUCKET_NAME")
+	head, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
 		Key:    aws.String(fileIdentifier),
 	})
+	if isNotFound(err) {
+		return document.ErrDocumentNotFound
+	}
 	if err != nil {
-		err = fmt.Errorf("failed to download file: %w", err)
+		return fmt.Errorf("failed to delete file: %w", err)
 	}
 
-	return
+	_, err = u.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(fileIdentifier),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	u.invalidateCache(bucketName, fileIdentifier)
+
+	if blobKey := pointerBlob(head.Metadata); blobKey != "" {
+		if err = u.releaseBlob(ctx, bucketName, blobKey, fileIdentifier); err != nil {
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
+
+	if err = u.deleteRenditions(ctx, bucketName, fileIdentifier); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	if id := head.Metadata[metaDocumentId]; id != "" {
+		if err = u.releaseDocumentId(ctx, bucketName, id, fileIdentifier); err != nil {
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
+
+	if err = u.deleteRecord(ctx, bucketName, fileIdentifier); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+
+	return nil
 }
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"mime/multipart"
@@ -13,7 +14,9 @@ import (
 	"aws-s3-bucket/domain/upload/interfaces/mocks"
 	"aws-s3-bucket/models/document"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -70,7 +73,7 @@ func createMultipartFile(content string, filename string) (multipart.File, *mult
 func Test_DownloadFile(t *testing.T) {
 	usecase, mockS3Client := initUseCaseUnitTest(t)
 	type args struct {
-		request string
+		request document.RequestDownloadDocument
 	}
 	type expected struct {
 		err      error
@@ -85,16 +88,39 @@ func Test_DownloadFile(t *testing.T) {
 		{
 			name: "DownloadFile_Success",
 			args: args{
-				request: "data/example.txt",
+				request: document.RequestDownloadDocument{Key: "data/example.txt"},
 			},
 			prepare: func(args args) {
 				mockS3Client.On("GetObject", mock.Anything, mock.Anything).Return(nil, nil).Once()
 			},
 		},
+		{
+			name: "DownloadFile_Version",
+			args: args{
+				request: document.RequestDownloadDocument{Key: "data/example.txt", VersionId: "v1"},
+			},
+			prepare: func(args args) {
+				mockS3Client.On("GetObject", mock.Anything, mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+					return aws.ToString(input.VersionId) == "v1"
+				})).Return(nil, nil).Once()
+			},
+		},
+		{
+			name: "DownloadFile_NotFound",
+			args: args{
+				request: document.RequestDownloadDocument{Key: "data/example.txt", VersionId: "v0"},
+			},
+			prepare: func(args args) {
+				mockS3Client.On("GetObject", mock.Anything, mock.Anything).Return(nil, &types.NoSuchKey{}).Once()
+			},
+			expected: expected{
+				err: document.ErrDocumentNotFound,
+			},
+		},
 		{
 			name: "DownloadFile_Failure",
 			args: args{
-				request: "data/example.txt",
+				request: document.RequestDownloadDocument{Key: "data/example.txt"},
 			},
 			prepare: func(args args) {
 				mockS3Client.On("GetObject", mock.Anything, mock.Anything).Return(nil, errors.New("failed to download file")).Once()
@@ -155,6 +181,10 @@ func Test_UploadFile(t *testing.T) {
 			expected: expected{
 				response: document.ResponseUploadDocument{
 					DocumentUrl: fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), "data/example.txt"),
+					Key:         "data/example.txt",
+					Bucket:      "test-bucket",
+					Size:        20,
+					ContentType: "text/plain",
 				},
 			},
 		},
@@ -219,6 +249,10 @@ func Test_UploadBase64(t *testing.T) {
 			expected: expected{
 				response: document.ResponseUploadDocument{
 					DocumentUrl: fmt.Sprintf("%s/api/v1/download/%s", os.Getenv("BASE_URL"), "data/example.txt"),
+					Key:         "data/example.txt",
+					Bucket:      "test-bucket",
+					Size:        20,
+					ContentType: "text/txt",
 				},
 			},
 		},
@@ -249,7 +283,7 @@ func Test_UploadBase64(t *testing.T) {
 				},
 			},
 			expected: expected{
-				err:      fmt.Errorf("invalid base64 string format"),
+				err:      fmt.Errorf("%w: payload is truncated, length 21 cannot be decoded", document.ErrInvalidBase64),
 				response: document.ResponseUploadDocument{},
 			},
 		},
@@ -269,3 +303,28 @@ func Test_UploadBase64(t *testing.T) {
 		})
 	}
 }
+
+func Test_InternalKey(t *testing.T) {
+	// no S3 call is expected, the shared blob is never reached
+	usecase, _ := initUseCaseUnitTest(t)
+
+	_, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "blobs",
+		DocumentName:   "x",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+	})
+	require.ErrorIs(t, err, document.ErrReservedKey)
+
+	_, fileHeader, err := createMultipartFile("This is test content", "x.txt")
+	require.NoError(t, err)
+	_, err = usecase.UploadFile(context.Background(), document.RequestUploadDocumentFile{DocumentKey: "_ids", DocumentName: "x"}, fileHeader)
+	require.ErrorIs(t, err, document.ErrReservedKey)
+
+	require.ErrorIs(t, usecase.DeleteFile(context.Background(), "blobs/x"), document.ErrDocumentNotFound)
+
+	_, err = usecase.GetMetadata(context.Background(), "refs/x")
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+
+	_, err = usecase.RestoreVersion(context.Background(), "_renditions/x", "v1")
+	require.ErrorIs(t, err, document.ErrDocumentNotFound)
+}
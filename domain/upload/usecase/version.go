@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func (u *usecase) ListVersions(ctx context.Context, fileIdentifier string) (response []document.ResponseDocumentVersion, err error) {
+
+	if isInternalKey(fileIdentifier) {
+		return nil, document.ErrDocumentNotFound
+	}
+	input := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(os.Getenv("BUCKET_NAME")),
+		Prefix: aws.String(fileIdentifier),
+	}
+
+	response = []document.ResponseDocumentVersion{}
+	for {
+		output, err := u.s3Client.ListObjectVersions(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions: %w", err)
+		}
+
+		// prefix also match folder/example.png.bak, keep only the exact key
+		for _, version := range output.Versions {
+			if aws.ToString(version.Key) != fileIdentifier {
+				continue
+			}
+			response = append(response, document.ResponseDocumentVersion{
+				VersionId:    aws.ToString(version.VersionId),
+				IsLatest:     aws.ToBool(version.IsLatest),
+				Size:         aws.ToInt64(version.Size),
+				ETag:         aws.ToString(version.ETag),
+				LastModified: aws.ToTime(version.LastModified),
+			})
+		}
+		for _, marker := range output.DeleteMarkers {
+			if aws.ToString(marker.Key) != fileIdentifier {
+				continue
+			}
+			response = append(response, document.ResponseDocumentVersion{
+				VersionId:      aws.ToString(marker.VersionId),
+				IsLatest:       aws.ToBool(marker.IsLatest),
+				IsDeleteMarker: true,
+				LastModified:   aws.ToTime(marker.LastModified),
+			})
+		}
+
+		if !aws.ToBool(output.IsTruncated) {
+			break
+		}
+		input.KeyMarker = output.NextKeyMarker
+		input.VersionIdMarker = output.NextVersionIdMarker
+	}
+
+	if len(response) == 0 {
+		return nil, document.ErrDocumentNotFound
+	}
+	sort.SliceStable(response, func(i, j int) bool {
+		return response[i].LastModified.After(response[j].LastModified)
+	})
+
+	// versions are listed without signed link, the ones of a private document are refused as its download.
+	// the newest version tell the visibility, the document may be deleted so it is headed by version id
+	for _, version := range response {
+		if version.IsDeleteMarker {
+			continue
+		}
+		head, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket:    input.Bucket,
+			Key:       aws.String(fileIdentifier),
+			VersionId: aws.String(version.VersionId),
+		})
+		if isNotFound(err) {
+			return nil, document.ErrDocumentNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions: %w", err)
+		}
+		if err = checkVisibility(head.Metadata, false); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	return response, nil
+}
+
+// RestoreVersion copy the old version over the current one, so the restore itself become the latest version
+func (u *usecase) RestoreVersion(ctx context.Context, fileIdentifier, versionId string) (response document.ResponseUploadDocument, err error) {
+
+	if isInternalKey(fileIdentifier) {
+		return response, document.ErrDocumentNotFound
+	}
+	bucketName := os.Getenv("BUCKET_NAME")
+	head, err := u.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:    aws.String(bucketName),
+		Key:       aws.String(fileIdentifier),
+		VersionId: aws.String(versionId),
+	})
+	if isNotFound(err) {
+		err = document.ErrDocumentNotFound
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to restore version: %w", err)
+		return
+	}
+
+	// the blob of the replaced version is released as an overwrite would, the one of the restored version is referenced again
+	blobKey := pointerBlob(head.Metadata)
+	var previousBlob string
+	if dedupeEnabled() || blobKey != "" {
+		previousBlob = u.previousBlob(ctx, bucketName, fileIdentifier)
+	}
+
+	output, err := u.s3Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(bucketName),
+		Key:        aws.String(fileIdentifier),
+		CopySource: aws.String(copySource(bucketName, fileIdentifier, versionId)),
+	})
+	if isNotFound(err) {
+		err = document.ErrDocumentNotFound
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to restore version: %w", err)
+		return
+	}
+	u.invalidateCache(bucketName, fileIdentifier)
+
+	if blobKey != "" {
+		_, err = u.s3Client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(refKey(blobKey, fileIdentifier)),
+			Body:   bytes.NewReader(nil),
+		})
+		if err != nil {
+			return response, fmt.Errorf("failed to reference blob: %w", err)
+		}
+	}
+	if previousBlob != "" && previousBlob != blobKey {
+		if err = u.releaseBlob(ctx, bucketName, previousBlob, fileIdentifier); err != nil {
+			return response, err
+		}
+	}
+	documentId := head.Metadata[metaDocumentId]
+	if documentId != "" {
+		if err = u.putDocumentId(ctx, bucketName, documentId, fileIdentifier); err != nil {
+			return response, err
+		}
+	}
+
+	response = document.ResponseUploadDocument{
+		Id:          documentId,
+		DocumentUrl: documentUrl(fileIdentifier, documentId),
+		Key:         fileIdentifier,
+		Bucket:      bucketName,
+		Size:        documentSize(head),
+		ContentType: aws.ToString(head.ContentType),
+		Visibility:  head.Metadata[metaVisibility],
+	}
+	// pointer of deduplicated document has no content type of its own
+	if blobKey != "" {
+		response.ContentType = ""
+	}
+	response.PublicUrl = publicUrl(fileIdentifier, response.Visibility)
+	if output != nil {
+		response.VersionId = aws.ToString(output.VersionId)
+		if output.CopyObjectResult != nil {
+			response.ETag = aws.ToString(output.CopyObjectResult.ETag)
+		}
+	}
+
+	if err = u.saveRecord(ctx, response, head.Metadata); err != nil {
+		return document.ResponseUploadDocument{}, err
+	}
+
+	return withoutKey(response), nil
+}
+
+// copySource build url encoded CopySource "bucket/key?versionId=" expected by CopyObject
+func copySource(bucketName, key, versionId string) string {
+	source := bucketName + "/" + escapeKey(key)
+	if versionId != "" {
+		source += "?versionId=" + url.QueryEscape(versionId)
+	}
+	return source
+}
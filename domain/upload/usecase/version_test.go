@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListVersions(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	type expected struct {
+		err      error
+		response []document.ResponseDocumentVersion
+	}
+	tests := []struct {
+		name     string
+		prepare  func()
+		expected expected
+	}{
+		{
+			name: "ListVersions_Success",
+			prepare: func() {
+				mockS3Client.On("ListObjectVersions", mock.Anything, mock.MatchedBy(func(input *s3.ListObjectVersionsInput) bool {
+					return input.KeyMarker == nil
+				})).Return(&s3.ListObjectVersionsOutput{
+					Versions: []types.ObjectVersion{
+						{Key: aws.String("data/example.txt"), VersionId: aws.String("v2"), IsLatest: aws.Bool(false), Size: aws.Int64(10), ETag: aws.String(`"etag2"`), LastModified: aws.Time(now.Add(-time.Hour))},
+						{Key: aws.String("data/example.txt.bak"), VersionId: aws.String("other"), LastModified: aws.Time(now)},
+					},
+					DeleteMarkers: []types.DeleteMarkerEntry{
+						{Key: aws.String("data/example.txt"), VersionId: aws.String("v3"), IsLatest: aws.Bool(true), LastModified: aws.Time(now)},
+					},
+					IsTruncated:         aws.Bool(true),
+					NextKeyMarker:       aws.String("data/example.txt"),
+					NextVersionIdMarker: aws.String("v2"),
+				}, nil).Once()
+				mockS3Client.On("ListObjectVersions", mock.Anything, mock.MatchedBy(func(input *s3.ListObjectVersionsInput) bool {
+					return aws.ToString(input.VersionIdMarker) == "v2"
+				})).Return(&s3.ListObjectVersionsOutput{
+					Versions: []types.ObjectVersion{
+						{Key: aws.String("data/example.txt"), VersionId: aws.String("v1"), IsLatest: aws.Bool(false), Size: aws.Int64(8), LastModified: aws.Time(now.Add(-2 * time.Hour))},
+					},
+				}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+					return aws.ToString(input.Key) == "data/example.txt" && aws.ToString(input.VersionId) == "v2"
+				})).Return(&s3.HeadObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: []document.ResponseDocumentVersion{
+					{VersionId: "v3", IsLatest: true, IsDeleteMarker: true, LastModified: now},
+					{VersionId: "v2", Size: 10, ETag: `"etag2"`, LastModified: now.Add(-time.Hour)},
+					{VersionId: "v1", Size: 8, LastModified: now.Add(-2 * time.Hour)},
+				},
+			},
+		},
+		{
+			name: "ListVersions_Private",
+			prepare: func() {
+				mockS3Client.On("ListObjectVersions", mock.Anything, mock.Anything).Return(&s3.ListObjectVersionsOutput{
+					Versions: []types.ObjectVersion{
+						{Key: aws.String("data/example.txt"), VersionId: aws.String("v1"), IsLatest: aws.Bool(true), Size: aws.Int64(8), LastModified: aws.Time(now)},
+					},
+				}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, mock.Anything).Return(&s3.HeadObjectOutput{
+					Metadata: map[string]string{metaVisibility: visibilityPrivate},
+				}, nil).Once()
+			},
+			expected: expected{
+				err: fmt.Errorf("%w: private document requires a signed link", document.ErrInvalidSignature),
+			},
+		},
+		{
+			name: "ListVersions_NotFound",
+			prepare: func() {
+				mockS3Client.On("ListObjectVersions", mock.Anything, mock.Anything).Return(&s3.ListObjectVersionsOutput{}, nil).Once()
+			},
+			expected: expected{
+				err: document.ErrDocumentNotFound,
+			},
+		},
+		{
+			name: "ListVersions_Failure",
+			prepare: func() {
+				mockS3Client.On("ListObjectVersions", mock.Anything, mock.Anything).Return(nil, errors.New("list failed")).Once()
+			},
+			expected: expected{
+				err: fmt.Errorf("failed to list versions: %w", errors.New("list failed")),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			response, err := usecase.ListVersions(nil, "data/example.txt")
+
+			require.Equal(t, tt.expected.err, err)
+			require.Equal(t, tt.expected.response, response)
+		})
+	}
+}
+
+func Test_RestoreVersion(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	headVersion := func(versionId string) interface{} {
+		return mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+			return aws.ToString(input.Key) == "data/my example.txt" && aws.ToString(input.VersionId) == versionId
+		})
+	}
+
+	type expected struct {
+		err      error
+		response document.ResponseUploadDocument
+	}
+	tests := []struct {
+		name     string
+		prepare  func()
+		expected expected
+	}{
+		{
+			name: "RestoreVersion_Success",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, headVersion("v1")).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(20),
+					ContentType:   aws.String("text/plain"),
+				}, nil).Once()
+				mockS3Client.On("CopyObject", mock.Anything, mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+					return aws.ToString(input.CopySource) == "test-bucket/data/my%20example.txt?versionId=v1" &&
+						aws.ToString(input.Key) == "data/my example.txt"
+				})).Return(&s3.CopyObjectOutput{VersionId: aws.String("v4"), CopyObjectResult: &types.CopyObjectResult{ETag: aws.String(`"etag"`)}}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadDocument{
+					DocumentUrl: "http://localhost:8080/api/v1/download/data/my%20example.txt",
+					Key:         "data/my example.txt",
+					Bucket:      "test-bucket",
+					ETag:        `"etag"`,
+					VersionId:   "v4",
+					Size:        20,
+					ContentType: "text/plain",
+				},
+			},
+		},
+		{
+			name: "RestoreVersion_Deduplicated",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, headVersion("v1")).Return(&s3.HeadObjectOutput{
+					ContentLength: aws.Int64(100),
+					ContentType:   aws.String(pointerContentType),
+					Metadata:      map[string]string{metaDedupeBlob: testBlob, metaDedupeSize: "20", metaDocumentId: "0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70"},
+				}, nil).Once()
+				mockS3Client.On("HeadObject", mock.Anything, headVersion("")).Return(&s3.HeadObjectOutput{
+					Metadata: map[string]string{metaDedupeBlob: testBlob},
+				}, nil).Once()
+				mockS3Client.On("CopyObject", mock.Anything, mock.Anything).Return(&s3.CopyObjectOutput{VersionId: aws.String("v4")}, nil).Once()
+				// the restored pointer reference its blob again so it is not deleted with another document
+				mockS3Client.On("PutObject", mock.Anything, matchKey(refKey(testBlob, "data/my example.txt"))).Return(&s3.PutObjectOutput{}, nil).Once()
+				mockS3Client.On("PutObject", mock.Anything, matchKey("_ids/0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70")).Return(&s3.PutObjectOutput{}, nil).Once()
+			},
+			expected: expected{
+				response: document.ResponseUploadDocument{
+					Id:          "0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70",
+					DocumentUrl: "http://localhost:8080/api/v1/files/0b5a3f3e-8c1d-4a5e-9f7a-2d3c4b5a6f70",
+					Key:         "data/my example.txt",
+					Bucket:      "test-bucket",
+					VersionId:   "v4",
+					Size:        20,
+				},
+			},
+		},
+		{
+			name: "RestoreVersion_NotFound",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, mock.Anything).Return(nil, &smithy.GenericAPIError{Code: "NoSuchVersion"}).Once()
+			},
+			expected: expected{
+				err: document.ErrDocumentNotFound,
+			},
+		},
+		{
+			name: "RestoreVersion_Failure",
+			prepare: func() {
+				mockS3Client.On("HeadObject", mock.Anything, mock.Anything).Return(&s3.HeadObjectOutput{}, nil).Once()
+				mockS3Client.On("CopyObject", mock.Anything, mock.Anything).Return(nil, errors.New("copy failed")).Once()
+			},
+			expected: expected{
+				err: fmt.Errorf("failed to restore version: %w", errors.New("copy failed")),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+
+			response, err := usecase.RestoreVersion(nil, "data/my example.txt", "v1")
+
+			require.Equal(t, tt.expected.err, err)
+			require.Equal(t, tt.expected.response, response)
+		})
+	}
+}
+
+func Test_RestoreVersion_Record(t *testing.T) {
+	usecase, mockS3Client, mockRepository := initRecordUnitTest(t)
+
+	mockS3Client.On("HeadObject", mock.Anything, matchKey("data/example.txt")).Return(&s3.HeadObjectOutput{
+		ContentLength: aws.Int64(20),
+		ContentType:   aws.String("text/plain"),
+		Metadata:      map[string]string{metaTags: "invoice"},
+	}, nil).Once()
+	mockS3Client.On("CopyObject", mock.Anything, mock.Anything).Return(&s3.CopyObjectOutput{}, nil).Once()
+	mockRepository.On("SaveDocument", mock.Anything, document.Record{
+		Key:         "data/example.txt",
+		Bucket:      "test-bucket",
+		Size:        20,
+		ContentType: "text/plain",
+		Status:      document.RecordStatusActive,
+		Tags:        []string{"invoice"},
+	}).Return(nil).Once()
+
+	_, err := usecase.RestoreVersion(context.Background(), "data/example.txt", "v1")
+	require.NoError(t, err)
+}
+
+func Test_UploadBase64_VersionId(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+
+	mockS3Client.On("PutObject", mock.Anything, mock.Anything).Return(&s3.PutObjectOutput{VersionId: aws.String("v1")}, nil).Once()
+
+	response, err := usecase.UploadBase64(nil, document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "v1", response.VersionId)
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"aws-s3-bucket/models/document"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// metaVisibility is public or private, a document without it is served as before visibility existed
+	metaVisibility = "visibility"
+
+	visibilityPublic  = "public"
+	visibilityPrivate = "private"
+
+	visibilityModeAcl    = "acl"
+	visibilityModePrefix = "prefix"
+
+	defaultPublicPrefix      = "public/"
+	defaultPublicCacheMaxAge = 86400
+)
+
+// visibilityMode is configured by VISIBILITY_MODE: acl put public documents with public-read ACL, prefix store
+// them under PUBLIC_PREFIX for buckets with ACLs disabled where a bucket policy grant public read of the prefix.
+// empty keep every object private in S3, the visibility is then only enforced by this service
+func visibilityMode() string {
+	return os.Getenv("VISIBILITY_MODE")
+}
+
+// publicPrefix is configured by PUBLIC_PREFIX, used by the prefix mode
+func publicPrefix() string {
+	if prefix := os.Getenv("PUBLIC_PREFIX"); prefix != "" {
+		return strings.TrimSuffix(prefix, "/") + "/"
+	}
+	return defaultPublicPrefix
+}
+
+// uploadVisibility is the visibility of the request, DEFAULT_VISIBILITY when empty
+func uploadVisibility(visibility string) string {
+	if visibility != "" {
+		return visibility
+	}
+	return os.Getenv("DEFAULT_VISIBILITY")
+}
+
+// applyVisibility record the visibility on the upload, DEFAULT_VISIBILITY when it has none, and move a public document under the public prefix
+// in prefix mode. a private document cannot be under it as the bucket policy would expose it
+func applyVisibility(doc upload) (upload, error) {
+	if doc.visibility = uploadVisibility(doc.visibility); doc.visibility == "" {
+		return doc, nil
+	}
+	if doc.visibility != visibilityPublic && doc.visibility != visibilityPrivate {
+		return doc, fmt.Errorf("%w: unknown visibility %q", document.ErrInvalidVisibility, doc.visibility)
+	}
+
+	if visibilityMode() == visibilityModePrefix && doc.visibility == visibilityPublic && !strings.HasPrefix(doc.key, publicPrefix()) {
+		doc.key = publicPrefix() + doc.key
+	}
+	if err := checkPublicPrefix(doc.key, doc.visibility); err != nil {
+		return doc, err
+	}
+	doc.metadata = withMetadata(doc.metadata, map[string]string{metaVisibility: doc.visibility})
+	return doc, nil
+}
+
+// checkPublicPrefix refuse a private document under the public prefix in prefix mode, the bucket policy would expose it
+func checkPublicPrefix(key, visibility string) error {
+	prefix := publicPrefix()
+	if visibilityMode() == visibilityModePrefix && visibility == visibilityPrivate && strings.HasPrefix(key, prefix) {
+		return fmt.Errorf("%w: private document cannot be under %q", document.ErrInvalidVisibility, prefix)
+	}
+	return nil
+}
+
+// withDerivedVisibility give an object derived from a document, a rendition or a preview, the visibility of the document
+// so it is served and cached the same way
+func withDerivedVisibility(input *s3.PutObjectInput, metadata map[string]string) *s3.PutObjectInput {
+	if visibility := metadata[metaVisibility]; visibility != "" {
+		input.Metadata = withMetadata(input.Metadata, map[string]string{metaVisibility: visibility})
+	}
+	input.ACL = visibilityACL(metadata)
+	if cacheControl := visibilityCacheControl(metadata); cacheControl != "" {
+		input.CacheControl = aws.String(cacheControl)
+	}
+	return input
+}
+
+// visibilityACL is the canned ACL of an object with the metadata, empty keep the bucket default
+func visibilityACL(metadata map[string]string) types.ObjectCannedACL {
+	if visibilityMode() == visibilityModeAcl && metadata[metaVisibility] == visibilityPublic {
+		return types.ObjectCannedACLPublicRead
+	}
+	return ""
+}
+
+// visibilityCacheControl is the Cache-Control of an object with the metadata, public document is cached
+// for PUBLIC_CACHE_MAX_AGE seconds by browsers and CDN, private one is never stored
+func visibilityCacheControl(metadata map[string]string) string {
+	switch metadata[metaVisibility] {
+	case visibilityPublic:
+		maxAge, err := strconv.Atoi(os.Getenv("PUBLIC_CACHE_MAX_AGE"))
+		if err != nil || maxAge <= 0 {
+			maxAge = defaultPublicCacheMaxAge
+		}
+		return fmt.Sprintf("public, max-age=%d", maxAge)
+	case visibilityPrivate:
+		return "private, no-store"
+	}
+	return ""
+}
+
+// publicUrl is the url of public document on PUBLIC_BASE_URL, a CDN or the public endpoint of the bucket.
+// deduplicated document is a pointer in S3, it cannot be served from there
+func publicUrl(key, visibility string) string {
+	baseUrl := os.Getenv("PUBLIC_BASE_URL")
+	if visibility != visibilityPublic || baseUrl == "" || dedupeEnabled() {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(baseUrl, "/"), escapeKey(key))
+}
+
+// checkVisibility refuse a private document downloaded without signed link
+func checkVisibility(metadata map[string]string, signed bool) error {
+	if metadata[metaVisibility] == visibilityPrivate && !signed {
+		return fmt.Errorf("%w: private document requires a signed link", document.ErrInvalidSignature)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"aws-s3-bucket/models/document"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UploadBase64_Visibility(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	os.Setenv("VISIBILITY_MODE", "acl")
+	os.Setenv("PUBLIC_BASE_URL", "https://cdn.example.com/")
+	defer os.Unsetenv("VISIBILITY_MODE")
+	defer os.Unsetenv("PUBLIC_BASE_URL")
+
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.ToString(input.Key) == "data/example.txt" && input.ACL == types.ObjectCannedACLPublicRead &&
+			input.Metadata[metaVisibility] == visibilityPublic && aws.ToString(input.CacheControl) == "public, max-age=86400"
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+		Visibility:     visibilityPublic,
+	})
+	require.NoError(t, err)
+	require.Equal(t, visibilityPublic, response.Visibility)
+	require.Equal(t, "https://cdn.example.com/data/example.txt", response.PublicUrl)
+
+	// private is the default, it is never public-read
+	os.Setenv("DEFAULT_VISIBILITY", visibilityPrivate)
+	defer os.Unsetenv("DEFAULT_VISIBILITY")
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return input.ACL == "" && input.Metadata[metaVisibility] == visibilityPrivate && aws.ToString(input.CacheControl) == "private, no-store"
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err = usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+	})
+	require.NoError(t, err)
+	require.Equal(t, visibilityPrivate, response.Visibility)
+	require.Empty(t, response.PublicUrl)
+}
+
+func Test_UploadBase64_VisibilityPrefix(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	os.Setenv("VISIBILITY_MODE", "prefix")
+	defer os.Unsetenv("VISIBILITY_MODE")
+
+	mockS3Client.On("PutObject", mock.Anything, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+		return aws.ToString(input.Key) == "public/data/example.txt" && input.ACL == ""
+	})).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	response, err := usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+		Visibility:     visibilityPublic,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "public/data/example.txt", response.Key)
+
+	_, err = usecase.UploadBase64(context.Background(), document.RequestUploadDocumentBase64{
+		DocumentKey:    "public/data",
+		DocumentName:   "example",
+		DocumentBase64: "data:text/txt;base64,VGhpcyBpcyB0ZXN0IGNvbnRlbnQ=",
+		Visibility:     visibilityPrivate,
+	})
+	require.ErrorIs(t, err, document.ErrInvalidVisibility)
+}
+
+func Test_DownloadFile_Private(t *testing.T) {
+	usecase, mockS3Client := initUseCaseUnitTest(t)
+	os.Setenv("DOWNLOAD_SIGNING_KEYS", "k1:secret")
+	defer os.Unsetenv("DOWNLOAD_SIGNING_KEYS")
+
+	private := func() *s3.GetObjectOutput {
+		return &s3.GetObjectOutput{
+			Body:     io.NopCloser(strings.NewReader("content")),
+			Metadata: map[string]string{metaVisibility: visibilityPrivate},
+		}
+	}
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/example.txt")).Return(private(), nil).Once()
+	_, err := usecase.DownloadFile(context.Background(), document.RequestDownloadDocument{Key: "data/example.txt"})
+	require.ErrorIs(t, err, document.ErrInvalidSignature)
+
+	link, err := usecase.SignDownloadLink(context.Background(), document.RequestSignDownload{Key: "data/example.txt"})
+	require.NoError(t, err)
+
+	mockS3Client.On("GetObject", mock.Anything, matchKey("data/example.txt")).Return(private(), nil).Once()
+	response, err := usecase.DownloadFile(context.Background(), linkRequest(t, link.Url, ""))
+	require.NoError(t, err)
+	require.Equal(t, "private, no-store", aws.ToString(response.CacheControl))
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	configApp "aws-s3-bucket/config"
+	uploadCache "aws-s3-bucket/domain/upload/cache"
 	uploadHttp "aws-s3-bucket/domain/upload/delivery/http"
+	uploadRepository "aws-s3-bucket/domain/upload/repository"
 	uploadUsecase "aws-s3-bucket/domain/upload/usecase"
 	"aws-s3-bucket/shared/constant"
 	"context"
@@ -33,6 +35,10 @@ func main() {
 	app := fiber.New(
 		fiber.Config{
 			AppName: "aws-bucket",
+			// client address of signed links bound to an ip, only set behind a proxy overwriting the header
+			ProxyHeader: os.Getenv("PROXY_HEADER"),
+			// keys are escaped per segment in the urls of documents
+			UnescapePath: true,
 		},
 	)
 	// Middleware to set request ID and CORS headers
@@ -44,9 +50,11 @@ func main() {
 			c.Locals(constant.HEADER_REQUEST_ID, requestId)
 
 		}
+		// uploader is recorded in the document database, read by usecase from the request context
+		c.Locals(constant.HEADER_UPLOADER, c.Get(constant.HEADER_UPLOADER))
 		c.Set("Access-Control-Allow-Origin", "*")
 		c.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Cache-Control, Pragma, X-Request-ID")
+		c.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, Accept, Origin, Cache-Control, Pragma, X-Request-ID, X-Checksum-Sha256, X-Checksum-Crc32c, X-Uploader-Id, X-Api-Key")
 		c.Set("Access-Control-Allow-Credentials", "true")
 		if c.Method() == fiber.MethodOptions {
 			return c.SendStatus(fiber.StatusNoContent)
@@ -83,8 +91,48 @@ func main() {
 	v1 := app.Group("/api/v1/")
 	validator := configApp.NewValidator()
 
+	usecaseOptions := []uploadUsecase.Option{uploadUsecase.WithPresigner(s3.NewPresignClient(s3Client))}
+
+	// document database is optional, without it nothing is recorded and listing is not available
+	if driver := os.Getenv("DATABASE_DRIVER"); driver != "" {
+		repository, db, err := uploadRepository.Open(context.TODO(), driver, os.Getenv("DATABASE_URL"))
+		if err != nil {
+			log.Fatalf("unable to open document database, %v", err)
+		}
+		defer db.Close()
+		usecaseOptions = append(usecaseOptions, uploadUsecase.WithRepository(repository))
+	}
+
+	// download cache is optional, small objects are kept in memory and larger ones on local disk
+	memorySize, _ := strconv.ParseInt(os.Getenv("DOWNLOAD_CACHE_MEMORY_SIZE"), 10, 64)
+	diskSize, _ := strconv.ParseInt(os.Getenv("DOWNLOAD_CACHE_DISK_SIZE"), 10, 64)
+	if memorySize > 0 || diskSize > 0 {
+		cacheConfig := uploadCache.Config{
+			TTL:             time.Minute,
+			MemorySize:      memorySize,
+			MemoryMaxObject: 1 << 20,
+			DiskDir:         os.Getenv("DOWNLOAD_CACHE_DISK_DIR"),
+			DiskSize:        diskSize,
+			DiskMaxObject:   64 << 20,
+		}
+		if ttl, err := time.ParseDuration(os.Getenv("DOWNLOAD_CACHE_TTL")); err == nil && ttl > 0 {
+			cacheConfig.TTL = ttl
+		}
+		if maxObject, err := strconv.ParseInt(os.Getenv("DOWNLOAD_CACHE_MEMORY_MAX_OBJECT"), 10, 64); err == nil && maxObject > 0 {
+			cacheConfig.MemoryMaxObject = maxObject
+		}
+		if maxObject, err := strconv.ParseInt(os.Getenv("DOWNLOAD_CACHE_DISK_MAX_OBJECT"), 10, 64); err == nil && maxObject > 0 {
+			cacheConfig.DiskMaxObject = maxObject
+		}
+		downloadCache, err := uploadCache.New(cacheConfig)
+		if err != nil {
+			log.Fatalf("unable to create download cache, %v", err)
+		}
+		usecaseOptions = append(usecaseOptions, uploadUsecase.WithCache(downloadCache))
+	}
+
 	// Initialize the usecase
-	multiUsecase := uploadUsecase.NewUsecase(s3Client)
+	multiUsecase := uploadUsecase.NewUsecase(s3Client, usecaseOptions...)
 
 	// Initialize the upload HTTP handler
 	uploadHttp.NewHandler(v1, multiUsecase, validator)
@@ -0,0 +1,13 @@
+package document
+
+// Archive is the documents resolved from RequestArchiveDocument, ready to be streamed as zip
+type Archive struct {
+	Entries   []ArchiveEntry
+	TotalSize int64
+}
+
+type ArchiveEntry struct {
+	Key  string
+	Name string
+	Size int64
+}
@@ -0,0 +1,20 @@
+package document
+
+import "time"
+
+// CachedObject is an object of the download cache without its content
+type CachedObject struct {
+	ETag            string
+	Size            int64
+	ContentType     string
+	ContentEncoding string
+	CacheControl    string
+	ChecksumSHA256  string
+	ChecksumCRC32C  string
+	LastModified    *time.Time
+	Expires         string
+	Metadata        map[string]string
+
+	// Stale is set by the cache once the ttl elapsed, the object must be revalidated before it is served
+	Stale bool
+}
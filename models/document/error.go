@@ -0,0 +1,30 @@
+package document
+
+import "errors"
+
+var (
+	ErrDocumentAlreadyExists = errors.New("document already exists")
+	ErrDocumentNotFound      = errors.New("document not found")
+	ErrChecksumMismatch      = errors.New("checksum mismatch")
+	ErrInvalidChecksum       = errors.New("invalid checksum format")
+	ErrInvalidTransformation = errors.New("invalid image transformation")
+	ErrEncryptedDocument     = errors.New("encrypted document is not allowed")
+	ErrArchiveTooLarge       = errors.New("archive too large")
+	ErrInvalidArchive        = errors.New("invalid archive")
+	ErrRangeNotSatisfiable   = errors.New("range not satisfiable")
+	ErrInvalidBatch          = errors.New("invalid batch")
+	ErrInvalidUrl            = errors.New("invalid url")
+	ErrRemoteFetch           = errors.New("failed to fetch remote document")
+	ErrDocumentTooLarge      = errors.New("document too large")
+	ErrBucketNotAllowed      = errors.New("bucket is not allowed")
+	ErrInvalidCopy           = errors.New("invalid copy")
+	ErrDatabaseNotConfigured = errors.New("document database is not configured")
+	ErrInvalidMetadata       = errors.New("invalid metadata")
+	ErrInvalidSearch         = errors.New("invalid search")
+	ErrInvalidSignature      = errors.New("invalid signature")
+	ErrSigningNotConfigured  = errors.New("download signing is not configured")
+	ErrInvalidVisibility     = errors.New("invalid visibility")
+	ErrInvalidBase64         = errors.New("invalid base64")
+	ErrReservedKey           = errors.New("key is reserved")
+	ErrUnauthorized          = errors.New("unauthorized")
+)
@@ -0,0 +1,59 @@
+package document
+
+import "time"
+
+const (
+	RecordStatusActive  = "active"
+	RecordStatusDeleted = "deleted"
+)
+
+// Record is the document row kept by the metadata database, written alongside every upload and delete
+type Record struct {
+	// Id is internal, it break ties of the cursor pagination
+	Id int64 `json:"-"`
+	// DocumentId is the opaque id of the document when OPAQUE_IDS is enabled
+	DocumentId     string            `json:"id,omitempty"`
+	Key            string            `json:"key"`
+	Bucket         string            `json:"bucket"`
+	Size           int64             `json:"size"`
+	ContentType    string            `json:"content_type,omitempty"`
+	ChecksumSHA256 string            `json:"checksum_sha256,omitempty"`
+	Uploader       string            `json:"uploader,omitempty"`
+	Visibility     string            `json:"visibility,omitempty"`
+	Status         string            `json:"status"`
+	Tags           []string          `json:"tags,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	DeletedAt      *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// RecordFilter select the records to list, ordered by key
+type RecordFilter struct {
+	Bucket         string
+	Prefix         string
+	IncludeDeleted bool
+	// ExcludeVisibility leave out the records of this visibility
+	ExcludeVisibility string
+	Limit             int
+	Offset            int
+}
+
+// SearchFilter select the active records to search, empty field does not filter
+type SearchFilter struct {
+	Bucket         string
+	Name           string
+	ContentType    string
+	MinSize        int64
+	MaxSize        int64
+	UploadedAfter  *time.Time
+	UploadedBefore *time.Time
+	Tags           []string
+	Metadata       map[string]string
+	// ExcludeVisibility leave out the records of this visibility
+	ExcludeVisibility string
+	// Sort is a column with "-" prefix for descending order: key, size or created_at
+	Sort   string
+	Limit  int
+	Cursor string
+}
@@ -4,9 +4,165 @@ type RequestUploadDocumentBase64 struct {
 	DocumentKey    string `json:"document_key" validate:"required" example:"folder-in-s3"`
 	DocumentName   string `json:"document_name" validate:"required" example:"example"`
 	DocumentBase64 string `json:"document_base64" validate:"required" example:"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAIAAACQd1PeAAAAA3NCSVQICAjb4U/gAAAADElEQVQImWMwMTEBAAE8AJ1tV5pJAAAAAElFTkSuQmCC"`
+	Overwrite      string `json:"overwrite" validate:"omitempty,oneof=allow reject rename" example:"allow"`
+	ChecksumSHA256 string `json:"checksum_sha256"`
+	ChecksumCRC32C string `json:"checksum_crc32c"`
+
+	// ContentType take precedence over the media type of the data uri, DocumentBase64 can also be the bare
+	// payload in the standard or url safe alphabet, padded or not. The content is sniffed when both are empty
+	ContentType string `json:"content_type" example:"image/png"`
+
+	// Tags and Metadata are searchable, stored as user metadata of the object
+	Tags     []string          `json:"tags" validate:"omitempty,max=20,dive,required,max=64,excludesall=0x2C" example:"invoice"`
+	Metadata map[string]string `json:"metadata" validate:"omitempty,max=20,dive,keys,required,max=64,endkeys,max=256"`
+	// Visibility private require a signed link to download, DEFAULT_VISIBILITY when empty
+	Visibility string `json:"visibility" validate:"omitempty,oneof=public private" example:"private"`
+}
+
+type RequestUploadDocumentUrl struct {
+	DocumentKey    string `json:"document_key" validate:"required" example:"folder-in-s3"`
+	DocumentName   string `json:"document_name" validate:"required" example:"example"`
+	Url            string `json:"url" validate:"required,url" example:"https://example.com/invoice.pdf"`
+	Overwrite      string `json:"overwrite" validate:"omitempty,oneof=allow reject rename" example:"allow"`
+	ChecksumSHA256 string `json:"checksum_sha256"`
+	ChecksumCRC32C string `json:"checksum_crc32c"`
+
+	// Tags and Metadata are searchable, stored as user metadata of the object
+	Tags     []string          `json:"tags" validate:"omitempty,max=20,dive,required,max=64,excludesall=0x2C" example:"invoice"`
+	Metadata map[string]string `json:"metadata" validate:"omitempty,max=20,dive,keys,required,max=64,endkeys,max=256"`
+	// Visibility private require a signed link to download, DEFAULT_VISIBILITY when empty
+	Visibility string `json:"visibility" validate:"omitempty,oneof=public private" example:"private"`
+}
+
+type RequestDownloadDocument struct {
+	Key string
+	// Id is set when the document is requested by its opaque id, Key is then the resolved key
+	Id        string
+	VersionId string
+
+	// AcceptEncoding is Accept-Encoding of client, compressed object is decompressed when it is not accepted
+	AcceptEncoding string
+	// Range is the Range header, a single byte range is supported
+	Range string
+	// Link is the signed link parameters, verified before fetching when set
+	Link *RequestDownloadLink
+	// Rendition is the name of a rendition profile or the pdf preview, served instead of the document
+	Rendition string
+
+	// image transformation, the original is served when all of them are empty
+	Width   int
+	Height  int
+	Fit     string
+	Format  string
+	Quality int
 }
 
 type RequestUploadDocumentFile struct {
 	DocumentKey  string `json:"document_key" validate:"required" example:"folder-in-s3"`
 	DocumentName string `json:"document_name" validate:"required" example:"example"`
+	Overwrite    string `json:"overwrite" validate:"omitempty,oneof=allow reject rename" example:"allow"`
+
+	ChecksumSHA256 string `json:"checksum_sha256"`
+	ChecksumCRC32C string `json:"checksum_crc32c"`
+
+	// Tags and Metadata are searchable, stored as user metadata of the object
+	Tags     []string          `json:"tags" validate:"omitempty,max=20,dive,required,max=64,excludesall=0x2C" example:"invoice"`
+	Metadata map[string]string `json:"metadata" validate:"omitempty,max=20,dive,keys,required,max=64,endkeys,max=256"`
+	// Visibility private require a signed link to download, DEFAULT_VISIBILITY when empty
+	Visibility string `json:"visibility" validate:"omitempty,oneof=public private" example:"private"`
+}
+
+type RequestArchiveDocument struct {
+	Keys   []string `json:"keys" validate:"required_without=Prefix,max=1000,dive,required" example:"folder-in-s3/example.png"`
+	Prefix string   `json:"prefix" validate:"required_without=Keys" example:"folder-in-s3/"`
+}
+
+type RequestUploadArchive struct {
+	DocumentKey string `json:"document_key" validate:"required" example:"folder-in-s3"`
+	Overwrite   string `json:"overwrite" validate:"omitempty,oneof=allow reject rename" example:"allow"`
+}
+
+type RequestUploadBatch struct {
+	DocumentKey string `json:"document_key" validate:"required" example:"folder-in-s3"`
+	// DocumentNames is name of every file in the same order, file name without extension is used when empty
+	DocumentNames []string `json:"document_names" validate:"omitempty,dive,required"`
+	Overwrite     string   `json:"overwrite" validate:"omitempty,oneof=allow reject rename" example:"allow"`
+	// Atomic delete the uploaded documents when one of them failed
+	Atomic bool `json:"atomic"`
+
+	// ChecksumSHA256 and ChecksumCRC32C is the expected checksum of every file in the same order, an empty one is not checked
+	ChecksumSHA256 []string `json:"checksum_sha256"`
+	ChecksumCRC32C []string `json:"checksum_crc32c"`
+
+	// Tags, Metadata and Visibility are applied to every file of the batch
+	Tags     []string          `json:"tags" validate:"omitempty,max=20,dive,required,max=64,excludesall=0x2C" example:"invoice"`
+	Metadata map[string]string `json:"metadata" validate:"omitempty,max=20,dive,keys,required,max=64,endkeys,max=256"`
+	// Visibility private require a signed link to download, DEFAULT_VISIBILITY when empty
+	Visibility string `json:"visibility" validate:"omitempty,oneof=public private" example:"private"`
+}
+
+type RequestCopyDocument struct {
+	SourceKey       string `json:"source_key" validate:"required" example:"folder-in-s3/example.png"`
+	SourceVersionId string `json:"source_version_id"`
+	// SourceBucket and DestinationBucket must be listed in ALLOWED_BUCKETS, BUCKET_NAME when empty
+	SourceBucket      string `json:"source_bucket"`
+	DestinationKey    string `json:"destination_key" validate:"required" example:"archive/example.png"`
+	DestinationBucket string `json:"destination_bucket"`
+	// MetadataDirective is copy (default) to keep metadata of source, or replace to use Metadata and ContentType
+	MetadataDirective string            `json:"metadata_directive" validate:"omitempty,oneof=copy replace" example:"copy"`
+	Metadata          map[string]string `json:"metadata"`
+	ContentType       string            `json:"content_type"`
+	Overwrite         string            `json:"overwrite" validate:"omitempty,oneof=allow reject rename" example:"reject"`
+}
+
+type RequestListDocument struct {
+	// Bucket must be listed in ALLOWED_BUCKETS, BUCKET_NAME when empty
+	Bucket         string `query:"bucket"`
+	Prefix         string `query:"prefix" example:"folder-in-s3/"`
+	IncludeDeleted bool   `query:"include_deleted"`
+	Limit          int    `query:"limit" validate:"omitempty,min=1,max=1000" example:"100"`
+	Offset         int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+type RequestSearchDocument struct {
+	// Bucket must be listed in ALLOWED_BUCKETS, BUCKET_NAME when empty
+	Bucket string `query:"bucket"`
+	// Name is a case insensitive substring of the key
+	Name string `query:"name" example:"invoice"`
+	// ContentType is exact, or a whole type with a wildcard subtype, ex: image/*
+	ContentType string `query:"content_type" example:"application/pdf"`
+	MinSize     int64  `query:"min_size" validate:"omitempty,min=0"`
+	MaxSize     int64  `query:"max_size" validate:"omitempty,min=0"`
+	// UploadedAfter and UploadedBefore are RFC 3339 date time
+	UploadedAfter  string `query:"uploaded_after" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2024-01-01T00:00:00Z"`
+	UploadedBefore string `query:"uploaded_before" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	// Tags must all be on the document
+	Tags []string `query:"tag" validate:"omitempty,max=20,dive,required"`
+	// Metadata is "name:value", every pair must match
+	Metadata []string `query:"metadata" validate:"omitempty,max=20,dive,required,contains=:" example:"owner:finance"`
+	Sort     string   `query:"sort" validate:"omitempty,oneof=key -key size -size created_at -created_at" example:"-created_at"`
+	Limit    int      `query:"limit" validate:"omitempty,min=1,max=1000" example:"50"`
+	// Cursor is the next_cursor of the previous page, the other parameters must be unchanged
+	Cursor string `query:"cursor"`
+}
+
+// RequestDownloadLink is the query of a signed download link, and the client it is checked against
+type RequestDownloadLink struct {
+	Expires   string
+	KeyId     string
+	Bind      string
+	Signature string
+
+	ClientIp    string
+	Disposition string
+}
+
+type RequestSignDownload struct {
+	Key string `json:"key" validate:"required" example:"folder-in-s3/example.png"`
+	// ExpiresIn is the lifetime of the link in seconds, DOWNLOAD_LINK_EXPIRES when empty
+	ExpiresIn int `json:"expires_in" validate:"omitempty,min=1,max=604800" example:"900"`
+	// ClientIp bind the link to the client address
+	ClientIp string `json:"client_ip" validate:"omitempty,ip" example:"203.0.113.7"`
+	// Disposition bind the link to inline, download or base64 response
+	Disposition string `json:"disposition" validate:"omitempty,oneof=inline download base64" example:"download"`
 }
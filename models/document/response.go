@@ -1,5 +1,106 @@
 package document
 
+import "time"
+
 type ResponseUploadDocument struct {
 	DocumentUrl string `json:"document_url"`
+	// Id is the opaque id of the document when OPAQUE_IDS is enabled, DocumentUrl then use it instead of the key
+	Id                 string     `json:"id,omitempty"`
+	Key                string     `json:"key,omitempty"`
+	Bucket             string     `json:"bucket,omitempty"`
+	ETag               string     `json:"etag,omitempty"`
+	VersionId          string     `json:"version_id,omitempty"`
+	Size               int64      `json:"size,omitempty"`
+	ContentType        string     `json:"content_type,omitempty"`
+	ContentEncoding    string     `json:"content_encoding,omitempty"`
+	ChecksumSHA256     string     `json:"checksum_sha256,omitempty"`
+	ChecksumCRC32C     string     `json:"checksum_crc32c,omitempty"`
+	SignedUrl          string     `json:"signed_url,omitempty"`
+	SignedUrlExpiresAt *time.Time `json:"signed_url_expires_at,omitempty"`
+	Visibility         string     `json:"visibility,omitempty"`
+	// PublicUrl serve public document from PUBLIC_BASE_URL without going through this service
+	PublicUrl string `json:"public_url,omitempty"`
+
+	Renditions map[string]string `json:"renditions,omitempty"`
+}
+
+type ResponseDocumentVersion struct {
+	VersionId      string    `json:"version_id"`
+	IsLatest       bool      `json:"is_latest"`
+	IsDeleteMarker bool      `json:"is_delete_marker"`
+	Size           int64     `json:"size"`
+	ETag           string    `json:"etag,omitempty"`
+	LastModified   time.Time `json:"last_modified"`
+}
+
+type ResponseDocumentMetadata struct {
+	Key             string            `json:"key"`
+	Size            int64             `json:"size"`
+	ContentType     string            `json:"content_type,omitempty"`
+	ContentEncoding string            `json:"content_encoding,omitempty"`
+	ETag            string            `json:"etag,omitempty"`
+	VersionId       string            `json:"version_id,omitempty"`
+	LastModified    *time.Time        `json:"last_modified,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	Pdf             *ResponsePdfInfo  `json:"pdf,omitempty"`
+}
+
+type ResponsePdfInfo struct {
+	PageCount int    `json:"page_count,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Author    string `json:"author,omitempty"`
+	Encrypted bool   `json:"encrypted"`
+}
+
+type ResponseUploadArchive struct {
+	Documents []ResponseExtractedDocument `json:"documents"`
+}
+
+type ResponseExtractedDocument struct {
+	Entry       string `json:"entry"`
+	Key         string `json:"key,omitempty"`
+	DocumentUrl string `json:"document_url,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type ResponseUploadBatch struct {
+	Documents  []ResponseBatchDocument `json:"documents"`
+	Succeeded  int                     `json:"succeeded"`
+	Failed     int                     `json:"failed"`
+	RolledBack bool                    `json:"rolled_back,omitempty"`
+}
+
+type ResponseBatchDocument struct {
+	File     string                  `json:"file"`
+	Document *ResponseUploadDocument `json:"document,omitempty"`
+	Error    string                  `json:"error,omitempty"`
+}
+
+type ResponseListDocument struct {
+	Documents []ResponseDocumentRecord `json:"documents"`
+	Limit     int                      `json:"limit"`
+	Offset    int                      `json:"offset"`
+}
+
+type ResponseDocumentRecord struct {
+	Record
+	DocumentUrl string `json:"document_url,omitempty"`
+}
+
+type ResponseSearchDocument struct {
+	Documents []ResponseDocumentRecord `json:"documents"`
+	// NextCursor is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ResultVary is the key of download result metadata holding the request header the content depends on,
+// set when it is not visible on the response itself
+type ResultVary struct{}
+
+// ResultFilename is the key of download result metadata holding the filename the document was uploaded with
+type ResultFilename struct{}
+
+type ResponseSignDownload struct {
+	Url       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
@@ -5,7 +5,31 @@ const (
 	STATUS_CODE_GENERAL_ERROR    = "500"
 	STATUS_CODE_VALIDATION_ERROR = "400"
 	STATUS_CODE_PARSING_REQUEST  = "4001"
+	STATUS_CODE_CONFLICT         = "409"
+	STATUS_CODE_NOT_FOUND        = "404"
+	STATUS_CODE_FORBIDDEN        = "403"
+	STATUS_CODE_UNAUTHORIZED     = "401"
 
 
 	HEADER_REQUEST_ID          = "X-Request-ID"
+	HEADER_API_KEY             = "X-Api-Key"
+	HEADER_CHECKSUM_SHA256     = "X-Checksum-Sha256"
+	HEADER_CHECKSUM_CRC32C     = "X-Checksum-Crc32c"
+	HEADER_UPLOADER            = "X-Uploader-Id"
+
+	OVERWRITE_ALLOW  = "allow"
+	OVERWRITE_REJECT = "reject"
+	OVERWRITE_RENAME = "rename"
+
+	IMAGE_FIT_CONTAIN = "contain"
+	IMAGE_FIT_COVER   = "cover"
+	IMAGE_FIT_FILL    = "fill"
+
+	IMAGE_FORMAT_JPEG = "jpeg"
+	IMAGE_FORMAT_PNG  = "png"
+	IMAGE_FORMAT_WEBP = "webp"
+
+	PDF_ENCRYPTED_ALLOW           = "allow"
+	PDF_ENCRYPTED_REJECT          = "reject"
+	PDF_ENCRYPTED_REJECT_PASSWORD = "reject-password"
 )
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// Base64Error is a document that is not valid base64, Reason tell what is wrong with it
+type Base64Error struct {
+	Reason string
+}
+
+func (e *Base64Error) Error() string {
+	return "invalid base64: " + e.Reason
+}
+
+func base64Error(format string, args ...any) error {
+	return &Base64Error{Reason: fmt.Sprintf(format, args...)}
+}
+
+// ParseBase64 accept a data uri "data:<mime>;base64,<payload>" or the bare payload, in the standard or the url
+// safe alphabet, padded or not. Line breaks of MIME base64 are ignored. contentType is the media type of the
+// data uri, empty for a bare payload. The whole payload is checked so decoding it cannot fail
+func ParseBase64(value string) (contentType, payload string, err error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", "", base64Error("document is empty")
+	}
+
+	payload = value
+	if rest, ok := strings.CutPrefix(value, "data:"); ok {
+		header, data, ok := strings.Cut(rest, ",")
+		if !ok {
+			return "", "", base64Error("data uri has no payload, a comma must follow its header")
+		}
+		mediaType, encoding, _ := strings.Cut(header, ";")
+		params := strings.Split(encoding, ";")
+		if !strings.EqualFold(params[len(params)-1], "base64") {
+			return "", "", base64Error("data uri is not base64 encoded, ;base64 is missing")
+		}
+		if mediaType != "" {
+			if _, _, err := mime.ParseMediaType(mediaType); err != nil {
+				return "", "", base64Error("media type %q is not valid", mediaType)
+			}
+		}
+		contentType, payload = mediaType, data
+	}
+
+	if err = checkBase64(payload); err != nil {
+		return "", "", err
+	}
+	return contentType, payload, nil
+}
+
+// checkBase64 report the first character that no alphabet allow, or a payload that cannot be complete
+func checkBase64(payload string) error {
+	var length, padding int
+	var standard, urlSafe bool
+	for i := 0; i < len(payload); i++ {
+		c := payload[i]
+		switch {
+		case c == '\r' || c == '\n':
+			continue
+		case c == '=':
+			padding++
+		case padding > 0:
+			return base64Error("padding is only allowed at the end, found %q at offset %d", c, i)
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		case c == '+' || c == '/':
+			standard = true
+		case c == '-' || c == '_':
+			urlSafe = true
+		default:
+			return base64Error("illegal character %q at offset %d", c, i)
+		}
+		if standard && urlSafe {
+			return base64Error("standard and url safe alphabets are mixed, at offset %d", i)
+		}
+		length++
+	}
+
+	switch {
+	case length == padding:
+		return base64Error("payload is empty")
+	case padding > 2:
+		return base64Error("too much padding, %d characters", padding)
+	case padding > 0 && length%4 != 0:
+		return base64Error("padding is incomplete, length %d is not a multiple of 4", length)
+	case length%4 == 1:
+		return base64Error("payload is truncated, length %d cannot be decoded", length)
+	}
+	return nil
+}
+
+// NewBase64Decoder decode a payload checked by ParseBase64 as it is read, in the alphabet it is written with
+func NewBase64Decoder(payload string) io.Reader {
+	encoding := base64.StdEncoding
+	if strings.ContainsAny(payload, "-_") {
+		encoding = base64.URLEncoding
+	}
+	if !strings.Contains(payload, "=") {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+	return base64Reader{decoder: base64.NewDecoder(encoding, strings.NewReader(payload))}
+}
+
+// base64Reader report decoding failure as Base64Error, the payload is read from memory so it cannot fail otherwise
+type base64Reader struct {
+	decoder io.Reader
+}
+
+func (r base64Reader) Read(p []byte) (int, error) {
+	n, err := r.decoder.Read(p)
+	var corrupt base64.CorruptInputError
+	if errors.As(err, &corrupt) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = base64Error("%s", err.Error())
+	}
+	return n, err
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseBase64(t *testing.T) {
+
+	type expected struct {
+		contentType string
+		payload     string
+		decoded     string
+		err         string
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected expected
+	}{
+		{
+			name:     "Data uri",
+			input:    "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAUA",
+			expected: expected{contentType: "image/png", payload: "iVBORw0KGgoAAAANSUhEUgAAAAUA", decoded: "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x05\x00"},
+		},
+		{
+			name:     "Data uri with parameter",
+			input:    "data:text/plain;charset=utf-8;base64,aGVsbG8=",
+			expected: expected{contentType: "text/plain", payload: "aGVsbG8=", decoded: "hello"},
+		},
+		{
+			name:     "Data uri without media type",
+			input:    "data:;base64,aGVsbG8=",
+			expected: expected{payload: "aGVsbG8=", decoded: "hello"},
+		},
+		{
+			name:     "Bare base64",
+			input:    "aGVsbG8gd29ybGQ=",
+			expected: expected{payload: "aGVsbG8gd29ybGQ=", decoded: "hello world"},
+		},
+		{
+			name:     "Bare base64 without padding",
+			input:    "aGVsbG8",
+			expected: expected{payload: "aGVsbG8", decoded: "hello"},
+		},
+		{
+			name:     "Url safe base64 without padding",
+			input:    "-_-_",
+			expected: expected{payload: "-_-_", decoded: "\xfb\xff\xbf"},
+		},
+		{
+			name:     "Url safe base64 with padding",
+			input:    "_-8=",
+			expected: expected{payload: "_-8=", decoded: "\xff\xef"},
+		},
+		{
+			name:     "Line breaks",
+			input:    "aGVs\r\nbG8g\nd29y\r\nbGQ=\n",
+			expected: expected{payload: "aGVs\r\nbG8g\nd29y\r\nbGQ=", decoded: "hello world"},
+		},
+		{
+			name:     "Missing data uri prefix",
+			input:    "dataimage/pngbase64,iVBORw0KGgoAAAANSUhEUgAAAAUA",
+			expected: expected{err: `invalid base64: illegal character ',' at offset 19`},
+		},
+		{
+			name:     "Data uri not base64",
+			input:    "data:imagepngbase64,iVBORw0KGgoAAAANSUhEUgAAAAUA",
+			expected: expected{err: "invalid base64: data uri is not base64 encoded, ;base64 is missing"},
+		},
+		{
+			name:     "Data uri without payload",
+			input:    "data:image/png;base64",
+			expected: expected{err: "invalid base64: data uri has no payload, a comma must follow its header"},
+		},
+		{
+			name:     "Data uri with invalid media type",
+			input:    "data:image png;base64,aGVsbG8=",
+			expected: expected{err: `invalid base64: media type "image png" is not valid`},
+		},
+		{
+			name:     "Data uri with empty payload",
+			input:    "data:image/png;base64,",
+			expected: expected{err: "invalid base64: payload is empty"},
+		},
+		{
+			name:     "Mixed alphabets",
+			input:    "ab+_",
+			expected: expected{err: "invalid base64: standard and url safe alphabets are mixed, at offset 3"},
+		},
+		{
+			name:     "Padding in the middle",
+			input:    "aGV=sbG8",
+			expected: expected{err: `invalid base64: padding is only allowed at the end, found 's' at offset 4`},
+		},
+		{
+			name:     "Incomplete padding",
+			input:    "aGVsbG8gd29ybG=",
+			expected: expected{err: "invalid base64: padding is incomplete, length 15 is not a multiple of 4"},
+		},
+		{
+			name:     "Truncated payload",
+			input:    "aGVsb",
+			expected: expected{err: "invalid base64: payload is truncated, length 5 cannot be decoded"},
+		},
+		{
+			name:     "Empty String",
+			input:    "",
+			expected: expected{err: "invalid base64: document is empty"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, payload, err := ParseBase64(tt.input)
+			if tt.expected.err != "" {
+				var invalid *Base64Error
+				require.ErrorAs(t, err, &invalid)
+				require.EqualError(t, err, tt.expected.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected.contentType, contentType)
+			require.Equal(t, tt.expected.payload, payload)
+
+			decoded, err := io.ReadAll(NewBase64Decoder(payload))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected.decoded, string(decoded))
+		})
+	}
+}
+
+func Test_NewBase64Decoder_Invalid(t *testing.T) {
+	_, err := io.ReadAll(NewBase64Decoder("aGVs*bG8="))
+
+	var invalid *Base64Error
+	require.ErrorAs(t, err, &invalid)
+}
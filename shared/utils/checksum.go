@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"strings"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+type Checksum struct {
+	SHA256 []byte
+	CRC32C []byte
+	Size   int64
+}
+
+// ComputeChecksum read r until EOF and compute the requested digests in one pass
+func ComputeChecksum(r io.Reader, withSHA256, withCRC32C bool) (checksum Checksum, err error) {
+	var sha, crc hash.Hash
+	writers := []io.Writer{}
+	if withSHA256 {
+		sha = sha256.New()
+		writers = append(writers, sha)
+	}
+	if withCRC32C {
+		crc = crc32.New(crc32cTable)
+		writers = append(writers, crc)
+	}
+
+	checksum.Size, err = io.Copy(io.MultiWriter(writers...), r)
+	if err != nil {
+		return
+	}
+	if sha != nil {
+		checksum.SHA256 = sha.Sum(nil)
+	}
+	if crc != nil {
+		checksum.CRC32C = crc.Sum(nil)
+	}
+	return
+}
+
+// DecodeChecksum accept checksum in base64 (as S3 does) or hex
+func DecodeChecksum(value string, size int) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if len(value) == size*2 {
+		if decoded, err := hex.DecodeString(value); err == nil {
+			return decoded, nil
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(decoded) != size {
+		return nil, fmt.Errorf("invalid checksum format")
+	}
+	return decoded, nil
+}
+
+type verifyingReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	expected []byte
+}
+
+// NewVerifyingReader wrap body and return error at EOF when the content does not match
+// the base64 SHA-256 or CRC32C checksum. composite checksum of multipart upload ("xxx-3") is not verified
+func NewVerifyingReader(body io.ReadCloser, checksumSHA256, checksumCRC32C string) io.ReadCloser {
+	reader := &verifyingReader{ReadCloser: body}
+	switch {
+	case checksumSHA256 != "" && !strings.Contains(checksumSHA256, "-"):
+		reader.hash = sha256.New()
+		reader.expected, _ = base64.StdEncoding.DecodeString(checksumSHA256)
+	case checksumCRC32C != "" && !strings.Contains(checksumCRC32C, "-"):
+		reader.hash = crc32.New(crc32cTable)
+		reader.expected, _ = base64.StdEncoding.DecodeString(checksumCRC32C)
+	default:
+		return body
+	}
+	return reader
+}
+
+func (r *verifyingReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF && !bytes.Equal(r.hash.Sum(nil), r.expected) {
+		err = fmt.Errorf("checksum mismatch")
+	}
+	return
+}
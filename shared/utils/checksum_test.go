@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testContent       = "This is test content"
+	testContentSHA256 = "cm34vMIcsxnd4DHhCjq0DuXOSXnO8BRRqb40H+yOgVM="
+	testContentCRC32C = "/t6Slg=="
+)
+
+func Test_ComputeChecksum(t *testing.T) {
+	checksum, err := ComputeChecksum(strings.NewReader(testContent), true, true)
+
+	require.NoError(t, err)
+	require.Equal(t, testContentSHA256, base64.StdEncoding.EncodeToString(checksum.SHA256))
+	require.Equal(t, testContentCRC32C, base64.StdEncoding.EncodeToString(checksum.CRC32C))
+	require.Equal(t, int64(len(testContent)), checksum.Size)
+
+	checksum, err = ComputeChecksum(strings.NewReader(testContent), false, true)
+
+	require.NoError(t, err)
+	require.Nil(t, checksum.SHA256)
+	require.NotNil(t, checksum.CRC32C)
+}
+
+func Test_DecodeChecksum(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		size     int
+		expected []byte
+		err      error
+	}{
+		{name: "base64", input: testContentCRC32C, size: 4, expected: []byte{0xfe, 0xde, 0x92, 0x96}},
+		{name: "hex", input: "fede9296", size: 4, expected: []byte{0xfe, 0xde, 0x92, 0x96}},
+		{name: "wrong size", input: testContentSHA256, size: 4, err: fmt.Errorf("invalid checksum format")},
+		{name: "invalid", input: "not-a-checksum", size: 4, err: fmt.Errorf("invalid checksum format")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeChecksum(tt.input, tt.size)
+
+			require.Equal(t, tt.err, err)
+			require.Equal(t, tt.expected, decoded)
+		})
+	}
+}
+
+func Test_NewVerifyingReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		sha256 string
+		crc32c string
+		err    error
+	}{
+		{name: "sha256 match", sha256: testContentSHA256},
+		{name: "crc32c match", crc32c: testContentCRC32C},
+		{name: "sha256 mismatch", sha256: base64.StdEncoding.EncodeToString(make([]byte, 32)), err: fmt.Errorf("checksum mismatch")},
+		{name: "crc32c mismatch", crc32c: "AAAAAA==", err: fmt.Errorf("checksum mismatch")},
+		{name: "composite not verified", sha256: "AAAAAA==-2"},
+		{name: "no checksum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewVerifyingReader(io.NopCloser(bytes.NewReader([]byte(testContent))), tt.sha256, tt.crc32c)
+
+			content, err := io.ReadAll(reader)
+
+			require.Equal(t, tt.err, err)
+			require.Equal(t, testContent, string(content))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	EncodingGzip   = "gzip"
+	EncodingBrotli = "br"
+)
+
+var ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// Compress encode data with gzip or br at default level
+func Compress(data []byte, encoding string) ([]byte, error) {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	switch encoding {
+	case EncodingGzip:
+		writer = gzip.NewWriter(&buf)
+	case EncodingBrotli:
+		writer = brotli.NewWriterLevel(&buf, brotli.DefaultCompression)
+	default:
+		return nil, fmt.Errorf("unsupported encoding %s", encoding)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+type decompressReader struct {
+	io.Reader
+	body io.Closer
+}
+
+func (r *decompressReader) Close() error {
+	if closer, ok := r.Reader.(io.Closer); ok {
+		closer.Close()
+	}
+	return r.body.Close()
+}
+
+// NewDecompressReader decode body encoded with gzip or br, closing it close body
+func NewDecompressReader(body io.ReadCloser, encoding string) (io.ReadCloser, error) {
+	switch encoding {
+	case EncodingGzip:
+		reader, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		return &decompressReader{Reader: reader, body: body}, nil
+	case EncodingBrotli:
+		return &decompressReader{Reader: brotli.NewReader(body), body: body}, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %s", encoding)
+}
+
+// AcceptsEncoding tell whether the Accept-Encoding header allow encoding, "*" match every encoding and q=0 refuse it
+func AcceptsEncoding(header, encoding string) bool {
+	accepted := false
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encoding && name != "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				quality, _ = strconv.ParseFloat(value, 64)
+			}
+		}
+		if name == encoding {
+			// explicit value win over the wildcard
+			return quality > 0
+		}
+		accepted = quality > 0
+	}
+	return accepted
+}
+
+// ParseRange resolve a single "bytes=" range of the Range header against size, the end is inclusive.
+// ok is false when the header is absent or not supported (multiple ranges, other unit) and must be ignored
+func ParseRange(header string, size int64) (start, end int64, ok bool, err error) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false, nil
+	}
+
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false, ErrRangeNotSatisfiable
+	}
+	first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+
+	if first == "" {
+		// suffix range, last n bytes
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix <= 0 || size == 0 {
+			return 0, 0, false, ErrRangeNotSatisfiable
+		}
+		return max(size-suffix, 0), size - 1, true, nil
+	}
+
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false, ErrRangeNotSatisfiable
+	}
+	end = size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false, ErrRangeNotSatisfiable
+		}
+		end = min(end, size-1)
+	}
+	return start, end, true, nil
+}
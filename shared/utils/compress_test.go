@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	data := []byte(strings.Repeat(`{"id":1,"name":"example"},`, 100))
+
+	for _, encoding := range []string{EncodingGzip, EncodingBrotli} {
+		compressed, err := Compress(data, encoding)
+		require.NoError(t, err)
+		require.Less(t, len(compressed), len(data))
+
+		reader, err := NewDecompressReader(io.NopCloser(strings.NewReader(string(compressed))), encoding)
+		require.NoError(t, err)
+		decompressed, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, data, decompressed)
+		require.NoError(t, reader.Close())
+	}
+
+	_, err := Compress(data, "zstd")
+	require.Error(t, err)
+}
+
+func TestAcceptsEncoding(t *testing.T) {
+	require.True(t, AcceptsEncoding("gzip, deflate, br", EncodingBrotli))
+	require.True(t, AcceptsEncoding("GZIP;q=0.5", EncodingGzip))
+	require.True(t, AcceptsEncoding("*", EncodingGzip))
+	require.False(t, AcceptsEncoding("", EncodingGzip))
+	require.False(t, AcceptsEncoding("deflate", EncodingGzip))
+	require.False(t, AcceptsEncoding("gzip;q=0", EncodingGzip))
+	require.False(t, AcceptsEncoding("*, br;q=0", EncodingBrotli))
+	require.False(t, AcceptsEncoding("*;q=0", EncodingGzip))
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header     string
+		start, end int64
+		ok         bool
+		err        error
+	}{
+		{header: "bytes=0-9", start: 0, end: 9, ok: true},
+		{header: "bytes=90-", start: 90, end: 99, ok: true},
+		{header: "bytes=90-200", start: 90, end: 99, ok: true},
+		{header: "bytes=-10", start: 90, end: 99, ok: true},
+		{header: "bytes=-200", start: 0, end: 99, ok: true},
+		{header: ""},
+		{header: "bytes=0-1,5-6"},
+		{header: "items=0-1"},
+		{header: "bytes=100-", err: ErrRangeNotSatisfiable},
+		{header: "bytes=9-1", err: ErrRangeNotSatisfiable},
+		{header: "bytes=abc", err: ErrRangeNotSatisfiable},
+	}
+
+	for _, tt := range tests {
+		start, end, ok, err := ParseRange(tt.header, 100)
+		require.ErrorIs(t, err, tt.err, tt.header)
+		require.Equal(t, tt.ok, ok, tt.header)
+		require.Equal(t, tt.start, start, tt.header)
+		require.Equal(t, tt.end, end, tt.header)
+	}
+}
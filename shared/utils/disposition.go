@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxFilenameLength is the size in bytes a filename is truncated to
+const MaxFilenameLength = 255
+
+// SanitizeFilename reduce a client filename to its base name, without the path of either OS, control
+// characters nor invalid UTF-8, truncated to MaxFilenameLength bytes. It is empty when nothing usable is left
+func SanitizeFilename(name string) string {
+	name = strings.ToValidUTF8(name, "_")
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "." || name == ".." {
+		return ""
+	}
+
+	for len(name) > MaxFilenameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// ContentDisposition build the Content-Disposition header of a download as of RFC 6266. A name that is
+// not plain ASCII is sent UTF-8 encoded in filename* (RFC 5987), filename keep an ASCII fallback
+// for the clients not supporting it
+func ContentDisposition(dispositionType, name string) string {
+	name = SanitizeFilename(name)
+	if name == "" {
+		return dispositionType
+	}
+
+	fallback := asciiFilename(name)
+	if fallback == name {
+		return fmt.Sprintf(`%s; filename="%s"`, dispositionType, name)
+	}
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, dispositionType, fallback, encodeExtValue(name))
+}
+
+// asciiFilename replace what a quoted-string cannot safely hold, % is replaced too as some clients decode it
+func asciiFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' || r == '%' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// encodeExtValue percent encode the UTF-8 bytes of the value except the attr-char of RFC 5987
+func encodeExtValue(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		if isAttrChar(b) {
+			builder.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&builder, "%%%02X", b)
+	}
+	return builder.String()
+}
+
+func isAttrChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) >= 0
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		expected string
+	}{
+		{name: "plain name", filename: "report.pdf", expected: "report.pdf"},
+		{name: "unix path", filename: "../../etc/passwd", expected: "passwd"},
+		{name: "windows path", filename: `C:\Users\me\report.pdf`, expected: "report.pdf"},
+		{name: "control characters", filename: "report\r\nSet-Cookie: a=b.pdf", expected: "reportSet-Cookie: a=b.pdf"},
+		{name: "invalid utf-8", filename: "report\xff.pdf", expected: "report_.pdf"},
+		{name: "dot", filename: "folder/..", expected: ""},
+		{name: "empty", filename: "", expected: ""},
+		{name: "too long", filename: strings.Repeat("é", 200), expected: strings.Repeat("é", 127)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, SanitizeFilename(test.filename))
+		})
+	}
+}
+
+func Test_ContentDisposition(t *testing.T) {
+	tests := []struct {
+		name        string
+		disposition string
+		filename    string
+		expected    string
+	}{
+		{name: "ascii name", disposition: "inline", filename: "example.png", expected: `inline; filename="example.png"`},
+		{name: "non ascii name", disposition: "attachment", filename: "résumé 2024.pdf", expected: `attachment; filename="r_sum_ 2024.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9%202024.pdf`},
+		{name: "quote and backslash", disposition: "attachment", filename: `a"b.pdf`, expected: `attachment; filename="a_b.pdf"; filename*=UTF-8''a%22b.pdf`},
+		{name: "percent", disposition: "attachment", filename: "100%.txt", expected: `attachment; filename="100_.txt"; filename*=UTF-8''100%25.txt`},
+		{name: "header injection", disposition: "inline", filename: "a.png\r\nX-Injected: 1", expected: `inline; filename="a.pngX-Injected: 1"`},
+		{name: "no usable name", disposition: "attachment", filename: "/", expected: "attachment"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, ContentDisposition(test.disposition, test.filename))
+		})
+	}
+}
@@ -2,43 +2,12 @@ package utils
 
 import (
 	"aws-s3-bucket/models/dto"
-	"encoding/base64"
 	"fmt"
-	"strings"
 
 	"github.com/ettle/strcase"
 	"github.com/go-playground/validator"
 )
 
-func ExtractBase64(request string) (raw, contentType, formatType string, decodedBytes []byte, err error) {
-
-	data := strings.Split(request, ",")
-	if len(data) != 2 {
-		err = fmt.Errorf("invalid base64 string format")
-		return
-	}
-
-	getContentType := strings.Split(data[0], ";")
-	getContentType = strings.Split(getContentType[0], ":")
-	if len(getContentType) != 2 {
-		err = fmt.Errorf("invalid base64 string format")
-		return
-	}
-
-	getFormatType := strings.Split(getContentType[1], "/")
-	if len(getFormatType) != 2 {
-		err = fmt.Errorf("invalid base64 string format")
-		return
-	}
-
-	decodedBytes, err = base64.StdEncoding.DecodeString(data[1])
-	if err != nil {
-		return
-	}
-
-	return data[1], getContentType[1], getFormatType[1], decodedBytes, nil
-}
-
 func FormatMessageValidator(err validator.FieldError) string {
 	param := err.Param()
 	message := err.Tag()
@@ -3,79 +3,12 @@ package utils
 import (
 	"aws-s3-bucket/config/interfaces/mocks"
 	"aws-s3-bucket/models/dto"
-	"encoding/base64"
-	"fmt"
 	"testing"
 
 	"github.com/go-playground/validator"
 	"github.com/stretchr/testify/require"
 )
 
-func Test_ExtractBase64(t *testing.T) {
-
-	type expected struct {
-		raw         string
-		contentType string
-		formatType  string
-		err         error
-	}
-	tests := []struct {
-		name     string
-		input    string
-		expected expected
-	}{
-		{
-			name:  "Valid Base64 String",
-			input: "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAUA",
-			expected: expected{
-				err:         nil,
-				formatType:  "png",
-				contentType: "image/png",
-				raw:         "iVBORw0KGgoAAAANSUhEUgAAAAUA",
-			},
-		},
-		{
-			name:  "Invalid Base64 String - get content type",
-			input: "dataimage/pngbase64,iVBORw0KGgoAAAANSUhEUgAAAAUA",
-			expected: expected{
-				err: fmt.Errorf("invalid base64 string format"),
-			},
-		},
-		{
-			name:  "Invalid Base64 String - get format type",
-			input: "data:imagepngbase64,iVBORw0KGgoAAAANSUhEUgAAAAUA",
-			expected: expected{
-				err: fmt.Errorf("invalid base64 string format"),
-			},
-		},
-		{
-			name:  "Empty String",
-			input: "",
-			expected: expected{
-				err: fmt.Errorf("invalid base64 string format"),
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			raw, contentType, formatType, decodedBytes, err := ExtractBase64(tt.input)
-			var decoded []byte
-			if raw != "" {
-				decoded, _ = base64.StdEncoding.DecodeString(raw)
-			}
-
-			require.Equal(t, tt.expected.err, err)
-			require.Equal(t, decodedBytes, decoded)
-			require.Equal(t, tt.expected.formatType, formatType)
-			require.Equal(t, tt.expected.contentType, contentType)
-			require.Equal(t, tt.expected.raw, raw)
-
-		})
-	}
-
-}
-
 func TestFormatMessageValidator(t *testing.T) {
 	tests := []struct {
 		name     string
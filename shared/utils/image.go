@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"aws-s3-bucket/shared/constant"
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/HugoSmits86/nativewebp"
+	xdraw "golang.org/x/image/draw"
+)
+
+// maxImagePixels refuse to decode huge image that would exhaust memory (decompression bomb)
+const maxImagePixels = 50_000_000
+
+// ImageTransform describe the rendition wanted, zero Width or Height is derived from the aspect ratio
+// and empty Format keep the source format
+type ImageTransform struct {
+	Width   int
+	Height  int
+	Fit     string
+	Format  string
+	Quality int
+}
+
+// TransformImage decode r, resize it according the transform and encode it again,
+// it return the encoded image with its content type
+func TransformImage(r io.Reader, transform ImageTransform) (data []byte, contentType string, err error) {
+	src, sourceFormat, err := DecodeImage(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	format := transform.Format
+	if format == "" {
+		format = sourceFormat
+	}
+
+	dst := ResizeImage(src, transform.Width, transform.Height, transform.Fit)
+	return EncodeImage(dst, format, transform.Quality)
+}
+
+// DecodeImage decode r after checking its dimension, it return the image with its format name
+func DecodeImage(r io.Reader) (img image.Image, format string, err error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, "", fmt.Errorf("image too large: %dx%d", config.Width, config.Height)
+	}
+
+	img, format, err = image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, format, nil
+}
+
+// EncodeImage encode img as jpeg, webp or png (the fallback for every other format)
+func EncodeImage(img image.Image, format string, quality int) (data []byte, contentType string, err error) {
+	var buf bytes.Buffer
+	switch format {
+	case constant.IMAGE_FORMAT_JPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case constant.IMAGE_FORMAT_WEBP:
+		// pure go encoder only support lossless webp, quality is ignored
+		err = nativewebp.Encode(&buf, img, nil)
+	default:
+		format = constant.IMAGE_FORMAT_PNG
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return buf.Bytes(), "image/" + format, nil
+}
+
+// ResizeImage scale src to width x height, contain keep the whole image inside the box,
+// cover fill the box and crop the overflow from the center and fill stretch the image
+func ResizeImage(src image.Image, width, height int, fit string) image.Image {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	if sw == 0 || sh == 0 || (width <= 0 && height <= 0) {
+		return src
+	}
+
+	switch {
+	case width <= 0:
+		width = scaleDimension(sw, height, sh)
+		fit = constant.IMAGE_FIT_FILL
+	case height <= 0:
+		height = scaleDimension(sh, width, sw)
+		fit = constant.IMAGE_FIT_FILL
+	}
+
+	crop := bounds
+	switch fit {
+	case constant.IMAGE_FIT_FILL:
+	case constant.IMAGE_FIT_COVER:
+		// keep the largest centered area that have the target aspect ratio
+		if sw*height > sh*width {
+			cw := scaleDimension(sh, width, height)
+			crop.Min.X += (sw - cw) / 2
+			crop.Max.X = crop.Min.X + cw
+		} else {
+			ch := scaleDimension(sw, height, width)
+			crop.Min.Y += (sh - ch) / 2
+			crop.Max.Y = crop.Min.Y + ch
+		}
+	default:
+		scale := math.Min(float64(width)/float64(sw), float64(height)/float64(sh))
+		width = max(1, int(math.Round(float64(sw)*scale)))
+		height = max(1, int(math.Round(float64(sh)*scale)))
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+// scaleDimension return value * numerator / denominator rounded, at least 1 pixel
+func scaleDimension(value, numerator, denominator int) int {
+	return max(1, int(math.Round(float64(value)*float64(numerator)/float64(denominator))))
+}
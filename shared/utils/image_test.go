@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	return img
+}
+
+func Test_ResizeImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		width, height  int
+		fit            string
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{name: "contain", width: 100, height: 100, fit: "contain", expectedWidth: 100, expectedHeight: 50},
+		{name: "cover", width: 100, height: 100, fit: "cover", expectedWidth: 100, expectedHeight: 100},
+		{name: "fill", width: 30, height: 70, fit: "fill", expectedWidth: 30, expectedHeight: 70},
+		{name: "width only", width: 50, expectedWidth: 50, expectedHeight: 25},
+		{name: "height only", height: 50, expectedWidth: 100, expectedHeight: 50},
+		{name: "original", expectedWidth: 200, expectedHeight: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds := ResizeImage(testImage(200, 100), tt.width, tt.height, tt.fit).Bounds()
+
+			require.Equal(t, tt.expectedWidth, bounds.Dx())
+			require.Equal(t, tt.expectedHeight, bounds.Dy())
+		})
+	}
+}
+
+func Test_TransformImage(t *testing.T) {
+	var source bytes.Buffer
+	require.NoError(t, png.Encode(&source, testImage(40, 20)))
+
+	tests := []struct {
+		name                string
+		format              string
+		expectedContentType string
+	}{
+		{name: "keep format", expectedContentType: "image/png"},
+		{name: "jpeg", format: "jpeg", expectedContentType: "image/jpeg"},
+		{name: "webp", format: "webp", expectedContentType: "image/webp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, contentType, err := TransformImage(bytes.NewReader(source.Bytes()), ImageTransform{Width: 10, Format: tt.format, Quality: 80})
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedContentType, contentType)
+
+			img, format, err := image.Decode(bytes.NewReader(data))
+			require.NoError(t, err)
+			require.Equal(t, contentType, "image/"+format)
+			require.Equal(t, image.Rect(0, 0, 10, 5), img.Bounds())
+		})
+	}
+
+	_, _, err := TransformImage(bytes.NewReader([]byte("not an image")), ImageTransform{Width: 10})
+	require.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+var ErrBlockedAddress = errors.New("address is not allowed")
+
+// sharedAddressSpace is carrier grade NAT range (RFC 6598), not routable on internet
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublicAddress tell whether addr is routable on internet, private, loopback, link-local,
+// multicast and unspecified address are not
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// RejectPrivateAddress is net.Dialer Control refusing to connect to non public address, it is checked
+// after DNS resolution so a public hostname resolving to a private address is refused too
+func RejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !IsPublicAddress(addr) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
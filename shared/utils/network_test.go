@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicAddress(t *testing.T) {
+	for _, public := range []string{"8.8.8.8", "2606:4700:4700::1111", "93.184.216.34"} {
+		require.True(t, IsPublicAddress(netip.MustParseAddr(public)), public)
+	}
+	for _, private := range []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1",
+		"0.0.0.0", "224.0.0.1", "::1", "fe80::1", "fc00::1", "::ffff:127.0.0.1", "::",
+	} {
+		require.False(t, IsPublicAddress(netip.MustParseAddr(private)), private)
+	}
+}
+
+func TestRejectPrivateAddress(t *testing.T) {
+	require.NoError(t, RejectPrivateAddress("tcp4", "8.8.8.8:443", nil))
+	require.ErrorIs(t, RejectPrivateAddress("tcp4", "127.0.0.1:80", nil), ErrBlockedAddress)
+	require.ErrorIs(t, RejectPrivateAddress("tcp6", "[::1]:80", nil), ErrBlockedAddress)
+	require.ErrorIs(t, RejectPrivateAddress("tcp4", "invalid", nil), ErrBlockedAddress)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func init() {
+	// pdfcpu write its configuration in user config dir by default, the server does not need it
+	api.DisableConfigDir()
+}
+
+type PDFInfo struct {
+	PageCount int
+	Title     string
+	Author    string
+	Encrypted bool
+	// PasswordProtected is true when a user password is needed to open the document,
+	// nothing else can be read in that case
+	PasswordProtected bool
+}
+
+// ReadPDFInfo read page count, title, author and encryption status of the PDF
+func ReadPDFInfo(rs io.ReadSeeker) (info PDFInfo, err error) {
+	result, err := api.PDFInfo(rs, "", nil, false, model.NewDefaultConfiguration())
+	if errors.Is(err, pdfcpu.ErrWrongPassword) {
+		return PDFInfo{Encrypted: true, PasswordProtected: true}, nil
+	}
+	if err != nil {
+		return info, fmt.Errorf("failed to read pdf: %w", err)
+	}
+
+	return PDFInfo{
+		PageCount: result.PageCount,
+		Title:     result.Title,
+		Author:    result.Author,
+		Encrypted: result.Encrypted,
+	}, nil
+}
+
+// PDFPreview return the largest image embedded in the first page, pure go cannot rasterize
+// vector content so it is meaningful for scanned document only. nil image when there is none
+func PDFPreview(rs io.ReadSeeker) (preview image.Image, err error) {
+	pages, err := api.ExtractImagesRaw(rs, []string{"1"}, model.NewDefaultConfiguration())
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract pdf image: %w", err)
+	}
+
+	largest := 0
+	for _, images := range pages {
+		for _, img := range images {
+			if img.IsImgMask {
+				continue
+			}
+			// dimension is checked before decoding as for uploaded image, a huge one is skipped
+			decoded, _, err := DecodeImage(img)
+			if err != nil {
+				// filter without go decoder (ex: JBIG2, CCITT)
+				continue
+			}
+			if area := decoded.Bounds().Dx() * decoded.Bounds().Dy(); area > largest {
+				preview, largest = decoded, area
+			}
+		}
+	}
+	return preview, nil
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"runtime"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/stretchr/testify/require"
+)
+
+// testPDF build two pages PDF with title and author, first page draw a jpeg when withImage
+func testPDF(t *testing.T, withImage bool) []byte {
+	page := "<</Type/Page/Parent 2 0 R/MediaBox[0 0 612 792]>>"
+	objects := []string{
+		"<</Type/Catalog/Pages 2 0 R>>",
+		"<</Type/Pages/Kids[3 0 R 5 0 R]/Count 2>>",
+		page,
+		"<</Title(Invoice)/Author(Jane)>>",
+		page,
+	}
+	if withImage {
+		var img bytes.Buffer
+		require.NoError(t, jpeg.Encode(&img, image.NewRGBA(image.Rect(0, 0, 16, 8)), nil))
+		content := "q 16 0 0 8 0 0 cm /Im1 Do Q"
+
+		objects[2] = "<</Type/Page/Parent 2 0 R/MediaBox[0 0 612 792]/Resources<</XObject<</Im1 6 0 R>>>>/Contents 7 0 R>>"
+		objects = append(objects,
+			fmt.Sprintf("<</Type/XObject/Subtype/Image/Width 16/Height 8/ColorSpace/DeviceRGB/BitsPerComponent 8/Filter/DCTDecode/Length %d>>\nstream\n%s\nendstream", img.Len(), img.String()),
+			fmt.Sprintf("<</Length %d>>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<</Root 1 0 R/Info 4 0 R/Size %d>>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func encryptPDF(t *testing.T, pdf []byte, userPassword string) []byte {
+	var out bytes.Buffer
+	require.NoError(t, api.Encrypt(bytes.NewReader(pdf), &out, model.NewAESConfiguration(userPassword, "owner", 256)))
+	return out.Bytes()
+}
+
+func Test_ReadPDFInfo(t *testing.T) {
+	pdf := testPDF(t, false)
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected PDFInfo
+	}{
+		{name: "plain", content: pdf, expected: PDFInfo{PageCount: 2, Title: "Invoice", Author: "Jane"}},
+		{name: "owner password", content: encryptPDF(t, pdf, ""), expected: PDFInfo{PageCount: 2, Title: "Invoice", Author: "Jane", Encrypted: true}},
+		{name: "user password", content: encryptPDF(t, pdf, "secret"), expected: PDFInfo{Encrypted: true, PasswordProtected: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ReadPDFInfo(bytes.NewReader(tt.content))
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, info)
+		})
+	}
+
+	_, err := ReadPDFInfo(bytes.NewReader([]byte(testContent)))
+	require.Error(t, err)
+}
+
+func Test_PDFPreview(t *testing.T) {
+	preview, err := PDFPreview(bytes.NewReader(testPDF(t, true)))
+
+	require.NoError(t, err)
+	require.NotNil(t, preview)
+	require.Equal(t, image.Rect(0, 0, 16, 8), preview.Bounds())
+
+	preview, err = PDFPreview(bytes.NewReader(testPDF(t, false)))
+
+	require.NoError(t, err)
+	require.Nil(t, preview)
+}
+
+func Test_PDFPreview_TooLarge(t *testing.T) {
+	pdf := testPDF(t, true)
+
+	// the jpeg header claim 8192x8192, over the pixels an image may have, the image is skipped without decoding
+	sof := bytes.Index(pdf, []byte{0xff, 0xc0})
+	require.NotEqual(t, -1, sof)
+	copy(pdf[sof+5:], []byte{0x20, 0x00, 0x20, 0x00})
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	preview, err := PDFPreview(bytes.NewReader(pdf))
+	runtime.ReadMemStats(&after)
+
+	require.NoError(t, err)
+	require.Nil(t, preview)
+	require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(64<<20))
+}
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+var (
+	jpegSignature = []byte{0xFF, 0xD8}
+	pngSignature  = []byte("\x89PNG\r\n\x1a\n")
+	exifHeader    = []byte("Exif\x00\x00")
+)
+
+const (
+	jpegMarkerAPP1  = 0xE1 // EXIF and XMP
+	jpegMarkerAPP13 = 0xED // Photoshop IRB holding IPTC
+	jpegMarkerSOS   = 0xDA
+	jpegMarkerEOI   = 0xD9
+
+	exifTagOrientation = 0x0112
+
+	// sanitizedJpegQuality is used only when the image must be re-encoded to apply the orientation
+	sanitizedJpegQuality = 95
+)
+
+// StripImageMetadata remove EXIF, XMP and IPTC from JPEG and PNG, other content is returned as is.
+// When EXIF orientation is not the default, the pixels are rotated first and the image is re-encoded
+// so it keep looking the same without the orientation tag
+func StripImageMetadata(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, jpegSignature):
+		return stripJpeg(data)
+	case bytes.HasPrefix(data, pngSignature):
+		return stripPng(data)
+	}
+	return data, nil
+}
+
+func stripJpeg(data []byte) ([]byte, error) {
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(jpegSignature)
+
+	orientation := 1
+	for i := len(jpegSignature); i < len(data); {
+		if data[i] != 0xFF || i+1 >= len(data) {
+			return nil, fmt.Errorf("invalid jpeg segment at offset %d", i)
+		}
+		marker := data[i+1]
+		if marker == 0xFF {
+			// fill byte
+			i++
+			continue
+		}
+		if marker == jpegMarkerSOS || marker == jpegMarkerEOI {
+			// entropy coded data does not hold metadata anymore
+			out.Write(data[i:])
+			break
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out.Write(data[i : i+2])
+			i += 2
+			continue
+		}
+
+		if i+4 > len(data) {
+			return nil, fmt.Errorf("invalid jpeg segment at offset %d", i)
+		}
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:i+4]))
+		if end > len(data) {
+			return nil, fmt.Errorf("invalid jpeg segment at offset %d", i)
+		}
+
+		switch marker {
+		case jpegMarkerAPP1:
+			if payload := data[i+4 : end]; bytes.HasPrefix(payload, exifHeader) {
+				orientation = exifOrientation(payload[len(exifHeader):])
+			}
+		case jpegMarkerAPP13:
+		default:
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+
+	if orientation == 1 {
+		return out.Bytes(), nil
+	}
+
+	// the pixel count is checked before decoding, as the rotation copy every pixel once more
+	img, _, err := DecodeImage(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode jpeg: %w", err)
+	}
+	var buf bytes.Buffer
+	if err = jpeg.Encode(&buf, ApplyOrientation(img, orientation), &jpeg.Options{Quality: sanitizedJpegQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func stripPng(data []byte) ([]byte, error) {
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+
+	orientation := 1
+	for i := len(pngSignature); i < len(data); {
+		if i+8 > len(data) {
+			return nil, fmt.Errorf("invalid png chunk at offset %d", i)
+		}
+		// length, type, data and crc
+		end := i + 12 + int(binary.BigEndian.Uint32(data[i:i+4]))
+		if end > len(data) || end < i {
+			return nil, fmt.Errorf("invalid png chunk at offset %d", i)
+		}
+
+		switch string(data[i+4 : i+8]) {
+		case "eXIf":
+			orientation = exifOrientation(data[i+8 : end-4])
+		case "tEXt", "zTXt", "iTXt", "tIME":
+			// textual chunks carry XMP and IPTC (ex: "XML:com.adobe.xmp", "Raw profile type iptc")
+		default:
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+
+	if orientation == 1 {
+		return out.Bytes(), nil
+	}
+
+	img, _, err := DecodeImage(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode png: %w", err)
+	}
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, ApplyOrientation(img, orientation)); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// exifOrientation read orientation tag from IFD0 of TIFF structured EXIF, 1 when missing or invalid
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) || offset < 8 {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for entry := offset + 2; entry+12 <= len(tiff) && count > 0; entry, count = entry+12, count-1 {
+		if order.Uint16(tiff[entry:]) != exifTagOrientation {
+			continue
+		}
+		if orientation := int(order.Uint16(tiff[entry+8:])); orientation >= 1 && orientation <= 8 {
+			return orientation
+		}
+		return 1
+	}
+	return 1
+}
+
+// ApplyOrientation return img as it should be displayed according the EXIF orientation (1-8)
+func ApplyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // flip horizontal
+				sx, sy = w-1-x, y
+			case 3: // rotate 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // flip vertical
+				sx, sy = x, h-1-y
+			case 5: // transpose
+				sx, sy = y, x
+			case 6: // rotate 90 clockwise
+				sx, sy = y, h-1-x
+			case 7: // transverse
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotate 90 counter clockwise
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
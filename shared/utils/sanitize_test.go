@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// tiffOrientation build little endian TIFF with only the orientation tag in IFD0
+func tiffOrientation(orientation uint16) []byte {
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x01\x00")
+	entry := make([]byte, 12)
+	binary.LittleEndian.PutUint16(entry[0:], exifTagOrientation)
+	binary.LittleEndian.PutUint16(entry[2:], 3)
+	binary.LittleEndian.PutUint32(entry[4:], 1)
+	binary.LittleEndian.PutUint16(entry[8:], orientation)
+	return append(append(tiff, entry...), 0, 0, 0, 0)
+}
+
+func jpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func pngChunk(kind string, payload []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(payload)))
+	chunk = append(append(chunk, kind...), payload...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func testJpeg(t *testing.T, orientation uint16) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(40, 20), nil))
+
+	metadata := append(jpegSegment(jpegMarkerAPP1, append(exifHeader, tiffOrientation(orientation)...)),
+		jpegSegment(jpegMarkerAPP1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta/>"))...)
+	metadata = append(metadata, jpegSegment(jpegMarkerAPP13, []byte("Photoshop 3.0\x00IPTC"))...)
+
+	return append(append([]byte{0xFF, 0xD8}, metadata...), buf.Bytes()[2:]...)
+}
+
+func Test_StripImageMetadata_Jpeg(t *testing.T) {
+	tests := []struct {
+		name          string
+		orientation   uint16
+		expectedBound image.Rectangle
+	}{
+		{name: "default orientation", orientation: 1, expectedBound: image.Rect(0, 0, 40, 20)},
+		{name: "rotated", orientation: 6, expectedBound: image.Rect(0, 0, 20, 40)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sanitized, err := StripImageMetadata(testJpeg(t, tt.orientation))
+
+			require.NoError(t, err)
+			require.NotContains(t, string(sanitized), "Exif")
+			require.NotContains(t, string(sanitized), "xmpmeta")
+			require.NotContains(t, string(sanitized), "Photoshop")
+
+			img, err := jpeg.Decode(bytes.NewReader(sanitized))
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedBound, img.Bounds())
+		})
+	}
+}
+
+func Test_StripImageMetadata_Png(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(40, 20)))
+	encoded := buf.Bytes()
+
+	// metadata chunk right after IHDR
+	ihdrEnd := len(pngSignature) + 25
+	source := append([]byte{}, encoded[:ihdrEnd]...)
+	source = append(source, pngChunk("eXIf", tiffOrientation(8))...)
+	source = append(source, pngChunk("iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00<x:xmpmeta/>"))...)
+	source = append(source, encoded[ihdrEnd:]...)
+
+	sanitized, err := StripImageMetadata(source)
+
+	require.NoError(t, err)
+	require.NotContains(t, string(sanitized), "eXIf")
+	require.NotContains(t, string(sanitized), "xmpmeta")
+
+	img, err := png.Decode(bytes.NewReader(sanitized))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+}
+
+func Test_StripImageMetadata_TooLarge(t *testing.T) {
+	// only the header declare the dimensions, decoding the pixels would allocate tens of gigabytes
+	ihdr := binary.BigEndian.AppendUint32(nil, 100_000)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, 100_000)
+	ihdr = append(ihdr, 8, 2, 0, 0, 0)
+
+	source := append([]byte{}, pngSignature...)
+	source = append(source, pngChunk("IHDR", ihdr)...)
+	source = append(source, pngChunk("eXIf", tiffOrientation(6))...)
+	source = append(source, pngChunk("IEND", nil)...)
+
+	_, err := StripImageMetadata(source)
+
+	require.ErrorContains(t, err, "image too large")
+}
+
+func Test_StripImageMetadata_Other(t *testing.T) {
+	sanitized, err := StripImageMetadata([]byte(testContent))
+
+	require.NoError(t, err)
+	require.Equal(t, []byte(testContent), sanitized)
+
+	_, err = StripImageMetadata([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF})
+	require.Error(t, err)
+}
+
+func Test_ApplyOrientation(t *testing.T) {
+	src := testImage(3, 2)
+
+	rotated := ApplyOrientation(src, 6)
+	require.Equal(t, image.Rect(0, 0, 2, 3), rotated.Bounds())
+	// top left of 90 clockwise rotation is bottom left of source
+	require.Equal(t, src.At(0, 1), rotated.At(0, 0))
+
+	flipped := ApplyOrientation(src, 2)
+	require.Equal(t, src.At(2, 0), flipped.At(0, 0))
+
+	require.Equal(t, src, ApplyOrientation(src, 1))
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// SignHMAC return the url safe base64 HMAC-SHA256 of the payload
+func SignHMAC(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyHMAC tell whether signature is SignHMAC of the payload, in constant time
+func VerifyHMAC(secret []byte, payload, signature string) bool {
+	expected, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SignHMAC(t *testing.T) {
+	secret := []byte("secret")
+	signature := SignHMAC(secret, "folder/example.png\n1700000000")
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		payload   string
+		signature string
+		expected  bool
+	}{
+		{name: "valid signature", secret: secret, payload: "folder/example.png\n1700000000", signature: signature, expected: true},
+		{name: "other payload", secret: secret, payload: "folder/other.png\n1700000000", signature: signature},
+		{name: "other secret", secret: []byte("rotated"), payload: "folder/example.png\n1700000000", signature: signature},
+		{name: "not base64", secret: secret, payload: "folder/example.png\n1700000000", signature: "%%%"},
+		{name: "empty signature", secret: secret, payload: "folder/example.png\n1700000000"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, VerifyHMAC(test.secret, test.payload, test.signature))
+		})
+	}
+}